@@ -1,20 +1,29 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/nabishec/restapi/internal/clients"
 	"github.com/nabishec/restapi/internal/config"
 	"github.com/nabishec/restapi/internal/http-server/handlers/deletion"
 	"github.com/nabishec/restapi/internal/http-server/handlers/get"
+	"github.com/nabishec/restapi/internal/http-server/handlers/graph"
 	"github.com/nabishec/restapi/internal/http-server/handlers/post"
 	"github.com/nabishec/restapi/internal/http-server/handlers/put"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
 	"github.com/nabishec/restapi/internal/http-server/middleware/logger"
+	"github.com/nabishec/restapi/internal/lib/apikey"
+	"github.com/nabishec/restapi/internal/lib/eventbus"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/storage/memory"
 	"github.com/nabishec/restapi/internal/storage/postgresql"
+	"github.com/nabishec/restapi/internal/worker"
 
 	_ "github.com/nabishec/restapi/docs"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -28,6 +37,10 @@ import (
 // @host localhost:8080
 // @BasePath /api/v1
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
 func main() {
 	// TODO: init config: cleanenv
 	cfg := config.MustLoad()
@@ -38,13 +51,37 @@ func main() {
 
 	log.Info("Programm started")
 
-	// TODO: init storage: postgresql
-	storage, err := postgresql.NewDatabase()
+	// TODO: init storage: postgresql, memory
+	storage, err := setupStorage(cfg.Storage)
 	if err != nil {
 		log.Error("failed to init storage", slerr.Err(err))
 		os.Exit(1)
 	}
-	// _ = storage
+	log.Info("storage initialized", slog.String("storage", cfg.Storage))
+
+	if cfg.Auth.Enabled && cfg.Auth.AdminKey != "" {
+		if err := apikey.CheckAdminKey(cfg.Auth.AdminKey); err != nil {
+			log.Error("refusing to start with a weak admin key", slerr.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	if !get.IsSearchLanguage(cfg.Search.Language) {
+		log.Error("unknown search language", slog.String("language", cfg.Search.Language))
+		os.Exit(1)
+	}
+
+	metadata, err := clients.NewRegistryFromConfig(cfg.Metadata, clients.New(cfg.ExternalAPI))
+	if err != nil {
+		log.Error("failed to init metadata providers", slerr.Err(err))
+		os.Exit(1)
+	}
+
+	go worker.Purge(context.Background(), log, storage, cfg.Trash.PurgeInterval, cfg.Trash.Retention)
+	go worker.Enrich(context.Background(), log, storage, metadata, cfg.Enrichment)
+	bus := eventbus.New()
+	go worker.ListenEvents(context.Background(), log, storage, bus)
+	go worker.Deliver(context.Background(), log, storage, clients.NewWebhookSender(cfg.Webhooks.Timeout), cfg.Webhooks)
 
 	router := chi.NewRouter()
 
@@ -54,11 +91,90 @@ func main() {
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
-	router.Post("/api/v1/songslibrary/song", post.SongPost(log, storage))
-	router.Get("/api/v1/songslibrary", get.SongsLibrary(log, storage))
-	router.Delete("/api/v1/songslibrary/song", deletion.SongDelete(log, storage))
-	router.Get("/api/v1/songslibrary/song", get.TextSongGet(log, storage))
-	router.Put("/api/v1/songslibrary/song", put.SongDetail(log, storage))
+	router.Group(func(api chi.Router) {
+		if cfg.Auth.Enabled {
+			api.Use(auth.New(log, storage, cfg.Auth.AdminKey))
+			api.Use(auth.Authorize(auth.MethodPolicy))
+		}
+
+		api.Post("/api/v1/songslibrary/song", post.SongPost(log, storage, cfg.Enrichment.MaxAttempts))
+		api.Post("/api/v1/songslibrary/import", post.SongImport(log, storage))
+		api.Get("/api/v1/songslibrary", get.SongsLibrary(log, storage, cfg.Search.SimilarityThreshold))
+		api.Get("/api/v1/songslibrary/export", get.SongsExport(log, storage, cfg.Search.SimilarityThreshold))
+		api.Get("/api/v1/songslibrary/search", get.SongSearch(log, storage, cfg.Search.Language))
+		api.Delete("/api/v1/songslibrary/song", deletion.SongDelete(log, storage))
+		api.Get("/api/v1/songslibrary/song", get.TextSongGet(log, storage))
+		api.Put("/api/v1/songslibrary/song", put.SongDetail(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/revisions", get.RevisionsGet(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/revisions/diff", get.RevisionDiff(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/revisions/{revision}", get.RevisionGet(log, storage))
+		api.Post("/api/v1/songslibrary/song/{id}/revisions/{revision}/restore", post.RevisionRestore(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/lyrics", get.LyricsGet(log, storage))
+		api.Put("/api/v1/songslibrary/song/{id}/lyrics", put.Lyrics(log, storage))
+		api.Delete("/api/v1/songslibrary/song/{id}/lyrics", deletion.LyricsDelete(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/chords", get.ChordSheetGet(log, storage))
+		api.Put("/api/v1/songslibrary/song/{id}/chords", put.ChordSheet(log, storage))
+		api.Delete("/api/v1/songslibrary/song/{id}/chords", deletion.ChordSheetDelete(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/translations", get.TranslationsGet(log, storage))
+		api.Get("/api/v1/songslibrary/song/{id}/translations/{lang}", get.TranslationGet(log, storage))
+		api.Put("/api/v1/songslibrary/song/{id}/translations/{lang}", put.Translation(log, storage))
+		api.Delete("/api/v1/songslibrary/song/{id}/translations/{lang}", deletion.TranslationDelete(log, storage))
+
+		api.Get("/api/v1/trash", get.TrashGet(log, storage))
+		api.Post("/api/v1/trash/{id}/restore", post.SongRestore(log, storage))
+
+		api.Get("/api/v1/jobs/{id}", get.JobGet(log, storage))
+		api.Get("/api/v1/events", get.EventsGet(log, storage, bus, cfg.Events.Heartbeat))
+
+		api.Get("/api/v1/groups", get.GroupsGet(log, storage))
+		api.Post("/api/v1/groups", post.GroupPost(log, storage))
+		api.Get("/api/v1/groups/{id}", get.GroupGet(log, storage))
+		api.Put("/api/v1/groups/{id}", put.GroupDetail(log, storage))
+		api.Delete("/api/v1/groups/{id}", deletion.GroupDelete(log, storage))
+		api.Get("/api/v1/groups/{id}/songs", get.GroupSongs(log, storage))
+
+		api.Post("/api/v1/albums", post.AlbumPost(log, storage))
+		api.Get("/api/v1/albums/{id}", get.AlbumGet(log, storage))
+		api.Get("/api/v1/albums/{id}/tracks", get.AlbumTracksGet(log, storage))
+		api.Post("/api/v1/albums/{id}/tracks", post.AlbumTrackPost(log, storage))
+		api.Put("/api/v1/albums/{id}/tracks", put.AlbumTracks(log, storage))
+
+		api.Post("/api/v1/playlists", post.PlaylistPost(log, storage))
+		api.Get("/api/v1/playlists/{id}", get.PlaylistGet(log, storage))
+		api.Delete("/api/v1/playlists/{id}", deletion.PlaylistDelete(log, storage))
+		api.Get("/api/v1/playlists/{id}/export", get.PlaylistExport(log, storage))
+		api.Post("/api/v1/playlists/{id}/entries", post.PlaylistEntryPost(log, storage))
+		api.Put("/api/v1/playlists/{id}/entries/{position}", put.PlaylistEntry(log, storage))
+		api.Delete("/api/v1/playlists/{id}/entries/{position}", deletion.PlaylistEntryDelete(log, storage))
+
+		// Without authentication there is no identity to check, as in the
+		// GraphQL mutations.
+		admin := api
+		if cfg.Auth.Enabled {
+			admin = api.With(auth.Require(apikey.RoleAdmin))
+		}
+		admin.Get("/api/v1/admin/keys", get.APIKeysGet(log, storage))
+		admin.Post("/api/v1/admin/keys", post.APIKeyPost(log, storage))
+		admin.Post("/api/v1/admin/keys/{id}/rotate", post.APIKeyRotate(log, storage))
+		admin.Delete("/api/v1/admin/keys/{id}", deletion.APIKeyDelete(log, storage))
+		admin.Get("/api/v1/audit", get.AuditGet(log, storage))
+		admin.Get("/api/v1/admin/webhooks", get.WebhooksGet(log, storage))
+		admin.Post("/api/v1/admin/webhooks", post.WebhookPost(log, storage))
+		admin.Delete("/api/v1/admin/webhooks/{id}", deletion.WebhookDelete(log, storage))
+		admin.Get("/api/v1/admin/webhooks/{id}/deliveries", get.WebhookDeliveriesGet(log, storage))
+		admin.Post("/api/v1/admin/webhooks/deliveries/{id}/replay", post.DeliveryReplay(log, storage))
+	})
+
+	router.Group(func(gql chi.Router) {
+		// Any key may query; the mutations check the role themselves, since
+		// the method of a GraphQL request says nothing about what it does.
+		if cfg.Auth.Enabled {
+			gql.Use(auth.New(log, storage, cfg.Auth.AdminKey))
+		}
+
+		gql.Post("/api/v1/graphql", graph.Handler(log, storage, cfg.Search.SimilarityThreshold, cfg.Enrichment.MaxAttempts))
+	})
+
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
 
 	log.Info("starting server", slog.String("address", cfg.Address))
@@ -78,6 +194,84 @@ func main() {
 	log.Error("server stoped")
 }
 
+type Storage interface {
+	post.SongAddingImp
+	get.SongLibraryImp
+	get.GettingTesxtSongImp
+	put.SongPutImp
+	deletion.SongDeletingImp
+	get.GroupsImp
+	get.GroupSongsImp
+	post.GroupAddingImp
+	put.GroupPutImp
+	deletion.GroupDeletingImp
+	get.AlbumImp
+	get.AlbumTracksImp
+	post.AlbumAddingImp
+	post.AlbumTrackAddingImp
+	put.AlbumTracksPutImp
+	get.PlaylistImp
+	post.PlaylistAddingImp
+	post.PlaylistEntryAddingImp
+	put.PlaylistEntryMovingImp
+	deletion.PlaylistDeletingImp
+	deletion.PlaylistEntryDeletingImp
+	auth.KeyResolverImp
+	get.APIKeysImp
+	post.APIKeyAddingImp
+	post.APIKeyRotatingImp
+	deletion.APIKeyRevokingImp
+	get.SongSearchImp
+	get.RevisionsImp
+	get.RevisionImp
+	post.RevisionRestoringImp
+	get.TrashImp
+	post.SongRestoringImp
+	worker.PurgerImp
+	worker.EnricherImp
+	get.JobImp
+	worker.DelivererImp
+	worker.EventListenerImp
+	get.EventsImp
+	get.WebhooksImp
+	post.WebhookAddingImp
+	deletion.WebhookDeletingImp
+	post.DeliveryReplayingImp
+	get.AuditImp
+	post.SongImportingImp
+	get.SongExportImp
+	get.LyricsImp
+	put.LyricsPutImp
+	deletion.LyricsDeletingImp
+	get.ChordSheetImp
+	put.ChordSheetPutImp
+	deletion.ChordSheetDeletingImp
+	get.TranslationsImp
+	put.TranslationPutImp
+	deletion.TranslationDeletingImp
+	graph.GraphImp
+}
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+)
+
+func setupStorage(kind string) (Storage, error) {
+	switch kind {
+	case storagePostgres:
+		db, err := postgresql.NewDatabase()
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case storageMemory:
+		return memory.NewStorage(), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage %q", kind)
+}
+
 const (
 	envLocal = "local"
 	envDev   = "dev"
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestBreaker runs a client whose breaker opens after two failures through
+// the states of the breaker; every call is made without retries.
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   reply
+		wantErr error
+		// afterProbe is the error of the call right after the probe.
+		afterProbe error
+	}{
+		{name: "probe succeeds", probe: detail, afterProbe: nil},
+		{name: "probe fails", probe: status(http.StatusInternalServerError), wantErr: ErrUnavailable, afterProbe: ErrCircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing := status(http.StatusInternalServerError)
+			server, calls := fakeAPI(t, failing, failing, tt.probe, detail)
+			cfg := testConfig(server.URL)
+			cfg.MaxRetries = 0
+			cfg.BreakerThreshold = 2
+			cfg.BreakerCooldown = 50 * time.Millisecond
+			client := New(cfg)
+
+			call := func(want error) {
+				t.Helper()
+				_, err := client.GetSongDetail(context.Background(), testSong)
+				if !errors.Is(err, want) || (want == nil && err != nil) {
+					t.Fatalf("GetSongDetail() error = %v, want %v", err, want)
+				}
+			}
+
+			call(ErrUnavailable)
+			call(ErrUnavailable)
+			// Open: the API isn't called.
+			call(ErrCircuitOpen)
+			if calls.Load() != 2 {
+				t.Fatalf("calls while open = %d, want 2", calls.Load())
+			}
+
+			time.Sleep(cfg.BreakerCooldown)
+			call(tt.wantErr)
+			if calls.Load() != 3 {
+				t.Fatalf("calls after cooldown = %d, want 3", calls.Load())
+			}
+			call(tt.afterProbe)
+		})
+	}
+}
+
+func TestBreakerSingleProbe(t *testing.T) {
+	b := newBreaker(1, 0)
+	b.failure()
+
+	if !b.allow() {
+		t.Fatal("probe not allowed after cooldown")
+	}
+	if b.allow() {
+		t.Error("second call allowed while probing")
+	}
+	b.release()
+	if !b.allow() {
+		t.Error("probe not allowed after a released one")
+	}
+}
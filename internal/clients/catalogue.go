@@ -0,0 +1,85 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// catalogueEntry is a song of a catalogue file.
+type catalogueEntry struct {
+	Song        string `json:"song" yaml:"song"`
+	Group       string `json:"group" yaml:"group"`
+	ReleaseDate string `json:"releaseDate" yaml:"releaseDate"`
+	Link        string `json:"link" yaml:"link"`
+	Text        string `json:"text" yaml:"text"`
+}
+
+// Catalogue supplies the details of the songs listed in a local JSON or YAML
+// file, read once when it's created.
+type Catalogue struct {
+	name  string
+	songs map[string]*catalogueEntry
+}
+
+func NewCatalogue(name string, path string) (*Catalogue, error) {
+	const op = "internal.clients.NewCatalogue()"
+
+	if name == "" {
+		name = ProviderCatalogue
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	var entries []*catalogueEntry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &entries)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &entries)
+	default:
+		err = fmt.Errorf("unknown catalogue format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	catalogue := &Catalogue{name: name, songs: make(map[string]*catalogueEntry, len(entries))}
+	for _, entry := range entries {
+		catalogue.songs[catalogueKey(entry.Group, entry.Song)] = entry
+	}
+	return catalogue, nil
+}
+
+func (c *Catalogue) Name() string {
+	return c.name
+}
+
+func (c *Catalogue) GetSongDetail(_ context.Context, song *model.Song) (*model.SongDetail, error) {
+	const op = "internal.clients.Catalogue.GetSongDetail()"
+
+	entry, ok := c.songs[catalogueKey(song.GroupName, song.SongName)]
+	if !ok {
+		return nil, fmt.Errorf("%s:%w", op, ErrNotFound)
+	}
+
+	return &model.SongDetail{
+		ReleaseDate: entry.ReleaseDate,
+		Link:        entry.Link,
+		Text:        entry.Text,
+	}, nil
+}
+
+// catalogueKey matches songs regardless of case and surrounding spaces.
+func catalogueKey(group string, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
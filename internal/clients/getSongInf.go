@@ -1,49 +1,216 @@
 package clients
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
-	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
 	"github.com/nabishec/restapi/internal/model"
 )
 
-func GetSongDetailsOfExternalApi(song *model.Song) (*model.SongDetail, error) {
-	const op = "external.serviceapi.GetSongDetailsOfExternalApi()"
+var (
+	ErrNotConfigured  = errors.New("external api url isn't set")
+	ErrNotFound       = errors.New("song not found in external api")
+	ErrBadRequest     = errors.New("incorrect request to external api")
+	ErrRateLimited    = errors.New("external api rate limit exceeded")
+	ErrUnavailable    = errors.New("external api unavailable")
+	ErrCircuitOpen    = errors.New("external api circuit open")
+	ErrBadResponse    = errors.New("incorrect response of external api")
+	ErrUnexpectedCode = errors.New("unexpected status of external api")
+)
+
+// maxResponseSize limits the size of a response of the external API.
+const maxResponseSize = 1 << 20
+
+// Client fetches song details from the external API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	cfg     config.ExternalAPI
+	breaker *breaker
+}
 
-	baseURL := os.Getenv("EXTERNAL_API_URL")
-	if baseURL == "" {
-		return nil, fmt.Errorf("%s:failed url(%s)", op, baseURL)
+func New(cfg config.ExternalAPI) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		http:    &http.Client{},
+		cfg:     cfg,
+		breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// retryableError is a failed attempt worth repeating; after is the delay the
+// API asked for with Retry-After, if any.
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func (c *Client) Name() string {
+	return ProviderExternalAPI
+}
+
+// GetSongDetail fetches the details of the song. Network errors, 5xx and 429
+// answers are retried with an exponential backoff, or after the delay given
+// by Retry-After; a song the API doesn't know is ErrNotFound.
+func (c *Client) GetSongDetail(ctx context.Context, song *model.Song) (*model.SongDetail, error) {
+	const op = "internal.clients.GetSongDetail()"
+
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("%s:%w", op, ErrNotConfigured)
 	}
-	baseURL += "/info"
 
 	reqParameters := url.Values{}
 	reqParameters.Add("group", song.GroupName)
 	reqParameters.Add("song", song.SongName)
+	reqURL := fmt.Sprintf("%s/info?%s", c.baseURL, reqParameters.Encode())
+
+	for attempt := 0; ; attempt++ {
+		songDetail, err := c.try(ctx, reqURL)
+		if err == nil {
+			return songDetail, nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		if attempt >= c.cfg.MaxRetries {
+			return nil, fmt.Errorf("%s:%w after %d attempts", op, err, attempt+1)
+		}
+
+		delay := c.backoff(attempt)
+		if retryable.after > 0 {
+			// Waiting longer than the longest backoff would hold the caller too long.
+			if retryable.after > c.cfg.RetryMaxDelay {
+				return nil, fmt.Errorf("%s:%w: retry after %s", op, err, retryable.after)
+			}
+			delay = retryable.after
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s:%w", op, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// try makes one attempt of the request within its own timeout.
+func (c *Client) try(ctx context.Context, reqURL string) (*model.SongDetail, error) {
+	if !c.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
 
-	reqURL := fmt.Sprintf("%s?%s", baseURL, reqParameters.Encode())
+	if c.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		c.breaker.release()
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.Get(reqURL)
+	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request to external service: %w", err)
+		// The caller gave up, the API may well be fine.
+		if ctx.Err() != nil && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			c.breaker.release()
+			return nil, err
+		}
+		c.breaker.failure()
+		return nil, &retryableError{err: fmt.Errorf("%w: %w", ErrUnavailable, err)}
 	}
+	defer resp.Body.Close()
 
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		c.breaker.failure()
+		return nil, &retryableError{err: fmt.Errorf("%w: %w", ErrUnavailable, err)}
+	}
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		c.breaker.failure()
+		return nil, &retryableError{
+			err:   fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode),
+			after: retryAfter(resp.Header.Get("Retry-After")),
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// The API is up, it only asks to slow down.
+		c.breaker.success()
+		return nil, &retryableError{
+			err:   ErrRateLimited,
+			after: retryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	c.breaker.success()
 	switch resp.StatusCode {
-	case http.StatusInternalServerError:
-		return nil, fmt.Errorf("external service error")
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNotFound
 	case http.StatusBadRequest:
-		return nil, fmt.Errorf("incorrect request to external api: request URL(%s)", reqURL)
+		return nil, fmt.Errorf("%w: request URL(%s)", ErrBadRequest, reqURL)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedCode, resp.StatusCode)
 	}
 
 	var songDetail model.SongDetail
-
-	err = json.NewDecoder(resp.Body).Decode(&songDetail)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding response of external service:%w", err)
+	if err := json.Unmarshal(body, &songDetail); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	if err := releasedate.Normalize(&songDetail); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
 	}
 
 	return &songDetail, nil
+}
+
+// backoff is the delay before the retry after the attempt: it doubles with
+// every attempt up to RetryMaxDelay, and a random half of it is jitter, so
+// that clients failed together don't retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.RetryBaseDelay << attempt
+	if delay <= 0 || delay > c.cfg.RetryMaxDelay {
+		delay = c.cfg.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
 
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date;
+// it is zero when the header is missing or malformed.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
 }
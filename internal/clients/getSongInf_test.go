@@ -0,0 +1,132 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var testSong = &model.Song{SongName: "Song1", GroupName: "Group1"}
+
+// reply answers a request to the fake external API.
+type reply func(w http.ResponseWriter)
+
+func status(code int, headers ...string) reply {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func detail(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"releaseDate": "16.07.2006", "link": "https://example.com", "text": "a"}`))
+}
+
+// fakeAPI serves the replies in turn, repeating the last one, and counts the
+// requests it got.
+func fakeAPI(t *testing.T, replies ...reply) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" || r.URL.Query().Get("song") != testSong.SongName ||
+			r.URL.Query().Get("group") != testSong.GroupName {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		call := int(calls.Add(1)) - 1
+		replies[min(call, len(replies)-1)](w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testConfig(url string) config.ExternalAPI {
+	return config.ExternalAPI{
+		URL:            url,
+		Timeout:        time.Second,
+		MaxRetries:     2,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  10 * time.Millisecond,
+	}
+}
+
+func TestGetSongDetail(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []reply
+		calls   int32
+		wantErr error
+	}{
+		{name: "found", replies: []reply{detail}, calls: 1},
+		{name: "5xx then found", replies: []reply{status(http.StatusServiceUnavailable), status(http.StatusBadGateway), detail}, calls: 3},
+		{name: "5xx on every attempt", replies: []reply{status(http.StatusInternalServerError)}, calls: 3, wantErr: ErrUnavailable},
+		{name: "not found", replies: []reply{status(http.StatusNotFound)}, calls: 1, wantErr: ErrNotFound},
+		{name: "bad request", replies: []reply{status(http.StatusBadRequest)}, calls: 1, wantErr: ErrBadRequest},
+		{name: "rate limited then found", replies: []reply{status(http.StatusTooManyRequests), detail}, calls: 2},
+		{name: "retry after too long", replies: []reply{status(http.StatusTooManyRequests, "Retry-After", "60")}, calls: 1, wantErr: ErrRateLimited},
+		{name: "malformed body", replies: []reply{func(w http.ResponseWriter) { w.Write([]byte(`{`)) }}, calls: 1, wantErr: ErrBadResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := fakeAPI(t, tt.replies...)
+
+			songDetail, err := New(testConfig(server.URL)).GetSongDetail(context.Background(), testSong)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("GetSongDetail() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && songDetail.ReleaseDate != "2006-07-16" {
+				t.Errorf("release date = %q, want 2006-07-16", songDetail.ReleaseDate)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.calls)
+			}
+		})
+	}
+}
+
+func TestGetSongDetailRetryAfter(t *testing.T) {
+	server, calls := fakeAPI(t, status(http.StatusTooManyRequests, "Retry-After", "1"), detail)
+	cfg := testConfig(server.URL)
+	cfg.RetryMaxDelay = 2 * time.Second
+
+	start := time.Now()
+	if _, err := New(cfg).GetSongDetail(context.Background(), testSong); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s of Retry-After", elapsed)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.value); got != tt.want {
+			t.Errorf("retryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
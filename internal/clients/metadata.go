@@ -0,0 +1,234 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var ErrUnknownProvider = errors.New("unknown metadata provider")
+
+const (
+	ProviderExternalAPI = "external_api"
+	ProviderCatalogue   = "catalogue"
+	ProviderStatic      = "static"
+)
+
+// fields are the fields of model.SongDetail merged from the providers.
+var fields = []string{model.FieldReleaseDate, model.FieldLink, model.FieldText}
+
+// MetadataProvider supplies the details of songs. A provider that doesn't
+// know the song answers ErrNotFound; it may leave fields it has no value for
+// empty.
+type MetadataProvider interface {
+	Name() string
+	GetSongDetail(ctx context.Context, song *model.Song) (*model.SongDetail, error)
+}
+
+type registered struct {
+	provider MetadataProvider
+	priority int
+	trust    map[string]float64
+}
+
+// Registry merges the details of songs from several providers field by
+// field: the most trusted non-empty value wins, and of equally trusted ones
+// the value of the provider with the higher priority.
+type Registry struct {
+	providers []*registered
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the provider. trust weighs its fields from 0, never taken, to
+// 1; the fields missing from it are trusted fully.
+func (r *Registry) Register(provider MetadataProvider, priority int, trust map[string]float64) {
+	weights := make(map[string]float64, len(fields))
+	for _, field := range fields {
+		weights[field] = 1
+		if weight, ok := trust[field]; ok {
+			weights[field] = weight
+		}
+	}
+
+	r.providers = append(r.providers, &registered{provider: provider, priority: priority, trust: weights})
+	sort.SliceStable(r.providers, func(i, j int) bool {
+		return r.providers[i].priority > r.providers[j].priority
+	})
+}
+
+// NewRegistryFromConfig registers the providers of the configuration; the
+// external API is the only one when none is configured.
+func NewRegistryFromConfig(cfg config.Metadata, externalAPI *Client) (*Registry, error) {
+	const op = "internal.clients.NewRegistryFromConfig()"
+
+	registry := NewRegistry()
+	if len(cfg.Providers) == 0 {
+		registry.Register(externalAPI, 0, nil)
+		return registry, nil
+	}
+
+	for _, providerCfg := range cfg.Providers {
+		var provider MetadataProvider
+		switch providerCfg.Kind {
+		case ProviderExternalAPI:
+			provider = externalAPI
+			if providerCfg.Name != "" {
+				provider = &renamed{MetadataProvider: externalAPI, name: providerCfg.Name}
+			}
+		case ProviderCatalogue:
+			catalogue, err := NewCatalogue(providerCfg.Name, providerCfg.Path)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%w", op, err)
+			}
+			provider = catalogue
+		case ProviderStatic:
+			provider = NewStatic(providerCfg.Name, &model.SongDetail{
+				ReleaseDate: providerCfg.ReleaseDate,
+				Link:        providerCfg.Link,
+				Text:        providerCfg.Text,
+			})
+		default:
+			return nil, fmt.Errorf("%s:%w: %q", op, ErrUnknownProvider, providerCfg.Kind)
+		}
+
+		for field := range providerCfg.Trust {
+			if !isField(field) {
+				return nil, fmt.Errorf("%s:unknown field %q in trust of %s", op, field, provider.Name())
+			}
+		}
+		registry.Register(provider, providerCfg.Priority, providerCfg.Trust)
+	}
+
+	return registry, nil
+}
+
+type providerAnswer struct {
+	songDetail *model.SongDetail
+	err        error
+}
+
+// GetSongDetail asks all the providers at once and merges their answers,
+// recording the provider of each field in Sources. While a field is missing,
+// the transient failure of a provider that might have supplied it is
+// returned, so that the song is asked for again later; a song without a
+// release date from any provider is ErrNotFound.
+func (r *Registry) GetSongDetail(ctx context.Context, song *model.Song) (*model.SongDetail, error) {
+	const op = "internal.clients.Registry.GetSongDetail()"
+
+	answers := make([]providerAnswer, len(r.providers))
+	var wg sync.WaitGroup
+	for i, reg := range r.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			songDetail, err := reg.provider.GetSongDetail(ctx, song)
+			if err == nil && songDetail.ReleaseDate != "" && releasedate.Normalize(songDetail) != nil {
+				// A date that can't be read is no date at all.
+				songDetail.ReleaseDate, songDetail.ReleaseDatePrecision = "", ""
+			}
+			answers[i] = providerAnswer{songDetail: songDetail, err: err}
+		}()
+	}
+	wg.Wait()
+
+	merged := &model.SongDetail{Sources: make(map[string]string)}
+	var failure error
+	for _, field := range fields {
+		var best *registered
+		var bestAnswer *model.SongDetail
+		for i, reg := range r.providers {
+			answer := answers[i]
+			if reg.trust[field] <= 0 {
+				continue
+			}
+			if answer.err != nil {
+				if !isPermanent(answer.err) && failure == nil {
+					failure = fmt.Errorf("%s: %w", reg.provider.Name(), answer.err)
+				}
+				continue
+			}
+			if fieldValue(answer.songDetail, field) == "" {
+				continue
+			}
+			// Providers are ordered by priority, so the first of equal trust wins.
+			if best == nil || reg.trust[field] > best.trust[field] {
+				best, bestAnswer = reg, answer.songDetail
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		setField(merged, field, fieldValue(bestAnswer, field))
+		if field == model.FieldReleaseDate {
+			merged.ReleaseDatePrecision = bestAnswer.ReleaseDatePrecision
+		}
+		merged.Sources[field] = best.provider.Name()
+	}
+
+	if len(merged.Sources) < len(fields) && failure != nil {
+		return nil, fmt.Errorf("%s:%w", op, failure)
+	}
+	if merged.ReleaseDate == "" {
+		return nil, fmt.Errorf("%s:%w", op, ErrNotFound)
+	}
+	return merged, nil
+}
+
+// isPermanent reports whether asking the provider again is of no use.
+func isPermanent(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrBadRequest) ||
+		errors.Is(err, ErrNotConfigured)
+}
+
+// renamed is a provider known by another name.
+type renamed struct {
+	MetadataProvider
+	name string
+}
+
+func (r *renamed) Name() string {
+	return r.name
+}
+
+func isField(field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldValue(songDetail *model.SongDetail, field string) string {
+	switch field {
+	case model.FieldReleaseDate:
+		return songDetail.ReleaseDate
+	case model.FieldLink:
+		return songDetail.Link
+	case model.FieldText:
+		return songDetail.Text
+	}
+	return ""
+}
+
+func setField(songDetail *model.SongDetail, field string, value string) {
+	switch field {
+	case model.FieldReleaseDate:
+		songDetail.ReleaseDate = value
+	case model.FieldLink:
+		songDetail.Link = value
+	case model.FieldText:
+		songDetail.Text = value
+	}
+}
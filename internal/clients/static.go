@@ -0,0 +1,37 @@
+package clients
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// Static supplies the same details for every song, the last resort when no
+// other provider knows it. {song} and {group} in the values are replaced by
+// the names of the song and its group.
+type Static struct {
+	name       string
+	songDetail *model.SongDetail
+}
+
+func NewStatic(name string, songDetail *model.SongDetail) *Static {
+	if name == "" {
+		name = ProviderStatic
+	}
+	return &Static{name: name, songDetail: songDetail}
+}
+
+func (s *Static) Name() string {
+	return s.name
+}
+
+func (s *Static) GetSongDetail(_ context.Context, song *model.Song) (*model.SongDetail, error) {
+	replacer := strings.NewReplacer("{song}", song.SongName, "{group}", song.GroupName)
+
+	return &model.SongDetail{
+		ReleaseDate: replacer.Replace(s.songDetail.ReleaseDate),
+		Link:        replacer.Replace(s.songDetail.Link),
+		Text:        replacer.Replace(s.songDetail.Text),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/webhook"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var ErrWebhookRejected = errors.New("webhook answered with an error")
+
+// WebhookSender posts events to webhooks.
+type WebhookSender struct {
+	http *http.Client
+}
+
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		http: &http.Client{
+			Timeout: timeout,
+			// A redirect is an answer of its own; following it would send the event elsewhere.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the event of the delivery signed with the secret of its
+// webhook and returns the status of the answer. Any status but 2xx is
+// ErrWebhookRejected.
+func (s *WebhookSender) Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	const op = "internal.clients.WebhookSender.Send()"
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "song-library-webhooks")
+	req.Header.Set(webhook.HeaderEvent, delivery.Event.Type)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(delivery.Secret, timestamp, body))
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+	defer resp.Body.Close()
+	// Draining the body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%s:%w: status %d", op, ErrWebhookRejected, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -11,8 +11,17 @@ import (
 )
 
 type Config struct {
-	Env        string `yaml:"env" env-default:"local" env-required:"true"`
-	HTTPServer `yaml:"http_server"`
+	Env         string `yaml:"env" env-default:"local" env-required:"true"`
+	Storage     string `yaml:"storage" env:"STORAGE" env-default:"postgres"` // postgres, memory
+	HTTPServer  `yaml:"http_server"`
+	ExternalAPI ExternalAPI `yaml:"external_api"`
+	Auth        Auth        `yaml:"auth"`
+	Search      Search      `yaml:"search"`
+	Trash       Trash       `yaml:"trash"`
+	Enrichment  Enrichment  `yaml:"enrichment"`
+	Metadata    Metadata    `yaml:"metadata"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Events      Events      `yaml:"events"`
 }
 
 type HTTPServer struct {
@@ -21,6 +30,93 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"iddle_timeout" env-default:"60s"`
 }
 
+// ExternalAPI configures the client of the API the song details are fetched
+// from. A failed request is retried MaxRetries times with an exponential
+// backoff from RetryBaseDelay up to RetryMaxDelay; after BreakerThreshold
+// failures in a row the client stops calling the API for BreakerCooldown.
+type ExternalAPI struct {
+	URL              string        `yaml:"url" env:"EXTERNAL_API_URL"`
+	Timeout          time.Duration `yaml:"timeout" env:"EXTERNAL_API_TIMEOUT" env-default:"2s"`
+	MaxRetries       int           `yaml:"max_retries" env:"EXTERNAL_API_MAX_RETRIES" env-default:"2"`
+	RetryBaseDelay   time.Duration `yaml:"retry_base_delay" env:"EXTERNAL_API_RETRY_BASE_DELAY" env-default:"100ms"`
+	RetryMaxDelay    time.Duration `yaml:"retry_max_delay" env:"EXTERNAL_API_RETRY_MAX_DELAY" env-default:"1s"`
+	BreakerThreshold int           `yaml:"breaker_threshold" env:"EXTERNAL_API_BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" env:"EXTERNAL_API_BREAKER_COOLDOWN" env-default:"30s"`
+}
+
+// Auth configures the API keys. AdminKey, when set, is accepted as an admin
+// key without a lookup so that the first keys can be created; it must be
+// injected at deploy time, and the service refuses to start with a short or
+// well-known one.
+type Auth struct {
+	Enabled  bool   `yaml:"enabled" env:"AUTH_ENABLED" env-default:"true"`
+	AdminKey string `yaml:"admin_key" env:"AUTH_ADMIN_KEY"`
+}
+
+type Search struct {
+	Language            string  `yaml:"language" env:"SEARCH_LANGUAGE" env-default:"russian"` // english, russian
+	SimilarityThreshold float64 `yaml:"similarity_threshold" env:"SEARCH_SIMILARITY_THRESHOLD" env-default:"0.3"`
+}
+
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// Enrichment configures the workers fetching the details of new songs from
+// the metadata providers. A failed job is retried after RetryBaseDelay,
+// doubled with every attempt up to RetryMaxDelay; a job not finished within
+// Lease is taken to be abandoned and is run again.
+type Enrichment struct {
+	Workers        int           `yaml:"workers" env:"ENRICHMENT_WORKERS" env-default:"2"`
+	PollInterval   time.Duration `yaml:"poll_interval" env:"ENRICHMENT_POLL_INTERVAL" env-default:"1s"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"ENRICHMENT_MAX_ATTEMPTS" env-default:"5"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"ENRICHMENT_RETRY_BASE_DELAY" env-default:"10s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"ENRICHMENT_RETRY_MAX_DELAY" env-default:"10m"`
+	Lease          time.Duration `yaml:"lease" env:"ENRICHMENT_LEASE" env-default:"1m"`
+}
+
+// Webhooks configures the workers delivering events to webhooks. A request
+// taking longer than Timeout or answered with other than 2xx is retried like
+// an enrichment job, until MaxAttempts are spent.
+type Webhooks struct {
+	Workers        int           `yaml:"workers" env:"WEBHOOKS_WORKERS" env-default:"2"`
+	PollInterval   time.Duration `yaml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL" env-default:"1s"`
+	Timeout        time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"5s"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"WEBHOOKS_RETRY_BASE_DELAY" env-default:"30s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"WEBHOOKS_RETRY_MAX_DELAY" env-default:"1h"`
+	Lease          time.Duration `yaml:"lease" env:"WEBHOOKS_LEASE" env-default:"1m"`
+}
+
+// Events configures the stream of library changes; an idle stream gets a
+// comment once every Heartbeat so that proxies keep it open.
+type Events struct {
+	Heartbeat time.Duration `yaml:"heartbeat" env:"EVENTS_HEARTBEAT" env-default:"15s"`
+}
+
+// Metadata lists the providers the details of new songs are merged from.
+// Without any, the details come from the external API alone.
+type Metadata struct {
+	Providers []MetadataProvider `yaml:"providers"`
+}
+
+// MetadataProvider configures a provider of Kind external_api, catalogue (a
+// JSON or YAML file at Path) or static (the fixed ReleaseDate, Link and Text,
+// where {song} and {group} stand for the song). Trust weighs the fields it
+// supplies, releaseDate, link and text, from 0 (never taken) to 1, the
+// default; among equally trusted values the one of the higher Priority wins.
+type MetadataProvider struct {
+	Kind        string             `yaml:"kind"`
+	Name        string             `yaml:"name"`
+	Priority    int                `yaml:"priority"`
+	Trust       map[string]float64 `yaml:"trust"`
+	Path        string             `yaml:"path"`
+	ReleaseDate string             `yaml:"release_date"`
+	Link        string             `yaml:"link"`
+	Text        string             `yaml:"text"`
+}
+
 func MustLoad() *Config {
 	err := godotenv.Load("configuration.env")
 	if err != nil {
@@ -41,5 +137,14 @@ func MustLoad() *Config {
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		log.Fatal("cannot read config:", err)
 	}
+
+	// The purge and the heartbeat run on tickers, which panic on an interval
+	// that isn't positive.
+	if cfg.Trash.PurgeInterval <= 0 {
+		log.Fatal("trash purge_interval must be positive:", cfg.Trash.PurgeInterval)
+	}
+	if cfg.Events.Heartbeat <= 0 {
+		log.Fatal("events heartbeat must be positive:", cfg.Events.Heartbeat)
+	}
 	return &cfg
 }
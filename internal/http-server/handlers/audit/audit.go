@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type RecorderImp interface {
+	AddAuditRecord(record *model.AuditRecord) error
+}
+
+// SongState is the audited state of a song as a whole.
+type SongState struct {
+	Song   *model.Song       `json:"song"`
+	Detail *model.SongDetail `json:"detail,omitempty"`
+}
+
+// Record stores an audit record of a successful change of the song made by
+// the request. A failure is logged and doesn't fail the request, since the
+// change itself is already done.
+func Record(log *slog.Logger, recorder RecorderImp, r *http.Request, operation string, song *model.Song, before interface{}, after interface{}) {
+	record := &model.AuditRecord{
+		Operation:  operation,
+		SongName:   song.SongName,
+		GroupName:  song.GroupName,
+		RequestID:  middleware.GetReqID(r.Context()),
+		RemoteAddr: r.RemoteAddr,
+		Actor:      auth.Name(r.Context()),
+		Before:     marshal(log, before),
+		After:      marshal(log, after),
+	}
+
+	if err := recorder.AddAuditRecord(record); err != nil {
+		log.Error("failed to record audit", slog.String("operation", operation), slerr.Err(err))
+	}
+}
+
+func marshal(log *slog.Logger, value interface{}) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Error("failed to marshal audit value", slerr.Err(err))
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
@@ -6,8 +6,11 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/nabishec/restapi/internal/lib/langtag"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
 	"github.com/nabishec/restapi/internal/model"
 )
@@ -41,3 +44,66 @@ func SongDecoderValJSON(log *slog.Logger, r *http.Request) (*model.Song, *string
 
 	return &song, nil
 }
+
+func GroupDecoderValJSON(log *slog.Logger, r *http.Request) (*model.Group, *string) {
+	group, errStr := RequestDecoderValJSON[model.Group](log, r)
+	if errStr != nil {
+		return nil, errStr
+	}
+	log.Info("request body decoded", slog.String("group: ", group.Name))
+
+	return group, nil
+}
+
+func RequestDecoderValJSON[T any](log *slog.Logger, r *http.Request) (*T, *string) {
+	var req T
+
+	defer r.Body.Close()
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			log.Error("request body is empty")
+		} else {
+			log.Error("failed to decode request body", slerr.Err(err))
+		}
+		reply := "bad request"
+		return nil, &reply
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validatorErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid types", slerr.Err(err))
+
+		reply := validatorErr.Error()
+		return nil, &reply
+	}
+
+	return &req, nil
+}
+
+func IdURLParam(log *slog.Logger, r *http.Request, key string) (int64, *string) {
+	id, err := strconv.ParseInt(chi.URLParam(r, key), 10, 64)
+	if err != nil {
+		log.Error("failed converting of "+key+":", slerr.Err(err))
+
+		reply := "incorrect value of " + key
+		return 0, &reply
+	}
+
+	return id, nil
+}
+
+// LanguageURLParam reads a BCP-47 language tag from the URL in its canonical form.
+func LanguageURLParam(log *slog.Logger, r *http.Request, key string) (string, *string) {
+	language, err := langtag.Canonical(chi.URLParam(r, key))
+	if err != nil {
+		log.Error("failed converting of "+key+":", slerr.Err(err))
+
+		reply := "incorrect value of " + key
+		return "", &reply
+	}
+
+	return language, nil
+}
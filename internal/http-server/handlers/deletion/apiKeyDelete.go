@@ -0,0 +1,66 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type APIKeyRevokingImp interface {
+	RevokeAPIKey(id int64) error
+}
+
+// @Summary      Revoke API Key
+// @Tags         admin
+// @Description  Revoke an API key; it stays listed but can no longer be used.
+// @Produce      json
+// @Param        id      path      int64   true  "Key ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Key doesn't exist or already revoked"
+// @Failure      500     {object}  model.Response  "Failed to revoke key"
+// @Security     ApiKeyAuth
+// @Router       /admin/keys/{id} [delete]
+func APIKeyDelete(log *slog.Logger, keyRevoking APIKeyRevokingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.apiKeyDelete.APIKeyDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := keyRevoking.RevokeAPIKey(id)
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.Info("api key doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("api key doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed revoke api key", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to revoke api key"))
+			return
+		}
+
+		log.Info("api key revoked", slog.Int64("id", id))
+		render.JSON(w, r, model.OK())
+	}
+}
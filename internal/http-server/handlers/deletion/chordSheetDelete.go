@@ -0,0 +1,73 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type ChordSheetDeletingImp interface {
+	DeleteChordSheet(songId int64) error
+}
+
+// @Summary      Delete Chord Sheet
+// @Tags         songslibrary/chords
+// @Description  Remove the chord sheet of a song; the text of its detail is kept.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song or chord sheet not found"
+// @Failure      500     {object}  model.Response  "Failed deletion of chord sheet"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/chords [delete]
+func ChordSheetDelete(log *slog.Logger, chordSheetDeleting ChordSheetDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.chordSheetDelete.ChordSheetDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := chordSheetDeleting.DeleteChordSheet(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrChordSheetNotFound) {
+			log.Info("song has no chord sheet", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song has no chord sheet"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete chord sheet", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of chord sheet"))
+			return
+		}
+
+		log.Info("chord sheet deleted", slog.Int64("id", songId))
+		render.JSON(w, r, model.OK())
+	}
+}
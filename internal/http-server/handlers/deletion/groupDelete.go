@@ -0,0 +1,81 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type GroupDeletingImp interface {
+	DeleteGroup(id int64) error
+}
+
+// @Summary      Delete a Group
+// @Tags         groups
+// @Description  Delete a group that has no songs left in the library or in the trash.
+// @Produce      json
+// @Param        id      path      int64   true  "Group ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Group doesn't exist"
+// @Failure      409     {object}  model.Response  "Group still has songs, or songs in the trash"
+// @Failure      500     {object}  model.Response  "Failed deletion of group"
+// @Security     ApiKeyAuth
+// @Router       /groups/{id} [delete]
+func GroupDelete(log *slog.Logger, groupDeleting GroupDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.groupDelete.GroupDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := groupDeleting.DeleteGroup(id)
+		if errors.Is(err, storage.ErrGroupNotFound) {
+			log.Info("group doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("group doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrGroupHasSongs) {
+			log.Info("group still has songs", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("group still has songs"))
+			return
+		}
+		if errors.Is(err, storage.ErrGroupHasTrashedSongs) {
+			log.Info("group still has songs in the trash", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("group still has songs in the trash"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete group", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of group"))
+			return
+		}
+
+		log.Info("group deleted", slog.Int64("id", id))
+		render.JSON(w, r, model.OK())
+	}
+}
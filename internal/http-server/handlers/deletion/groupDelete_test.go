@@ -0,0 +1,35 @@
+package deletion
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestGroupDelete(t *testing.T) {
+	song := &model.Song{SongName: "Song1", GroupName: "Group1"}
+	songStorage := handlertest.Storage(t, song)
+	handler := GroupDelete(handlertest.Logger(), songStorage)
+
+	message := func(want string) func(t *testing.T, resp *model.Response) {
+		return func(t *testing.T, resp *model.Response) {
+			if resp.Error != want {
+				t.Errorf("error = %q, want %q", resp.Error, want)
+			}
+		}
+	}
+	handlertest.Run(t, http.MethodDelete, "/groups/{id}", handler, []handlertest.Case{
+		{Name: "group with songs", Target: "/groups/1", Status: http.StatusConflict, Check: message("group still has songs")},
+		{Name: "unknown group", Target: "/groups/9", Status: http.StatusNotFound},
+	})
+
+	if err := songStorage.DeleteSong(song, handlertest.Logger()); err != nil {
+		t.Fatal(err)
+	}
+	handlertest.Run(t, http.MethodDelete, "/groups/{id}", handler, []handlertest.Case{
+		{Name: "group with songs in the trash", Target: "/groups/1", Status: http.StatusConflict,
+			Check: message("group still has songs in the trash")},
+	})
+}
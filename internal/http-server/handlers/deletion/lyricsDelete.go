@@ -0,0 +1,73 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type LyricsDeletingImp interface {
+	DeleteLyrics(songId int64) error
+}
+
+// @Summary      Delete Song Lyrics
+// @Tags         songslibrary/lyrics
+// @Description  Remove the time-synced lyrics of a song; the song text falls back to the plain text of its details.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song or lyrics not found"
+// @Failure      500     {object}  model.Response  "Failed deletion of lyrics"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/lyrics [delete]
+func LyricsDelete(log *slog.Logger, lyricsDeleting LyricsDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.lyricsDelete.LyricsDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := lyricsDeleting.DeleteLyrics(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrLyricsNotFound) {
+			log.Info("song has no lyrics", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song has no lyrics"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete lyrics", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of lyrics"))
+			return
+		}
+
+		log.Info("lyrics deleted", slog.Int64("id", songId))
+		render.JSON(w, r, model.OK())
+	}
+}
@@ -0,0 +1,133 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type PlaylistDeletingImp interface {
+	DeletePlaylist(id int64) error
+}
+
+type PlaylistEntryDeletingImp interface {
+	DeletePlaylistEntry(playlistId int64, position int) error
+}
+
+// @Summary      Delete a Playlist
+// @Tags         playlists
+// @Description  Delete a playlist with all its entries.
+// @Produce      json
+// @Param        id      path      int64   true  "Playlist ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Playlist doesn't exist"
+// @Failure      500     {object}  model.Response  "Failed deletion of playlist"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id} [delete]
+func PlaylistDelete(log *slog.Logger, playlistDeleting PlaylistDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.playlistDelete.PlaylistDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := playlistDeleting.DeletePlaylist(id)
+		if errors.Is(err, storage.ErrPlaylistNotFound) {
+			log.Info("playlist doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete playlist", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of playlist"))
+			return
+		}
+
+		log.Info("playlist deleted", slog.Int64("id", id))
+		render.JSON(w, r, model.OK())
+	}
+}
+
+// @Summary      Delete a Playlist Entry
+// @Tags         playlists
+// @Description  Remove the entry at the given position; later entries move up.
+// @Produce      json
+// @Param        id        path      int64   true  "Playlist ID"
+// @Param        position  path      int     true  "Position of the entry"
+// @Success      200       {object}  model.Response  "OK"
+// @Failure      400       {object}  model.Response  "Bad request"
+// @Failure      404       {object}  model.Response  "Playlist or entry doesn't exist"
+// @Failure      500       {object}  model.Response  "Failed deletion of entry"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id}/entries/{position} [delete]
+func PlaylistEntryDelete(log *slog.Logger, entryDeleting PlaylistEntryDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.playlistDelete.PlaylistEntryDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		playlistId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		position, errStr := decoder.IdURLParam(log, r, "position")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := entryDeleting.DeletePlaylistEntry(playlistId, int(position))
+		if errors.Is(err, storage.ErrPlaylistNotFound) {
+			log.Info("playlist doesn't exist", slog.Int64("id", playlistId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrEntryNotFound) {
+			log.Info("playlist entry doesn't exist", slog.Int64("position", position))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist entry doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete playlist entry", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of playlist entry"))
+			return
+		}
+
+		log.Info("playlist entry deleted", slog.Int64("playlist", playlistId))
+		render.JSON(w, r, model.OK())
+	}
+}
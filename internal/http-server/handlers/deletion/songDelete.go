@@ -7,6 +7,8 @@ import (
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
 	"github.com/nabishec/restapi/internal/model"
 	"github.com/nabishec/restapi/internal/storage"
@@ -14,11 +16,14 @@ import (
 
 type SongDeletingImp interface {
 	DeleteSong(song *model.Song, log *slog.Logger) error
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	audit.RecorderImp
+	event.PublisherImp
 }
 
 // @Summary      Delete a Song
 // @Tags         songdelete/song
-// @Description  Delete a song from the library by song name and group name.
+// @Description  Move a song to the trash by song name and group name; it can be restored until the trash is purged.
 // @Produce      json
 // @Param        song    query     string  true  "Name of the song"   Example: "Song1"
 // @Param        group   query     string  true  "Name of the group"  Example: "Group1"
@@ -26,6 +31,7 @@ type SongDeletingImp interface {
 // @Failure      400     {object}  model.Response    "Bad request"
 // @Failure      404     {object}  model.Response    "Song doesn't exist"
 // @Failure      500     {object}  model.Response    "Failed deletion of song"
+// @Security     ApiKeyAuth
 // @Router       /songslibrary/song [delete]
 func SongDelete(log *slog.Logger, songDeleting SongDeletingImp) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +57,9 @@ func SongDelete(log *slog.Logger, songDeleting SongDeletingImp) http.HandlerFunc
 			GroupName: groupName,
 		}
 
+		// The detail is only needed for the audit record, so a missing one is fine.
+		songDetail, _ := songDeleting.GetSongDetail(song)
+
 		err := songDeleting.DeleteSong(song, log)
 		if errors.Is(err, storage.ErrSongNotFound) {
 			log.Info("song doesn't exist", slog.String("song:", song.SongName+
@@ -68,6 +77,10 @@ func SongDelete(log *slog.Logger, songDeleting SongDeletingImp) http.HandlerFunc
 			return
 		}
 
+		audit.Record(log, songDeleting, r, model.AuditDeleteSong, song,
+			&audit.SongState{Song: song, Detail: songDetail}, nil)
+		event.Publish(log, songDeleting, r, model.EventSongDeleted, song, songDetail)
+
 		log.Info("song deleted", slog.String("song:", song.SongName+":"+song.GroupName))
 		render.JSON(w, r, model.OK())
 	}
@@ -0,0 +1,29 @@
+package deletion
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func TestSongDelete(t *testing.T) {
+	song := &model.Song{SongName: "Song1", GroupName: "Group1"}
+	songStorage := handlertest.Storage(t, song)
+	handler := SongDelete(handlertest.Logger(), songStorage)
+
+	deleted := func(t *testing.T, _ *model.Response) {
+		if _, err := songStorage.GetSongDetail(song); !errors.Is(err, storage.ErrSongNotFound) {
+			t.Errorf("deleted song is still found: %v", err)
+		}
+	}
+	handlertest.Run(t, http.MethodDelete, "/song", handler, []handlertest.Case{
+		{Name: "existing song", Target: "/song?song=Song1&group=Group1", Status: http.StatusOK, Check: deleted},
+		{Name: "deleted song", Target: "/song?song=Song1&group=Group1", Status: http.StatusNotFound},
+		{Name: "unknown song", Target: "/song?song=Song2&group=Group1", Status: http.StatusNotFound},
+		{Name: "no group", Target: "/song?song=Song1", Status: http.StatusBadRequest},
+	})
+}
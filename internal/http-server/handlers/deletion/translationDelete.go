@@ -0,0 +1,80 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type TranslationDeletingImp interface {
+	DeleteTranslation(songId int64, language string) error
+}
+
+// @Summary      Delete Song Translation
+// @Tags         songslibrary/translations
+// @Description  Remove the translation of a song to a language given by its BCP-47 tag.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        lang    path      string  true  "BCP-47 language tag"  Example: "ru"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song or translation not found"
+// @Failure      500     {object}  model.Response  "Failed deletion of translation"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/translations/{lang} [delete]
+func TranslationDelete(log *slog.Logger, translationDeleting TranslationDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.translationDelete.TranslationDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		language, errStr := decoder.LanguageURLParam(log, r, "lang")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := translationDeleting.DeleteTranslation(songId, language)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrTranslationNotFound) {
+			log.Info("translation doesn't exist", slog.Int64("id", songId), slog.String("language", language))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("translation doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete translation", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of translation"))
+			return
+		}
+
+		log.Info("translation deleted", slog.Int64("id", songId), slog.String("language", language))
+		render.JSON(w, r, model.OK())
+	}
+}
@@ -0,0 +1,66 @@
+package deletion
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type WebhookDeletingImp interface {
+	DeleteWebhook(id int64) error
+}
+
+// @Summary      Delete Webhook
+// @Tags         admin
+// @Description  Remove a webhook subscription together with its deliveries; queued ones are never sent.
+// @Produce      json
+// @Param        id      path      int64            true  "Webhook ID"
+// @Success      200     {object}  model.Response   "OK"
+// @Failure      400     {object}  model.Response   "Bad request"
+// @Failure      404     {object}  model.Response   "Webhook not found"
+// @Failure      500     {object}  model.Response   "Failed deletion of webhook"
+// @Security     ApiKeyAuth
+// @Router       /admin/webhooks/{id} [delete]
+func WebhookDelete(log *slog.Logger, webhookDeleting WebhookDeletingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.delete.webhookDelete.WebhookDelete()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := webhookDeleting.DeleteWebhook(id)
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			log.Info("webhook doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("webhook doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed delete webhook", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed deletion of webhook"))
+			return
+		}
+
+		log.Info("webhook deleted", slog.Int64("id", id))
+		render.JSON(w, r, model.OK())
+	}
+}
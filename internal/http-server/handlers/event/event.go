@@ -0,0 +1,58 @@
+package event
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type PublisherImp interface {
+	AddEvent(event *model.Event) error
+}
+
+// SongPublisherImp is a PublisherImp that also finds a song by its id.
+type SongPublisherImp interface {
+	PublisherImp
+	GetSong(id int64) (*model.Song, error)
+}
+
+// Publish adds the event of a successful change of the song made by the
+// request to the event log, from where it is delivered to the webhooks. A
+// failure is logged and doesn't fail the request, like an audit record.
+func Publish(log *slog.Logger, publisher PublisherImp, r *http.Request, eventType string, song *model.Song, data interface{}) {
+	event := &model.Event{
+		Type:      eventType,
+		Song:      &model.Song{ID: song.ID, SongName: song.SongName, GroupName: song.GroupName},
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			log.Error("failed to marshal event data", slerr.Err(err))
+		}
+		if string(raw) != "null" {
+			event.Data = raw
+		}
+	}
+
+	if err := publisher.AddEvent(event); err != nil {
+		log.Error("failed to publish event", slog.String("type", eventType), slerr.Err(err))
+	}
+}
+
+// PublishById is Publish for the handlers that change a song by its id; the
+// names of the song are looked up for the event.
+func PublishById(log *slog.Logger, publisher SongPublisherImp, r *http.Request, eventType string, songId int64, data interface{}) {
+	song, err := publisher.GetSong(songId)
+	if err != nil {
+		log.Error("failed to publish event", slog.String("type", eventType), slerr.Err(err))
+		return
+	}
+
+	Publish(log, publisher, r, eventType, song, data)
+}
@@ -0,0 +1,152 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type AlbumImp interface {
+	GetAlbum(id int64) (*model.Album, error)
+}
+
+type AlbumTracksImp interface {
+	GetAlbumTracks(albumId int64, limit int64, offset int64) ([]*model.Song, error)
+	CountAlbumTracks(albumId int64) (int64, error)
+}
+
+// @Summary      Get Album
+// @Tags         albums
+// @Description  Retrieve an album by its ID.
+// @Produce      json
+// @Param        id      path      int64   true  "Album ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Album not found"
+// @Failure      500     {object}  model.Response  "Failed to get album"
+// @Security     ApiKeyAuth
+// @Router       /albums/{id} [get]
+func AlbumGet(log *slog.Logger, albumImp AlbumImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.albums.AlbumGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		album, err := albumImp.GetAlbum(id)
+		if errors.Is(err, storage.ErrAlbumNotFound) {
+			log.Info("album doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("album doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed get album", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get album"))
+			return
+		}
+
+		log.Info("album getted", slog.Int64("id", id))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Album:  album,
+		})
+	}
+}
+
+// @Summary      Get Album Tracks
+// @Tags         albums
+// @Description  Retrieve the album tracklist in order with pagination options.
+// @Produce      json
+// @Param        id      path      int64   true  "Album ID"
+// @Param        first   query     int64   false "Number of items to return"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Album not found"
+// @Failure      500     {object}  model.Response  "Failed to get album tracks"
+// @Security     ApiKeyAuth
+// @Router       /albums/{id}/tracks [get]
+func AlbumTracksGet(log *slog.Logger, albumTracksImp AlbumTracksImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.albums.AlbumTracksGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		tracksNumber, err := albumTracksImp.CountAlbumTracks(id)
+		if errors.Is(err, storage.ErrAlbumNotFound) {
+			log.Info("album doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("album doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("can't count album tracks", slerr.Err(err))
+			tracksNumber = 0
+		}
+
+		tracks, err := albumTracksImp.GetAlbumTracks(id, first, after)
+		if err != nil {
+			log.Error("failed get album tracks", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get album tracks"))
+			return
+		}
+
+		edges := make([]*model.SongEdge, 0, len(tracks))
+		for i, val := range tracks {
+			edges = append(edges, &model.SongEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("album tracks getted", slog.Int64("id", id))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			AlbumTracks: &model.SongsConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), tracksNumber),
+			},
+		})
+	}
+}
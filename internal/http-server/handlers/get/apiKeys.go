@@ -0,0 +1,49 @@
+package get
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type APIKeysImp interface {
+	GetAPIKeys() ([]*model.APIKey, error)
+}
+
+// @Summary      Get API Keys
+// @Tags         admin
+// @Description  List API keys, including revoked ones. Secrets are never returned.
+// @Produce      json
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      500     {object}  model.Response  "Failed to get keys"
+// @Security     ApiKeyAuth
+// @Router       /admin/keys [get]
+func APIKeysGet(log *slog.Logger, keysImp APIKeysImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.apiKeys.APIKeysGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		keys, err := keysImp.GetAPIKeys()
+		if err != nil {
+			log.Error("failed get api keys", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get api keys"))
+			return
+		}
+
+		log.Info("api keys getted")
+		render.JSON(w, r, model.Response{
+			Status:  "OK",
+			APIKeys: keys,
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package get
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type AuditImp interface {
+	GetAuditRecords(filter *model.AuditFilter, limit int64, offset int64) ([]*model.AuditRecord, error)
+	CountAuditRecords(filter *model.AuditFilter) (int64, error)
+}
+
+var auditOperations = map[string]bool{
+	model.AuditAddSong:         true,
+	model.AuditAddSongDetail:   true,
+	model.AuditPutSongDetail:   true,
+	model.AuditDeleteSong:      true,
+	model.AuditRestoreSong:     true,
+	model.AuditRestoreRevision: true,
+	model.AuditPutChordSheet:   true,
+}
+
+// @Summary      Get Audit Log
+// @Tags         admin
+// @Description  Retrieve the audit records of song changes, newest first.
+// @Produce      json
+// @Param        song       query     string  false "Name of the song"   Example: "Song1"
+// @Param        group      query     string  false "Name of the group"  Example: "Group1"
+// @Param        operation  query     string  false "AddSong, AddSongDetail, PutSongDetail, DeleteSong, RestoreSong, RestoreRevision or PutChordSheet"  Example: "DeleteSong"
+// @Param        from       query     string  false "Earliest time, RFC 3339 or a date"  Example: "2024-01-01"
+// @Param        to         query     string  false "Latest time, RFC 3339 or a date (the whole day)"  Example: "2024-01-31T12:00:00Z"
+// @Param        first      query     int64   false "Number of items to return"  Example: 10
+// @Param        after      query     string  false "Cursor after which to return items"
+// @Success      200        {object}  model.Response  "OK"
+// @Failure      400        {object}  model.Response  "Bad request"
+// @Failure      500        {object}  model.Response  "Failed to get audit log"
+// @Security     ApiKeyAuth
+// @Router       /audit [get]
+func AuditGet(log *slog.Logger, auditImp AuditImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.audit.AuditGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter := &model.AuditFilter{
+			SongName:  r.URL.Query().Get("song"),
+			GroupName: r.URL.Query().Get("group"),
+			Operation: r.URL.Query().Get("operation"),
+		}
+		if filter.Operation != "" && !auditOperations[filter.Operation] {
+			log.Error("unknown audit operation", slog.String("operation", filter.Operation))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of operation"))
+			return
+		}
+
+		var errStr *string
+		filter.From, errStr = timeParam(log, r, "from", false)
+		if errStr == nil {
+			filter.To, errStr = timeParam(log, r, "to", true)
+		}
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		records, err := auditImp.GetAuditRecords(filter, first, after)
+		if err != nil {
+			log.Error("failed get audit log", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get audit log"))
+			return
+		}
+
+		recordsNumber, err := auditImp.CountAuditRecords(filter)
+		if err != nil {
+			log.Error("can't count audit records", slerr.Err(err))
+			recordsNumber = 0
+		}
+
+		edges := make([]*model.AuditEdge, 0, len(records))
+		for i, val := range records {
+			edges = append(edges, &model.AuditEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("audit log getted")
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Audit: &model.AuditConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), recordsNumber),
+			},
+		})
+	}
+}
+
+// timeParam reads a time in RFC 3339 or a date. The end bound of a date
+// covers the whole day.
+func timeParam(log *slog.Logger, r *http.Request, key string, end bool) (*time.Time, *string) {
+	timeStr := r.URL.Query().Get(key)
+	if timeStr == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, timeStr)
+		if err == nil && end {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+	if err != nil {
+		log.Error("failed converting of "+key+":", slerr.Err(err))
+
+		reply := "incorrect value of " + key
+		return nil, &reply
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,128 @@
+package get
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/chordpro"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type ChordSheetImp interface {
+	GetChordSheet(songId int64) (*model.ChordSheet, error)
+}
+
+// @Summary      Get Chord Sheet
+// @Tags         songslibrary/chords
+// @Description  Retrieve the chord sheet of a song as sections, lines and chord positions, or download it as ChordPro, optionally transposed.
+// @Produce      json
+// @Produce      text/plain
+// @Param        id         path      int64   true  "Song ID"
+// @Param        transpose  query     int     false "Semitones to transpose the chords by"  Example: -2
+// @Param        format     query     string  false "json or chordpro"  Example: "chordpro"
+// @Success      200        {object}  model.Response  "OK"
+// @Failure      400        {object}  model.Response  "Bad request"
+// @Failure      404        {object}  model.Response  "Song or chord sheet not found"
+// @Failure      500        {object}  model.Response  "Failed to get chord sheet"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/chords [get]
+func ChordSheetGet(log *slog.Logger, chordSheetImp ChordSheetImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.chordSheet.ChordSheetGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		var transpose int
+		if transposeStr := r.URL.Query().Get("transpose"); transposeStr != "" {
+			var err error
+			transpose, err = strconv.Atoi(transposeStr)
+			if err != nil {
+				log.Error("failed converting of transpose:", slerr.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of transpose"))
+				return
+			}
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format, _ = r.Context().Value(middleware.URLFormatCtxKey).(string)
+		}
+		switch format {
+		case "", "json":
+		case "chordpro", "cho", "pro":
+			format = "chordpro"
+		default:
+			log.Error("unknown chord sheet format", slog.String("format", format))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of format"))
+			return
+		}
+
+		sheet, err := chordSheetImp.GetChordSheet(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrChordSheetNotFound) {
+			log.Info("song has no chord sheet", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song has no chord sheet"))
+			return
+		}
+		if err != nil {
+			log.Error("failed get chord sheet", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get chord sheet"))
+			return
+		}
+
+		if transpose != 0 {
+			sheet = chordpro.Transpose(sheet, transpose)
+		}
+
+		if format == "chordpro" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"song-%d.cho\"", songId))
+			if _, err := io.WriteString(w, chordpro.Format(sheet)); err != nil {
+				log.Error("failed to write chord sheet", slerr.Err(err))
+				return
+			}
+
+			log.Info("chord sheet exported", slog.Int64("id", songId), slog.Int("transpose", transpose))
+			return
+		}
+
+		log.Info("chord sheet getted", slog.Int64("id", songId), slog.Int("transpose", transpose))
+		render.JSON(w, r, model.Response{
+			Status:     "OK",
+			ChordSheet: sheet,
+		})
+	}
+}
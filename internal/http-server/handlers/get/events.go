@@ -0,0 +1,174 @@
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type EventsImp interface {
+	GetEvents(filter *model.EventFilter, after int64, limit int) ([]*model.Event, error)
+	LastEventID() (int64, error)
+}
+
+type EventSubscribingImp interface {
+	Subscribe() (<-chan struct{}, func())
+}
+
+// eventsBatch is the number of events read from the log at once.
+const eventsBatch = 100
+
+var eventTypes = map[string]bool{
+	model.EventSongCreated:      true,
+	model.EventSongDeleted:      true,
+	model.EventDetailCreated:    true,
+	model.EventDetailUpdated:    true,
+	model.EventEnrichmentFailed: true,
+}
+
+// @Summary      Stream Library Events
+// @Tags         events
+// @Description  Server-Sent Events stream of library changes: song.created, song.deleted, detail.created, detail.updated and enrichment.failed, with the event as JSON data. Every event carries its id in the event log; a client reconnecting with Last-Event-ID first gets the events it missed. Without it the stream starts with the next event.
+// @Produce      text/event-stream
+// @Param        group          query     string  false "Name of the group"  Example: "Group1"
+// @Param        type           query     string  false "Comma-separated event types"  Example: "song.created,song.deleted"
+// @Param        Last-Event-ID  header    int64   false "Id of the last event received"
+// @Param        lastEventId    query     int64   false "Same as Last-Event-ID, for clients that can't set headers"
+// @Success      200            {string}  string          "Stream of events"
+// @Failure      400            {object}  model.Response  "Bad request"
+// @Failure      500            {object}  model.Response  "Failed to stream events"
+// @Security     ApiKeyAuth
+// @Router       /events [get]
+func EventsGet(log *slog.Logger, eventsImp EventsImp, bus EventSubscribingImp, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.events.EventsGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter := &model.EventFilter{GroupName: r.URL.Query().Get("group")}
+		if typesStr := r.URL.Query().Get("type"); typesStr != "" {
+			for _, eventType := range strings.Split(typesStr, ",") {
+				eventType = strings.TrimSpace(eventType)
+				if !eventTypes[eventType] {
+					log.Error("unknown event type", slog.String("type", eventType))
+
+					w.WriteHeader(http.StatusBadRequest) // 400
+					render.JSON(w, r, model.StatusError("incorrect value of type"))
+					return
+				}
+				filter.Types = append(filter.Types, eventType)
+			}
+		}
+
+		lastEventIdStr := r.Header.Get("Last-Event-ID")
+		if lastEventIdStr == "" {
+			lastEventIdStr = r.URL.Query().Get("lastEventId")
+		}
+
+		// Subscribing first, no event added while the stream starts is missed.
+		wake, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+
+		var after int64
+		var err error
+		if lastEventIdStr != "" {
+			after, err = strconv.ParseInt(lastEventIdStr, 10, 64)
+			if err != nil || after < 0 {
+				log.Error("failed converting of Last-Event-ID:", slog.String("Last-Event-ID", lastEventIdStr))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of Last-Event-ID"))
+				return
+			}
+		} else {
+			after, err = eventsImp.LastEventID()
+			if err != nil {
+				log.Error("failed get last event id", slerr.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError) // 500
+				render.JSON(w, r, model.StatusError("failed to stream events"))
+				return
+			}
+		}
+
+		// The stream outlives the write timeout of the server.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Error("failed to lift write deadline", slerr.Err(err))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK) // 200
+		if err := rc.Flush(); err != nil {
+			log.Error("failed to flush event stream", slerr.Err(err))
+			return
+		}
+
+		log.Info("event stream started", slog.Int64("after", after), slog.String("group", filter.GroupName))
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			events, err := eventsImp.GetEvents(filter, after, eventsBatch)
+			if err != nil {
+				// The client reconnects and resumes after the last event it got.
+				log.Error("failed get events", slerr.Err(err))
+				return
+			}
+			for _, event := range events {
+				if err := writeEvent(w, event); err != nil {
+					log.Info("event stream closed", slerr.Err(err))
+					return
+				}
+				after = event.ID
+			}
+			if err := rc.Flush(); err != nil {
+				log.Info("event stream closed", slerr.Err(err))
+				return
+			}
+			if len(events) == eventsBatch {
+				continue
+			}
+
+			select {
+			case <-r.Context().Done():
+				log.Info("event stream closed", slog.Int64("after", after))
+				return
+			case <-wake:
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					log.Info("event stream closed", slerr.Err(err))
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeEvent writes the event in the text/event-stream format; its JSON is
+// a single line, as a data field must be.
+func writeEvent(w http.ResponseWriter, event *model.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,144 @@
+package get
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/export"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// exportFlushEvery is the number of songs after which the export is flushed
+// to the client.
+const exportFlushEvery = 100
+
+type SongExportImp interface {
+	ExportSongs(ctx context.Context, filter *model.LibraryFilter, write func(song *model.ExportedSong) error) error
+}
+
+// @Summary      Export Song Library
+// @Tags         songslibrary/song
+// @Description  Download the whole library or the songs matching the filters, with details and lyrics, as a streamed file.
+// @Produce      text/csv
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/tab-separated-values
+// @Param        format  query     string  false "csv, json, ndjson or tsv"  Example: "csv"
+// @Param        song    query     string  false "Name of the song"   Example: "Song1"
+// @Param        group   query     string  false "Name of the group"  Example: "Group1"
+// @Param        match   query     string  false "How song and group are matched: exact, prefix, contains or fuzzy"  Example: "contains"
+// @Param        threshold query   number  false "Minimal similarity from 0 to 1 for fuzzy match"  Example: 0.3
+// @Param        releasedFrom query string false "Earliest release date, e.g. 2006, 2006-07 or 2006-07-16"  Example: "2000"
+// @Param        releasedTo   query string false "Latest release date; a year or month includes the whole period"  Example: "2009-12"
+// @Param        sort    query     string  false "Comma-separated sort keys song, group, releaseDate or score (fuzzy match only), prefixed with - for descending order"  Example: "group,song"
+// @Success      200     {string}  string          "Library file"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      500     {object}  model.Response  "Failed to export song library"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/export [get]
+func SongsExport(log *slog.Logger, songExportImp SongExportImp, similarityThreshold float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.export.SongsExport()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format, _ = r.Context().Value(middleware.URLFormatCtxKey).(string)
+		}
+		if format == "" {
+			format = export.FormatCSV
+		}
+
+		contentType, ok := export.ContentTypes[format]
+		if !ok {
+			log.Error("unknown export format", slog.String("format", format))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of format"))
+			return
+		}
+
+		filter, errStr := libraryFilter(log, r, similarityThreshold)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		// The headers are sent with the first song, so that a failure before
+		// it can still be answered with an error status.
+		buffer := bufio.NewWriter(w)
+		writer := export.NewWriter(format, buffer)
+		var count int
+		start := func() {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"songslibrary-%s.%s\"",
+				time.Now().UTC().Format("2006-01-02"), format))
+			w.WriteHeader(http.StatusOK)
+		}
+
+		// A large library takes longer to send than the write timeout of the
+		// server allows, which would cut the file short behind a 200.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Error("failed to lift write deadline", slerr.Err(err))
+		}
+
+		err := songExportImp.ExportSongs(r.Context(), filter, func(song *model.ExportedSong) error {
+			if count == 0 {
+				start()
+			}
+			count++
+
+			if err := writer.Write(song); err != nil {
+				return err
+			}
+			if count%exportFlushEvery == 0 {
+				if err := buffer.Flush(); err != nil {
+					return err
+				}
+				if flusher, ok := w.(http.Flusher); ok {
+					flusher.Flush()
+				}
+			}
+			return nil
+		})
+		if err != nil && count == 0 {
+			log.Error("failed to export library", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to export song library"))
+			return
+		}
+		if err != nil {
+			// The file is already partly sent, so the client gets it cut short.
+			log.Error("export interrupted", slog.Int("songs", count), slerr.Err(err))
+			return
+		}
+
+		if count == 0 {
+			start()
+		}
+		err = writer.Close()
+		if err == nil {
+			err = buffer.Flush()
+		}
+		if err != nil {
+			log.Error("failed to write export", slerr.Err(err))
+			return
+		}
+
+		log.Info("song library exported", slog.String("format", format), slog.Int("songs", count))
+	}
+}
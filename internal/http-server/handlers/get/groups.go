@@ -0,0 +1,253 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type GroupsImp interface {
+	GetGroups(limit int64, offset int64) ([]*model.Group, error)
+	CountGroups() (int64, error)
+}
+
+type GroupImp interface {
+	GetGroup(id int64) (*model.Group, error)
+}
+
+type GroupSongsImp interface {
+	GroupImp
+	SongLibraryImp
+}
+
+// @Summary      Get Groups
+// @Tags         groups
+// @Description  Retrieve the list of groups with pagination options.
+// @Produce      json
+// @Param        first   query     int64   false "Number of items to return"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      500     {object}  model.Response  "Failed to get groups"
+// @Security     ApiKeyAuth
+// @Router       /groups [get]
+func GroupsGet(log *slog.Logger, groupsImp GroupsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.groups.GroupsGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		groups, err := groupsImp.GetGroups(first, after)
+		if err != nil {
+			log.Error("failed get groups", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get groups"))
+			return
+		}
+
+		groupsNumber, err := groupsImp.CountGroups()
+		if err != nil {
+			log.Error("can't count groups", slerr.Err(err))
+			groupsNumber = 0
+		}
+
+		edges := make([]*model.GroupEdge, 0, len(groups))
+		for i, val := range groups {
+			edges = append(edges, &model.GroupEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("groups getted")
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Groups: &model.GroupsConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), groupsNumber),
+			},
+		})
+	}
+}
+
+// @Summary      Get Group
+// @Tags         groups
+// @Description  Retrieve a group by its ID.
+// @Produce      json
+// @Param        id      path      int64   true  "Group ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Group not found"
+// @Failure      500     {object}  model.Response  "Failed to get group"
+// @Security     ApiKeyAuth
+// @Router       /groups/{id} [get]
+func GroupGet(log *slog.Logger, groupImp GroupImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.groups.GroupGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		group, ok := getGroup(log, w, r, groupImp)
+		if !ok {
+			return
+		}
+
+		log.Info("group getted", slog.Int64("id", group.ID))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Group:  group,
+		})
+	}
+}
+
+// @Summary      Get Group Songs
+// @Tags         groups
+// @Description  Retrieve the songs of a group with pagination options.
+// @Produce      json
+// @Param        id      path      int64   true  "Group ID"
+// @Param        first   query     int64   false "Number of items to return after the cursor"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Param        last    query     int64   false "Number of items to return before the cursor"  Example: 10
+// @Param        before  query     string  false "Cursor before which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Group not found"
+// @Failure      500     {object}  model.Response  "Failed to get group songs"
+// @Security     ApiKeyAuth
+// @Router       /groups/{id}/songs [get]
+func GroupSongs(log *slog.Logger, groupSongsImp GroupSongsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.groups.GroupSongs()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		group, ok := getGroup(log, w, r, groupSongsImp)
+		if !ok {
+			return
+		}
+
+		filter := &model.LibraryFilter{
+			GroupName: group.Name,
+			Match:     model.MatchExact,
+		}
+
+		page, errStr := libraryPage(log, r, filter.Sort)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		resp, err := paginationLibrary(filter, page, groupSongsImp, log)
+		if err != nil {
+			log.Error("failed get group songs", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get group songs"))
+			return
+		}
+
+		log.Info("group songs getted", slog.Int64("id", group.ID))
+		render.JSON(w, r, resp)
+	}
+}
+
+func getGroup(log *slog.Logger, w http.ResponseWriter, r *http.Request, groupImp GroupImp) (*model.Group, bool) {
+	id, errStr := decoder.IdURLParam(log, r, "id")
+	if errStr != nil {
+		w.WriteHeader(http.StatusBadRequest) // 400
+		render.JSON(w, r, model.StatusError(*errStr))
+		return nil, false
+	}
+
+	group, err := groupImp.GetGroup(id)
+	if errors.Is(err, storage.ErrGroupNotFound) {
+		log.Info("group doesn't exist", slog.Int64("id", id))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("group doesn't exist"))
+		return nil, false
+	}
+	if err != nil {
+		log.Error("failed get group", slerr.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError) // 500
+		render.JSON(w, r, model.StatusError("failed get group"))
+		return nil, false
+	}
+
+	return group, true
+}
+
+func pageParams(log *slog.Logger, r *http.Request) (int64, int64, *string) {
+	firstStr := r.URL.Query().Get("first")
+	afterStr := r.URL.Query().Get("after")
+
+	var first int64 = 10
+	var after int64
+	var err error
+
+	if firstStr != "" {
+		first, err = strconv.ParseInt(firstStr, 10, 64)
+		if err != nil {
+			log.Error("failed converting of first:", slerr.Err(err))
+
+			reply := "incorrect value of first"
+			return 0, 0, &reply
+		}
+	}
+
+	if afterStr != "" {
+		after, err = cursor.DecodeOffset(afterStr)
+		if err != nil {
+			log.Error("failed decoding of after:", slerr.Err(err))
+
+			reply := "incorrect value of after"
+			return 0, 0, &reply
+		}
+	}
+
+	return first, after, nil
+}
+
+// offsetPageInfo describes a page of count items taken after the given offset.
+func offsetPageInfo(after int64, count int, total int64) *model.LibraryPageInfo {
+	pageInfo := &model.LibraryPageInfo{
+		HasPreviousPage: after > 0,
+		HasNextPage:     after+int64(count) < total,
+		TotalCount:      total,
+	}
+	if count > 0 {
+		startCursor := cursor.EncodeOffset(after + 1)
+		endCursor := cursor.EncodeOffset(after + int64(count))
+		pageInfo.StartCursor = &startCursor
+		pageInfo.EndCursor = &endCursor
+	}
+	return pageInfo
+}
@@ -0,0 +1,70 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type JobImp interface {
+	GetJob(id int64) (*model.Job, error)
+}
+
+// @Summary      Get Job
+// @Tags         jobs
+// @Description  Retrieve the state of a background job, e.g. fetching the details of a new song: queued, running, succeeded or failed, with the number of attempts and the error of the last one.
+// @Produce      json
+// @Param        id      path      int64   true  "Job ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Job not found"
+// @Failure      500     {object}  model.Response  "Failed to get job"
+// @Security     ApiKeyAuth
+// @Router       /jobs/{id} [get]
+func JobGet(log *slog.Logger, jobImp JobImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.job.JobGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		job, err := jobImp.GetJob(id)
+		if errors.Is(err, storage.ErrJobNotFound) {
+			log.Info("job doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("job doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed get job", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get job"))
+			return
+		}
+		job.URL = r.URL.Path
+
+		log.Info("job getted", slog.Int64("id", id), slog.String("state", job.State))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Job:    job,
+		})
+	}
+}
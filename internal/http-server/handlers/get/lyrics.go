@@ -0,0 +1,145 @@
+package get
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type LyricsImp interface {
+	GetLyrics(songId int64) (*model.Lyrics, error)
+	GetLyricLineAt(songId int64, at int64) (*model.LyricLine, error)
+}
+
+// @Summary      Get Song Lyrics
+// @Tags         songslibrary/lyrics
+// @Description  Retrieve the time-synced lyrics of a song, the line sung at a playback offset, or download them as an LRC file.
+// @Produce      json
+// @Produce      text/plain
+// @Param        id      path      int64   true  "Song ID"
+// @Param        at      query     string  false "Playback offset as mm:ss.xx"  Example: "01:02.50"
+// @Param        format  query     string  false "json or lrc"  Example: "lrc"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song, lyrics or line not found"
+// @Failure      500     {object}  model.Response  "Failed to get lyrics"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/lyrics [get]
+func LyricsGet(log *slog.Logger, lyricsImp LyricsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.lyrics.LyricsGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		if atStr := r.URL.Query().Get("at"); atStr != "" {
+			at, err := lrc.ParseTime(atStr)
+			if err != nil {
+				log.Error("failed converting of at:", slerr.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of at"))
+				return
+			}
+
+			line, err := lyricsImp.GetLyricLineAt(songId, at)
+			if errors.Is(err, storage.ErrLyricLineNotFound) {
+				log.Info("no lyric line at offset", slog.Int64("id", songId), slog.Int64("at", at))
+
+				w.WriteHeader(http.StatusNotFound) // 404
+				render.JSON(w, r, model.StatusError("no line is sung at this offset"))
+				return
+			}
+			if !lyricsFound(log, w, r, songId, err) {
+				return
+			}
+
+			log.Info("lyric line getted", slog.Int64("id", songId), slog.Int64("at", at))
+			render.JSON(w, r, model.Response{
+				Status:    "OK",
+				LyricLine: line,
+			})
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format, _ = r.Context().Value(middleware.URLFormatCtxKey).(string)
+		}
+		if format != "" && format != "json" && format != "lrc" {
+			log.Error("unknown lyrics format", slog.String("format", format))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of format"))
+			return
+		}
+
+		lyrics, err := lyricsImp.GetLyrics(songId)
+		if !lyricsFound(log, w, r, songId, err) {
+			return
+		}
+
+		if format == "lrc" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"song-%d.lrc\"", songId))
+			if _, err := io.WriteString(w, lrc.Format(lyrics)); err != nil {
+				log.Error("failed to write lyrics", slerr.Err(err))
+				return
+			}
+
+			log.Info("lyrics exported", slog.Int64("id", songId))
+			return
+		}
+
+		log.Info("lyrics getted", slog.Int64("id", songId))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Lyrics: lyrics,
+		})
+	}
+}
+
+// lyricsFound answers the request when the song or its lyrics are missing.
+func lyricsFound(log *slog.Logger, w http.ResponseWriter, r *http.Request, songId int64, err error) bool {
+	if errors.Is(err, storage.ErrSongNotFound) {
+		log.Info("song doesn't exist", slog.Int64("id", songId))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("song doesn't exist"))
+		return false
+	}
+	if errors.Is(err, storage.ErrLyricsNotFound) {
+		log.Info("song has no lyrics", slog.Int64("id", songId))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("song has no lyrics"))
+		return false
+	}
+	if err != nil {
+		log.Error("failed get lyrics", slerr.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError) // 500
+		render.JSON(w, r, model.StatusError("failed get lyrics"))
+		return false
+	}
+	return true
+}
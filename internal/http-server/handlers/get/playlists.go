@@ -0,0 +1,144 @@
+package get
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/playlist"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type PlaylistImp interface {
+	GetPlaylist(id int64) (*model.Playlist, error)
+}
+
+// @Summary      Get Playlist
+// @Tags         playlists
+// @Description  Retrieve a playlist with its ordered entries.
+// @Produce      json
+// @Param        id      path      int64   true  "Playlist ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Playlist not found"
+// @Failure      500     {object}  model.Response  "Failed to get playlist"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id} [get]
+func PlaylistGet(log *slog.Logger, playlistImp PlaylistImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.playlists.PlaylistGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		pl, ok := getPlaylist(log, w, r, playlistImp)
+		if !ok {
+			return
+		}
+
+		log.Info("playlist getted", slog.Int64("id", pl.ID))
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Playlist: pl,
+		})
+	}
+}
+
+// @Summary      Export Playlist
+// @Tags         playlists
+// @Description  Download a playlist as extended M3U or XSPF, using song links as track locations.
+// @Produce      audio/x-mpegurl
+// @Produce      application/xspf+xml
+// @Param        id      path      int64   true  "Playlist ID"
+// @Param        format  query     string  false "m3u or xspf" Example: "m3u"
+// @Success      200     {string}  string          "Playlist file"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Playlist not found"
+// @Failure      500     {object}  model.Response  "Failed to export playlist"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id}/export [get]
+func PlaylistExport(log *slog.Logger, playlistImp PlaylistImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.playlists.PlaylistExport()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format, _ = r.Context().Value(middleware.URLFormatCtxKey).(string)
+		}
+		if format == "" {
+			format = "m3u"
+		}
+
+		var contentType string
+		var write func(w io.Writer, pl *model.Playlist) error
+		switch format {
+		case "m3u", "m3u8":
+			contentType = "audio/x-mpegurl; charset=utf-8"
+			write = playlist.WriteM3U
+		case "xspf":
+			contentType = "application/xspf+xml; charset=utf-8"
+			write = playlist.WriteXSPF
+		default:
+			log.Error("unknown playlist format", slog.String("format", format))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of format"))
+			return
+		}
+
+		pl, ok := getPlaylist(log, w, r, playlistImp)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"playlist-%d.%s\"", pl.ID, format))
+		if err := write(w, pl); err != nil {
+			log.Error("failed to write playlist", slerr.Err(err))
+			return
+		}
+
+		log.Info("playlist exported", slog.Int64("id", pl.ID), slog.String("format", format))
+	}
+}
+
+func getPlaylist(log *slog.Logger, w http.ResponseWriter, r *http.Request, playlistImp PlaylistImp) (*model.Playlist, bool) {
+	id, errStr := decoder.IdURLParam(log, r, "id")
+	if errStr != nil {
+		w.WriteHeader(http.StatusBadRequest) // 400
+		render.JSON(w, r, model.StatusError(*errStr))
+		return nil, false
+	}
+
+	pl, err := playlistImp.GetPlaylist(id)
+	if errors.Is(err, storage.ErrPlaylistNotFound) {
+		log.Info("playlist doesn't exist", slog.Int64("id", id))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("playlist doesn't exist"))
+		return nil, false
+	}
+	if err != nil {
+		log.Error("failed get playlist", slerr.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError) // 500
+		render.JSON(w, r, model.StatusError("failed get playlist"))
+		return nil, false
+	}
+
+	return pl, true
+}
@@ -0,0 +1,257 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/diff"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type RevisionsImp interface {
+	GetRevisions(songId int64, limit int64, offset int64) ([]*model.Revision, error)
+	CountRevisions(songId int64) (int64, error)
+}
+
+type RevisionImp interface {
+	GetRevision(songId int64, revision int64) (*model.Revision, error)
+}
+
+// @Summary      Get Song Revisions
+// @Tags         songslibrary/revisions
+// @Description  Retrieve the revisions of the song detail, newest first.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        first   query     int64   false "Number of items to return"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song not found"
+// @Failure      500     {object}  model.Response  "Failed to get revisions"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/revisions [get]
+func RevisionsGet(log *slog.Logger, revisionsImp RevisionsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.revisions.RevisionsGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		revisionsNumber, err := revisionsImp.CountRevisions(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("can't count revisions", slerr.Err(err))
+			revisionsNumber = 0
+		}
+
+		revisions, err := revisionsImp.GetRevisions(songId, first, after)
+		if err != nil {
+			log.Error("failed get revisions", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get revisions"))
+			return
+		}
+
+		edges := make([]*model.RevisionEdge, 0, len(revisions))
+		for i, val := range revisions {
+			edges = append(edges, &model.RevisionEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("revisions getted", slog.Int64("id", songId))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Revisions: &model.RevisionsConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), revisionsNumber),
+			},
+		})
+	}
+}
+
+// @Summary      Get Song Revision
+// @Tags         songslibrary/revisions
+// @Description  Retrieve one revision of the song detail.
+// @Produce      json
+// @Param        id        path      int64   true  "Song ID"
+// @Param        revision  path      int64   true  "Revision number"
+// @Success      200       {object}  model.Response  "OK"
+// @Failure      400       {object}  model.Response  "Bad request"
+// @Failure      404       {object}  model.Response  "Song or revision not found"
+// @Failure      500       {object}  model.Response  "Failed to get revision"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/revisions/{revision} [get]
+func RevisionGet(log *slog.Logger, revisionImp RevisionImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.revisions.RevisionGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		number, errStr := decoder.IdURLParam(log, r, "revision")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		revision, ok := getRevision(log, w, r, revisionImp, songId, number)
+		if !ok {
+			return
+		}
+
+		log.Info("revision getted", slog.Int64("id", songId), slog.Int64("revision", number))
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Revision: revision,
+		})
+	}
+}
+
+// @Summary      Diff Song Revisions
+// @Tags         songslibrary/revisions
+// @Description  Compare two revisions of the song detail; the text is compared line by line.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        from    query     int64   true  "Older revision number"  Example: 1
+// @Param        to      query     int64   true  "Newer revision number"  Example: 2
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request or revisions too large to compare"
+// @Failure      404     {object}  model.Response  "Song or revision not found"
+// @Failure      500     {object}  model.Response  "Failed to get revision"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/revisions/diff [get]
+func RevisionDiff(log *slog.Logger, revisionImp RevisionImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.revisions.RevisionDiff()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		var numbers [2]int64
+		for i, key := range []string{"from", "to"} {
+			number, err := strconv.ParseInt(r.URL.Query().Get(key), 10, 64)
+			if err != nil {
+				log.Error("failed converting of "+key+":", slerr.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of "+key))
+				return
+			}
+			numbers[i] = number
+		}
+
+		from, ok := getRevision(log, w, r, revisionImp, songId, numbers[0])
+		if !ok {
+			return
+		}
+		to, ok := getRevision(log, w, r, revisionImp, songId, numbers[1])
+		if !ok {
+			return
+		}
+
+		lines, err := diff.Lines(from.Detail.Text, to.Detail.Text)
+		if errors.Is(err, diff.ErrTooLarge) {
+			log.Info("revisions too large to compare", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("revisions are too large to compare"))
+			return
+		}
+
+		revisionDiff := &model.RevisionDiff{
+			From:  from.Revision,
+			To:    to.Revision,
+			Lines: lines,
+		}
+		if from.Detail.ReleaseDate != to.Detail.ReleaseDate {
+			revisionDiff.ReleaseDate = &model.ValueChange{From: from.Detail.ReleaseDate, To: to.Detail.ReleaseDate}
+		}
+		if from.Detail.Link != to.Detail.Link {
+			revisionDiff.Link = &model.ValueChange{From: from.Detail.Link, To: to.Detail.Link}
+		}
+
+		log.Info("revisions compared", slog.Int64("id", songId))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Diff:   revisionDiff,
+		})
+	}
+}
+
+func getRevision(log *slog.Logger, w http.ResponseWriter, r *http.Request, revisionImp RevisionImp, songId int64, number int64) (*model.Revision, bool) {
+	revision, err := revisionImp.GetRevision(songId, number)
+	if errors.Is(err, storage.ErrSongNotFound) {
+		log.Info("song doesn't exist", slog.Int64("id", songId))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("song doesn't exist"))
+		return nil, false
+	}
+	if errors.Is(err, storage.ErrRevisionNotFound) {
+		log.Info("revision doesn't exist", slog.Int64("id", songId), slog.Int64("revision", number))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("revision doesn't exist"))
+		return nil, false
+	}
+	if err != nil {
+		log.Error("failed get revision", slerr.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError) // 500
+		render.JSON(w, r, model.StatusError("failed get revision"))
+		return nil, false
+	}
+
+	return revision, true
+}
@@ -4,16 +4,20 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/cursor"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
 	"github.com/nabishec/restapi/internal/model"
 )
 
 type SongLibraryImp interface {
-	GetSongLibrary(songName string, groupName string, limit int64, offset int64, log *slog.Logger) ([]*model.Song, error)
-	CountNumberOfSong(song string, group string) (int64, error)
+	GetSongLibrary(filter *model.LibraryFilter, page *model.LibraryPage, log *slog.Logger) ([]*model.SongEdge, error)
+	CountNumberOfSong(filter *model.LibraryFilter) (int64, error)
 }
 
 // @Summary      Get Song Library
@@ -22,14 +26,22 @@ type SongLibraryImp interface {
 // @Produce      json
 // @Param        song    query     string  false "Name of the song"   Example: "Song1"
 // @Param        group   query     string  false "Name of the group"  Example: "Group1"
-// @Param        first   query     int64   false "Number of items to return"  Example: 10
-// @Param        after   query     int64   false "Offset from which to return items" Example: 0
+// @Param        match   query     string  false "How song and group are matched: exact, prefix, contains or fuzzy"  Example: "contains"
+// @Param        threshold query   number  false "Minimal similarity from 0 to 1 for fuzzy match"  Example: 0.3
+// @Param        releasedFrom query string false "Earliest release date, e.g. 2006, 2006-07 or 2006-07-16"  Example: "2000"
+// @Param        releasedTo   query string false "Latest release date; a year or month includes the whole period"  Example: "2009-12"
+// @Param        sort    query     string  false "Comma-separated sort keys song, group, releaseDate or score (fuzzy match only), prefixed with - for descending order"  Example: "-releaseDate,group,song"
+// @Param        first   query     int64   false "Number of items to return after the cursor"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Param        last    query     int64   false "Number of items to return before the cursor"  Example: 10
+// @Param        before  query     string  false "Cursor before which to return items"
 // @Success      200     {object}  model.Response      "OK"
 // @Failure      400     {object}  model.Response         "Bad request"
 // @Failure      404     {object}  model.Response         "No songs matching the request"
 // @Failure      500     {object}  model.Response         "Failed to get song library"
+// @Security     ApiKeyAuth
 // @Router       /songslibrary [get]
-func SongsLibrary(log *slog.Logger, songLibraryImp SongLibraryImp) http.HandlerFunc {
+func SongsLibrary(log *slog.Logger, songLibraryImp SongLibraryImp, similarityThreshold float64) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.put.SongsLibrary()"
 
@@ -38,41 +50,21 @@ func SongsLibrary(log *slog.Logger, songLibraryImp SongLibraryImp) http.HandlerF
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
-		songName := r.URL.Query().Get("song")
-		groupName := r.URL.Query().Get("group")
-		firstStr := r.URL.Query().Get("first")
-		afterStr := r.URL.Query().Get("after")
-
-		var first int64
-		var err error
-		if firstStr == "" {
-			first = 10
-		} else {
-			first, err = strconv.ParseInt(firstStr, 10, 64)
-			if err != nil {
-				log.Error("failed converting of first:", slerr.Err(err))
-
-				w.WriteHeader(http.StatusBadRequest) // 400
-				render.JSON(w, r, model.StatusError("incorrect value of first"))
-				return
-			}
+		filter, errStr := libraryFilter(log, r, similarityThreshold)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
 		}
 
-		var after int64
-		if afterStr == "" {
-			after = 0
-		} else {
-			after, err = strconv.ParseInt(afterStr, 10, 64)
-			if err != nil {
-				log.Error("failed converting of after:", slerr.Err(err))
-
-				w.WriteHeader(http.StatusBadRequest) // 400
-				render.JSON(w, r, model.StatusError("incorrect value of after"))
-				return
-			}
+		page, errStr := libraryPage(log, r, filter.Sort)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
 		}
 
-		library, err := songLibraryImp.GetSongLibrary(songName, groupName, first, after, log)
+		resp, err := paginationLibrary(filter, page, songLibraryImp, log)
 		if err != nil {
 			log.Error("failed get library", slerr.Err(err))
 
@@ -80,51 +72,228 @@ func SongsLibrary(log *slog.Logger, songLibraryImp SongLibraryImp) http.HandlerF
 			render.JSON(w, r, model.StatusError("failed get song library"))
 			return
 		}
-		if len(library) == 0 {
+		if len(resp.SongsLibrary.Edges) == 0 {
 			log.Info("there wasn't single song matching request")
 
 			w.WriteHeader(http.StatusNotFound) // 404
 			render.JSON(w, r, model.StatusError("there wasn't single song matching request"))
 			return
 		}
-		resp := paginationLibrary(library, after, songName, groupName, songLibraryImp, log)
 
 		log.Info("song library getted")
 		render.JSON(w, r, resp)
 	}
 }
 
-func paginationLibrary(library []*model.Song, after int64, songName string, groupName string, accesDBFunc SongLibraryImp, log *slog.Logger) model.Response {
-	edges := make([]*model.SongEdge, 0, len(library))
+// libraryFilter reads the song and group filters with their match mode, the
+// release date range and the sort keys of a library listing.
+func libraryFilter(log *slog.Logger, r *http.Request, similarityThreshold float64) (*model.LibraryFilter, *string) {
+	filter := &model.LibraryFilter{
+		SongName:  r.URL.Query().Get("song"),
+		GroupName: r.URL.Query().Get("group"),
+		Match:     r.URL.Query().Get("match"),
+		Threshold: similarityThreshold,
+	}
+	switch filter.Match {
+	case "":
+		filter.Match = model.MatchExact
+	case model.MatchExact, model.MatchPrefix, model.MatchContains, model.MatchFuzzy:
+	default:
+		log.Error("unknown match mode", slog.String("match", filter.Match))
+
+		reply := "incorrect value of match"
+		return nil, &reply
+	}
+
+	thresholdStr := r.URL.Query().Get("threshold")
+	if thresholdStr != "" {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			log.Error("failed converting of threshold:", slog.String("threshold", thresholdStr))
+
+			reply := "incorrect value of threshold"
+			return nil, &reply
+		}
+		filter.Threshold = threshold
+	}
+
+	var errStr *string
+	filter.ReleasedFrom, errStr = releasedParam(log, r, "releasedFrom", false)
+	if errStr == nil {
+		filter.ReleasedTo, errStr = releasedParam(log, r, "releasedTo", true)
+	}
+	if errStr != nil {
+		return nil, errStr
+	}
+
+	scored := filter.Match == model.MatchFuzzy && (filter.SongName != "" || filter.GroupName != "")
+
+	sortStr := r.URL.Query().Get("sort")
+	if sortStr != "" {
+		sort, errStr := librarySort(log, sortStr, scored)
+		if errStr != nil {
+			return nil, errStr
+		}
+		filter.Sort = sort
+	} else if scored {
+		filter.Sort = []model.SortKey{{Field: model.SortScore, Desc: true}}
+	}
+
+	return filter, nil
+}
+
+// releasedParam reads a release date bound in any accepted format. The end
+// bound of a year or month covers the whole period.
+func releasedParam(log *slog.Logger, r *http.Request, key string, end bool) (*time.Time, *string) {
+	dateStr := r.URL.Query().Get(key)
+	if dateStr == "" {
+		return nil, nil
+	}
+
+	date, precision, err := releasedate.Parse(dateStr)
+	if err != nil {
+		log.Error("failed converting of "+key+":", slerr.Err(err))
+
+		reply := "incorrect value of " + key
+		return nil, &reply
+	}
+	if end {
+		date = releasedate.End(date, precision)
+	}
+
+	return &date, nil
+}
+
+// librarySortFields is the allow-list of keys accepted by the sort parameter.
+var librarySortFields = map[string]bool{
+	model.SortSong:        true,
+	model.SortGroup:       true,
+	model.SortReleaseDate: true,
+	model.SortScore:       true,
+}
+
+// librarySort parses a comma-separated list of sort keys, each optionally
+// prefixed with "-" for descending order, e.g. "-releaseDate,group,song".
+// The score key is only available for a fuzzy match.
+func librarySort(log *slog.Logger, sortStr string, scored bool) ([]model.SortKey, *string) {
+	fields := strings.Split(sortStr, ",")
+	keys := make([]model.SortKey, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		key := model.SortKey{Field: strings.TrimSpace(field)}
+		if name, ok := strings.CutPrefix(key.Field, "-"); ok {
+			key.Field, key.Desc = name, true
+		}
+
+		if !librarySortFields[key.Field] || seen[key.Field] || (key.Field == model.SortScore && !scored) {
+			log.Error("unknown sort key", slog.String("sort", sortStr))
+
+			reply := "incorrect value of sort"
+			return nil, &reply
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// libraryPage reads first/after for forward paging or last/before for
+// backward paging. Cursors are checked against the sort keys of the listing.
+func libraryPage(log *slog.Logger, r *http.Request, sort []model.SortKey) (*model.LibraryPage, *string) {
+	firstStr := r.URL.Query().Get("first")
+	afterStr := r.URL.Query().Get("after")
+	lastStr := r.URL.Query().Get("last")
+	beforeStr := r.URL.Query().Get("before")
+
+	if (firstStr != "" || afterStr != "") && (lastStr != "" || beforeStr != "") {
+		log.Error("forward and backward paging requested together")
+
+		reply := "first/after can't be used together with last/before"
+		return nil, &reply
+	}
+
+	page := &model.LibraryPage{Limit: 10}
+	limitStr, limitName := firstStr, "first"
+	cursorStr, cursorName := afterStr, "after"
+	if lastStr != "" || beforeStr != "" {
+		page.Backward = true
+		limitStr, limitName = lastStr, "last"
+		cursorStr, cursorName = beforeStr, "before"
+	}
+
+	if limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit < 0 {
+			log.Error("failed converting of "+limitName+":", slog.String(limitName, limitStr))
+
+			reply := "incorrect value of " + limitName
+			return nil, &reply
+		}
+		page.Limit = limit
+	}
+
+	if cursorStr != "" {
+		c, err := cursor.Decode(cursorStr, sort)
+		if err != nil {
+			log.Error("failed decoding of "+cursorName+":", slerr.Err(err))
 
-	songsNumber, err := accesDBFunc.CountNumberOfSong(songName, groupName)
+			reply := "incorrect value of " + cursorName
+			return nil, &reply
+		}
+		page.Cursor = c
+	}
+
+	return page, nil
+}
+
+// paginationLibrary fetches one row more than requested to find out whether
+// there is another page in the paging direction.
+func paginationLibrary(filter *model.LibraryFilter, page *model.LibraryPage, accesDBFunc SongLibraryImp, log *slog.Logger) (model.Response, error) {
+	probe := *page
+	probe.Limit = page.Limit + 1
+
+	library, err := accesDBFunc.GetSongLibrary(filter, &probe, log)
+	if err != nil {
+		return model.Response{}, err
+	}
+
+	hasMore := int64(len(library)) > page.Limit
+	if hasMore {
+		if page.Backward {
+			library = library[1:]
+		} else {
+			library = library[:page.Limit]
+		}
+	}
+
+	songsNumber, err := accesDBFunc.CountNumberOfSong(filter)
 	if err != nil {
 		log.Error("can't count songs", slerr.Err(err))
 		songsNumber = 0
 	}
 
-	for i, val := range library {
-		edges = append(edges, &model.SongEdge{
-			Node:   val,
-			Cursor: (int64(i) + after + 1),
-		})
+	pageInfo := &model.LibraryPageInfo{
+		TotalCount: songsNumber,
 	}
-
-	endCursor := after + int64(len(library))
-	hasNextPage := endCursor < songsNumber
-	if songsNumber == 0 {
-		hasNextPage = false
+	if page.Backward {
+		pageInfo.HasPreviousPage = hasMore
+		pageInfo.HasNextPage = page.Cursor != nil
+	} else {
+		pageInfo.HasPreviousPage = page.Cursor != nil
+		pageInfo.HasNextPage = hasMore
+	}
+	if len(library) > 0 {
+		pageInfo.StartCursor = &library[0].Cursor
+		pageInfo.EndCursor = &library[len(library)-1].Cursor
 	}
 
 	return model.Response{
 		Status: "OK",
 		SongsLibrary: &model.SongsConnection{
-			Edges: edges,
-			PageInfo: &model.LibraryPageInfo{
-				EndCursor:   &endCursor,
-				HasNextPage: hasNextPage,
-			},
+			Edges:    library,
+			PageInfo: pageInfo,
 		},
-	}
-
+	}, nil
 }
@@ -0,0 +1,51 @@
+package get
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestSongsLibrary(t *testing.T) {
+	handler := SongsLibrary(handlertest.Logger(), handlertest.Storage(t,
+		&model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"},
+		&model.Song{SongName: "Song2", GroupName: "Group1", ReleaseDate: "2010"},
+		&model.Song{SongName: "Song3", GroupName: "Group2"},
+	), 0.3)
+
+	handlertest.Run(t, http.MethodGet, "/songslibrary", handler, []handlertest.Case{
+		{Name: "all songs", Target: "/songslibrary", Status: http.StatusOK, Check: songs("Song1", "Song2", "Song3")},
+		{Name: "by group", Target: "/songslibrary?group=Group1", Status: http.StatusOK, Check: songs("Song1", "Song2")},
+		{Name: "by song", Target: "/songslibrary?song=Song3", Status: http.StatusOK, Check: songs("Song3")},
+		{Name: "no match", Target: "/songslibrary?group=Group3", Status: http.StatusNotFound},
+	})
+}
+
+// songs checks the names of the songs listed by the library.
+func songs(want ...string) func(t *testing.T, resp *model.Response) {
+	return func(t *testing.T, resp *model.Response) {
+		var got []string
+		for _, edge := range resp.SongsLibrary.Edges {
+			got = append(got, edge.Node.SongName)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("songs = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSongsLibraryReleased(t *testing.T) {
+	handler := SongsLibrary(handlertest.Logger(), handlertest.Storage(t,
+		&model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"},
+		&model.Song{SongName: "Song2", GroupName: "Group1", ReleaseDate: "2010"},
+	), 0.3)
+
+	handlertest.Run(t, http.MethodGet, "/songslibrary", handler, []handlertest.Case{
+		{Name: "released from a year", Target: "/songslibrary?releasedFrom=2007", Status: http.StatusOK, Check: songs("Song2")},
+		{Name: "released to a month", Target: "/songslibrary?releasedTo=07.2006", Status: http.StatusOK, Check: songs("Song1")},
+		{Name: "unreadable date", Target: "/songslibrary?releasedFrom=soon", Status: http.StatusBadRequest},
+	})
+}
@@ -0,0 +1,113 @@
+package get
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type SongSearchImp interface {
+	SearchSongs(query string, language string, limit int64, offset int64) ([]*model.SearchResult, error)
+	CountSearchSongs(query string, language string) (int64, error)
+}
+
+// searchLanguages are the configurations the lyrics are indexed with, a query
+// in any other one would never match.
+var searchLanguages = map[string]bool{
+	"english": true,
+	"russian": true,
+}
+
+// IsSearchLanguage tells whether the lyrics are indexed with the language.
+func IsSearchLanguage(language string) bool {
+	return searchLanguages[language]
+}
+
+// @Summary      Search Songs
+// @Tags         songslibrary/song
+// @Description  Full-text search over song lyrics, ranked by relevance, with highlighted snippets.
+// @Produce      json
+// @Param        q       query     string  true  "Search query, web search syntax"  Example: "black hole"
+// @Param        lang    query     string  false "Text search configuration: english or russian"
+// @Param        first   query     int64   false "Number of items to return"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      500     {object}  model.Response  "Failed to search songs"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/search [get]
+func SongSearch(log *slog.Logger, songSearchImp SongSearchImp, defaultLanguage string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.songSearch.SongSearch()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		query := r.URL.Query().Get("q")
+		if query == "" {
+			log.Error("request incomplete")
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("request is incomplete"))
+			return
+		}
+
+		language := r.URL.Query().Get("lang")
+		if language == "" {
+			language = defaultLanguage
+		}
+		if !searchLanguages[language] {
+			log.Error("unknown search language", slog.String("lang", language))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of lang"))
+			return
+		}
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		results, err := songSearchImp.SearchSongs(query, language, first, after)
+		if err != nil {
+			log.Error("failed search songs", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed search songs"))
+			return
+		}
+
+		resultsNumber, err := songSearchImp.CountSearchSongs(query, language)
+		if err != nil {
+			log.Error("can't count search results", slerr.Err(err))
+			resultsNumber = 0
+		}
+
+		edges := make([]*model.SearchEdge, 0, len(results))
+		for i, val := range results {
+			edges = append(edges, &model.SearchEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("songs found", slog.Int("count", len(results)))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			SearchResult: &model.SearchConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), resultsNumber),
+			},
+		})
+	}
+}
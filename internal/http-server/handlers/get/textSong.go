@@ -4,32 +4,42 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/langtag"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/sections"
 	"github.com/nabishec/restapi/internal/model"
 	"github.com/nabishec/restapi/internal/storage"
 )
 
 type GettingTesxtSongImp interface {
-	GetSongText(song *model.Song) (*string, error)
+	GetSongSections(song *model.Song) ([]*model.LyricsSection, error)
+	GetSongTranslations(song *model.Song) ([]*model.Translation, error)
 }
 
 // @Summary      Get Song Text
 // @Tags         songslibrary/song
-// @Description  Retrieve the text of a song with pagination options.
+// @Description  Retrieve the text of a song section by section with pagination options. Sections may be filtered by type; with collapse the repeated sections, e.g. a chorus sung again, come without lines and point at their first occurrence. A translation is returned instead of the original for lang, and with a second language every section is followed by the same section in that language.
 // @Produce      json
 // @Param        song    query     string  true  "Name of the song"   Example: "Song1"
 // @Param        group   query     string  true  "Name of the group"  Example: "Group1"
-// @Param        first   query     int     false "Number of items to return"  Example: 2
-// @Param        after   query     int     false "Offset from which to return items" Example: 1
+// @Param        first   query     int     false "Number of sections to return"  Example: 2
+// @Param        after   query     int     false "Cursor (section position) after which to return sections" Example: 1
+// @Param        type    query     string  false "Comma-separated section types: verse, chorus, pre-chorus, bridge, intro, outro, hook, instrumental, other" Example: chorus
+// @Param        collapse query    bool    false "Leave the lines out of repeated sections"
+// @Param        lang    query     string  false "BCP-47 tag of the translation to return; the Accept-Language header is used without it" Example: ru
+// @Param        with    query     string  false "BCP-47 tag of a second language to interleave section by section; the original lyrics stand for it when the song has no such translation" Example: en
+// @Param        Accept-Language header string false "Preferred languages of the text"
 // @Success      200     {object}  model.Response    "OK"
 // @Failure      400     {object}  model.Response       "Bad request"
-// @Failure      404     {object}  model.Response       "Song not found"
+// @Failure      404     {object}  model.Response       "Song or translation not found"
 // @Failure      500     {object}  model.Response       "Failed to get song text"
+// @Security     ApiKeyAuth
 // @Router       /songslibrary/song [get]
 func TextSongGet(log *slog.Logger, gettingTesxtSongImp GettingTesxtSongImp) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -82,12 +92,52 @@ func TextSongGet(log *slog.Logger, gettingTesxtSongImp GettingTesxtSongImp) http
 			}
 		}
 
+		types, ok := sectionTypes(r.URL.Query().Get("type"))
+		if !ok {
+			log.Error("incorrect value of type", slog.String("type", r.URL.Query().Get("type")))
+
+			w.WriteHeader(http.StatusBadRequest) //400
+			render.JSON(w, r, model.StatusError("incorrect value of type"))
+			return
+		}
+
+		var collapse bool
+		if collapseStr := r.URL.Query().Get("collapse"); collapseStr != "" {
+			collapse, err = strconv.ParseBool(collapseStr)
+			if err != nil {
+				log.Error("failed to convert 'collapse' value", slerr.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest) //400
+				render.JSON(w, r, model.StatusError("incorrect value of collapse"))
+				return
+			}
+		}
+
+		lang, err := languageParam(r, "lang")
+		if err != nil {
+			log.Error("failed converting of lang:", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) //400
+			render.JSON(w, r, model.StatusError("incorrect value of lang"))
+			return
+		}
+		with, err := languageParam(r, "with")
+		if err != nil {
+			log.Error("failed converting of with:", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) //400
+			render.JSON(w, r, model.StatusError("incorrect value of with"))
+			return
+		}
+		accepted := langtag.Accepted(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+
 		song := &model.Song{
 			SongName:  songName,
 			GroupName: groupName,
 		}
 
-		text, err := gettingTesxtSongImp.GetSongText(song)
+		lyricsSections, err := gettingTesxtSongImp.GetSongSections(song)
 		if errors.Is(err, storage.ErrSongNotFound) {
 			log.Info("song doesn't exist", slog.String("song:", song.SongName+
 				":"+song.GroupName))
@@ -104,7 +154,49 @@ func TextSongGet(log *slog.Logger, gettingTesxtSongImp GettingTesxtSongImp) http
 			return
 		}
 
-		resp := pagination(text, first, after)
+		versions := []*lyricsVersion{{sections: lyricsSections}}
+		if lang != "" || with != "" || len(accepted) > 0 {
+			translations, err := gettingTesxtSongImp.GetSongTranslations(song)
+			if err != nil {
+				log.Error("failed getiing translations of song", slerr.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError) //500
+				render.JSON(w, r, model.StatusError("failed getting text of song"))
+				return
+			}
+
+			if lang != "" {
+				translation := matchTranslation(translations, lang)
+				if translation == nil {
+					log.Info("translation doesn't exist", slog.String("language", lang))
+
+					w.WriteHeader(http.StatusNotFound) //404
+					render.JSON(w, r, model.StatusError("translation doesn't exist"))
+					return
+				}
+				versions[0] = translationVersion(translation)
+			} else if translation := matchTranslation(translations, accepted...); translation != nil {
+				versions[0] = translationVersion(translation)
+			}
+
+			if with != "" {
+				second := &lyricsVersion{language: with, sections: lyricsSections}
+				if translation := matchTranslation(translations, with); translation != nil {
+					second = translationVersion(translation)
+				}
+				if second.language != versions[0].language {
+					versions = append(versions, second)
+				}
+			}
+		}
+
+		if collapse {
+			for _, version := range versions {
+				version.sections = sections.Collapse(version.sections)
+			}
+		}
+
+		resp := pagination(versions, types, first, after)
 
 		log.Info("song text retrieved successfully")
 		render.JSON(w, r, resp)
@@ -112,27 +204,110 @@ func TextSongGet(log *slog.Logger, gettingTesxtSongImp GettingTesxtSongImp) http
 
 }
 
-func pagination(text *string, first int, after int) model.Response {
-	var edges []*model.CoupletEdge
+// sectionTypes reads the comma-separated section types of the filter; an
+// empty filter lets every section through.
+func sectionTypes(value string) (map[string]bool, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	types := make(map[string]bool)
+	for _, sectionType := range strings.Split(value, ",") {
+		sectionType = strings.ToLower(strings.TrimSpace(sectionType))
+		if !slices.Contains(sections.Types, sectionType) {
+			return nil, false
+		}
+		types[sectionType] = true
+	}
+	return types, true
+}
+
+// languageParam reads an optional BCP-47 language tag from the query.
+func languageParam(r *http.Request, key string) (string, error) {
+	tag := r.URL.Query().Get(key)
+	if tag == "" {
+		return "", nil
+	}
+	return langtag.Canonical(tag)
+}
+
+// lyricsVersion is the lyrics of the song in one language. The language of
+// the original lyrics is unknown and left empty.
+type lyricsVersion struct {
+	language string
+	sections []*model.LyricsSection
+}
+
+func translationVersion(translation *model.Translation) *lyricsVersion {
+	return &lyricsVersion{language: translation.Language, sections: translation.Sections}
+}
+
+// matchTranslation picks the translation serving the wanted languages best;
+// it is nil when none of them is translated.
+func matchTranslation(translations []*model.Translation, wanted ...string) *model.Translation {
+	available := make([]string, 0, len(translations))
+	for _, translation := range translations {
+		available = append(available, translation.Language)
+	}
+
+	language, ok := langtag.Match(available, wanted...)
+	if !ok {
+		return nil
+	}
+	return translations[slices.Index(available, language)]
+}
 
-	couplets := strings.Split(*text, "\n\n")
+func filterSections(lyricsSections []*model.LyricsSection, types map[string]bool) []*model.LyricsSection {
+	if types == nil {
+		return lyricsSections
+	}
 
-	startInd := after
-	endIndex := startInd + first
+	var filtered []*model.LyricsSection
+	for _, section := range lyricsSections {
+		if types[section.Type] {
+			filtered = append(filtered, section)
+		}
+	}
+	return filtered
+}
 
-	var endCursor int
+// pagination pages the sections of the first version by position: the
+// cursor of an edge is the position of its section in the song, so it stays
+// valid under any filter. Every section is followed by the sections at its
+// position in the other versions, which share its cursor.
+func pagination(versions []*lyricsVersion, types map[string]bool, first int, after int) model.Response {
+	edges := []*model.CoupletEdge{}
 
-	for i, length := startInd, len(couplets); i < length && i < endIndex; i++ {
-		edges = append(edges, &model.CoupletEdge{
-			Node:   &couplets[i],
-			Cursor: i + 1,
-		})
-		if i < (length-1) || i < (endIndex-1) {
-			endCursor = i
+	companions := make([]map[int]*model.LyricsSection, 0, len(versions)-1)
+	for _, version := range versions[1:] {
+		byPosition := make(map[int]*model.LyricsSection, len(version.sections))
+		for _, section := range version.sections {
+			byPosition[section.Position] = section
 		}
+		companions = append(companions, byPosition)
 	}
 
-	hasNextPage := endCursor < len(couplets)
+	var count int
+	var hasNextPage bool
+	endCursor := after
+	for _, section := range filterSections(versions[0].sections, types) {
+		if section.Position <= after {
+			continue
+		}
+		if count == first {
+			hasNextPage = true
+			break
+		}
+		count++
+		endCursor = section.Position
+
+		edges = append(edges, sectionEdge(section, versions[0].language))
+		for i, byPosition := range companions {
+			if companion, ok := byPosition[section.Position]; ok {
+				edges = append(edges, sectionEdge(companion, versions[i+1].language))
+			}
+		}
+	}
 
 	return model.Response{
 		Status: "OK",
@@ -146,3 +321,17 @@ func pagination(text *string, first int, after int) model.Response {
 	}
 
 }
+
+func sectionEdge(section *model.LyricsSection, language string) *model.CoupletEdge {
+	edge := &model.CoupletEdge{
+		Cursor:   section.Position,
+		Section:  section,
+		Language: language,
+	}
+	// A collapsed repeat has no lines of its own.
+	if len(section.Lines) > 0 {
+		node := strings.Join(section.Lines, "\n")
+		edge.Node = &node
+	}
+	return edge
+}
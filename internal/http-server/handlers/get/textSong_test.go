@@ -0,0 +1,52 @@
+package get
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestTextSongGet(t *testing.T) {
+	handler := TextSongGet(handlertest.Logger(), handlertest.Storage(t,
+		&model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"},
+	))
+
+	handlertest.Run(t, http.MethodGet, "/song", handler, []handlertest.Case{
+		{Name: "first page", Target: "/song?song=Song1&group=Group1", Status: http.StatusOK, Check: couplets(true, "a", "b")},
+		{Name: "after a cursor", Target: "/song?song=Song1&group=Group1&after=2", Status: http.StatusOK, Check: couplets(false, "c")},
+		{Name: "unknown song", Target: "/song?song=Song2&group=Group1", Status: http.StatusNotFound},
+		{Name: "no group", Target: "/song?song=Song1", Status: http.StatusBadRequest},
+		{Name: "bad first", Target: "/song?song=Song1&group=Group1&first=x", Status: http.StatusBadRequest},
+	})
+}
+
+// couplets checks the couplets of a page of the text and whether more follow.
+func couplets(hasNextPage bool, want ...string) func(t *testing.T, resp *model.Response) {
+	return func(t *testing.T, resp *model.Response) {
+		var got []string
+		for _, edge := range resp.SongText.Edges {
+			got = append(got, *edge.Node)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("couplets = %q, want %q", got, want)
+		}
+		if resp.SongText.PageInfo.HasNextPage != hasNextPage {
+			t.Errorf("hasNextPage = %v, want %v", resp.SongText.PageInfo.HasNextPage, hasNextPage)
+		}
+	}
+}
+
+func TestTextSongGetType(t *testing.T) {
+	handler := TextSongGet(handlertest.Logger(), handlertest.Storage(t,
+		&model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"},
+	))
+
+	handlertest.Run(t, http.MethodGet, "/song", handler, []handlertest.Case{
+		{Name: "verses", Target: "/song?song=Song1&group=Group1&type=verse", Status: http.StatusOK, Check: couplets(false, "a", "c")},
+		{Name: "choruses", Target: "/song?song=Song1&group=Group1&type=chorus", Status: http.StatusOK, Check: couplets(false, "b")},
+		{Name: "unknown type", Target: "/song?song=Song1&group=Group1&type=refrain", Status: http.StatusBadRequest},
+	})
+}
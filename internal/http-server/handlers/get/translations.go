@@ -0,0 +1,135 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type TranslationsImp interface {
+	GetTranslation(songId int64, language string) (*model.Translation, error)
+	GetTranslations(songId int64) ([]*model.Translation, error)
+}
+
+// @Summary      Get Song Translations
+// @Tags         songslibrary/translations
+// @Description  List the translations of a song by language, each split into sections aligned to the sections of the original lyrics.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song not found"
+// @Failure      500     {object}  model.Response  "Failed to get translations"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/translations [get]
+func TranslationsGet(log *slog.Logger, translationsImp TranslationsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.translations.TranslationsGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		translations, err := translationsImp.GetTranslations(songId)
+		if !translationFound(log, w, r, songId, err) {
+			return
+		}
+		if translations == nil {
+			translations = []*model.Translation{}
+		}
+
+		log.Info("translations getted", slog.Int64("id", songId), slog.Int("count", len(translations)))
+		render.JSON(w, r, model.Response{
+			Status:       "OK",
+			Translations: translations,
+		})
+	}
+}
+
+// @Summary      Get Song Translation
+// @Tags         songslibrary/translations
+// @Description  Retrieve the translation of a song to a language given by its BCP-47 tag.
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        lang    path      string  true  "BCP-47 language tag"  Example: "ru"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song or translation not found"
+// @Failure      500     {object}  model.Response  "Failed to get translation"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/translations/{lang} [get]
+func TranslationGet(log *slog.Logger, translationsImp TranslationsImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.translations.TranslationGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		language, errStr := decoder.LanguageURLParam(log, r, "lang")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		translation, err := translationsImp.GetTranslation(songId, language)
+		if !translationFound(log, w, r, songId, err) {
+			return
+		}
+
+		log.Info("translation getted", slog.Int64("id", songId), slog.String("language", language))
+		render.JSON(w, r, model.Response{
+			Status:      "OK",
+			Translation: translation,
+		})
+	}
+}
+
+// translationFound answers the request when the song or its translation are missing.
+func translationFound(log *slog.Logger, w http.ResponseWriter, r *http.Request, songId int64, err error) bool {
+	if errors.Is(err, storage.ErrSongNotFound) {
+		log.Info("song doesn't exist", slog.Int64("id", songId))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("song doesn't exist"))
+		return false
+	}
+	if errors.Is(err, storage.ErrTranslationNotFound) {
+		log.Info("translation doesn't exist", slog.Int64("id", songId))
+
+		w.WriteHeader(http.StatusNotFound) // 404
+		render.JSON(w, r, model.StatusError("translation doesn't exist"))
+		return false
+	}
+	if err != nil {
+		log.Error("failed get translation", slerr.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError) // 500
+		render.JSON(w, r, model.StatusError("failed get translation"))
+		return false
+	}
+	return true
+}
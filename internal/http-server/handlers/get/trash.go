@@ -0,0 +1,78 @@
+package get
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type TrashImp interface {
+	GetTrash(limit int64, offset int64) ([]*model.Song, error)
+	CountTrash() (int64, error)
+}
+
+// @Summary      Get Trash
+// @Tags         trash
+// @Description  Retrieve the deleted songs that can still be restored, most recently deleted first.
+// @Produce      json
+// @Param        first   query     int64   false "Number of items to return"  Example: 10
+// @Param        after   query     string  false "Cursor after which to return items"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      500     {object}  model.Response  "Failed to get trash"
+// @Security     ApiKeyAuth
+// @Router       /trash [get]
+func TrashGet(log *slog.Logger, trashImp TrashImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.trash.TrashGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		first, after, errStr := pageParams(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		songs, err := trashImp.GetTrash(first, after)
+		if err != nil {
+			log.Error("failed get trash", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get trash"))
+			return
+		}
+
+		songsNumber, err := trashImp.CountTrash()
+		if err != nil {
+			log.Error("can't count trash", slerr.Err(err))
+			songsNumber = 0
+		}
+
+		edges := make([]*model.SongEdge, 0, len(songs))
+		for i, val := range songs {
+			edges = append(edges, &model.SongEdge{
+				Node:   val,
+				Cursor: cursor.EncodeOffset(after + int64(i) + 1),
+			})
+		}
+
+		log.Info("trash getted")
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Trash: &model.SongsConnection{
+				Edges:    edges,
+				PageInfo: offsetPageInfo(after, len(edges), songsNumber),
+			},
+		})
+	}
+}
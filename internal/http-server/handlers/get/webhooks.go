@@ -0,0 +1,142 @@
+package get
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type WebhooksImp interface {
+	GetWebhooks() ([]*model.Webhook, error)
+	GetWebhookDeliveries(webhookId int64, state string, first int) ([]*model.WebhookDelivery, error)
+}
+
+// maxDeliveries limits the number of deliveries returned at once.
+const maxDeliveries = 100
+
+var deliveryStates = map[string]bool{
+	model.JobQueued:    true,
+	model.JobRunning:   true,
+	model.JobSucceeded: true,
+	model.JobFailed:    true,
+}
+
+// @Summary      Get Webhooks
+// @Tags         admin
+// @Description  List webhook subscriptions. Secrets are never returned.
+// @Produce      json
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      500     {object}  model.Response  "Failed to get webhooks"
+// @Security     ApiKeyAuth
+// @Router       /admin/webhooks [get]
+func WebhooksGet(log *slog.Logger, webhooksImp WebhooksImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.webhooks.WebhooksGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hooks, err := webhooksImp.GetWebhooks()
+		if err != nil {
+			log.Error("failed get webhooks", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get webhooks"))
+			return
+		}
+		for _, hook := range hooks {
+			hook.Secret = ""
+		}
+
+		log.Info("webhooks getted")
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Webhooks: hooks,
+		})
+	}
+}
+
+// @Summary      Get Webhook Deliveries
+// @Tags         admin
+// @Description  Retrieve the last deliveries to a webhook, newest first, with the log of their attempts: the status of the answer, the error and the duration.
+// @Produce      json
+// @Param        id      path      int64   true  "Webhook ID"
+// @Param        state   query     string  false "queued, running, succeeded or failed"  Example: "failed"
+// @Param        first   query     int     false "Number of deliveries to return, up to 100"  Example: 20
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Webhook not found"
+// @Failure      500     {object}  model.Response  "Failed to get deliveries"
+// @Security     ApiKeyAuth
+// @Router       /admin/webhooks/{id}/deliveries [get]
+func WebhookDeliveriesGet(log *slog.Logger, webhooksImp WebhooksImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.get.webhooks.WebhookDeliveriesGet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		state := r.URL.Query().Get("state")
+		if state != "" && !deliveryStates[state] {
+			log.Error("unknown delivery state", slog.String("state", state))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of state"))
+			return
+		}
+
+		first := 20
+		if firstStr := r.URL.Query().Get("first"); firstStr != "" {
+			var err error
+			first, err = strconv.Atoi(firstStr)
+			if err != nil || first < 1 || first > maxDeliveries {
+				log.Error("failed converting of first:", slog.String("first", firstStr))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of first"))
+				return
+			}
+		}
+
+		deliveries, err := webhooksImp.GetWebhookDeliveries(id, state, first)
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			log.Info("webhook doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("webhook doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed get deliveries", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed get deliveries"))
+			return
+		}
+
+		log.Info("deliveries getted", slog.Int64("id", id), slog.Int("count", len(deliveries)))
+		render.JSON(w, r, model.Response{
+			Status:     "OK",
+			Deliveries: deliveries,
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+//go:embed schema.graphql
+var schema string
+
+type GraphImp interface {
+	GetSongLibrary(filter *model.LibraryFilter, page *model.LibraryPage, log *slog.Logger) ([]*model.SongEdge, error)
+	CountNumberOfSong(filter *model.LibraryFilter) (int64, error)
+	GetSongSections(song *model.Song) ([]*model.LyricsSection, error)
+	AddSongAndEnqueue(song *model.Song, job *model.Job) error
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	AddSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error
+	DeleteSong(song *model.Song, log *slog.Logger) error
+	DetailsLoadingImp
+	audit.RecorderImp
+	event.PublisherImp
+}
+
+type Request struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// @Summary      GraphQL
+// @Tags         graphql
+// @Description  Query the song library with GraphQL: songs come in Relay connection pages with the same filters, sort keys and cursors as the REST listing, and the text of a song is a nested connection of its couplets. The details of all the songs of a page are fetched at once. The mutations addSong and updateSongDetail need the editor role and deleteSong the admin role, like their REST counterparts.
+// @Accept       json
+// @Produce      json
+// @Param        request body      Request  true  "GraphQL query with its variables" Example: {"query": "{ songs(first: 2) { edges { node { song group detail { link } } } } }"}
+// @Success      200     {object}  map[string]interface{}  "Data and errors of the query"
+// @Failure      400     {object}  model.Response          "Bad request"
+// @Security     ApiKeyAuth
+// @Router       /graphql [post]
+func Handler(log *slog.Logger, graphImp GraphImp, similarityThreshold float64, maxAttempts int) http.HandlerFunc {
+	s := graphql.MustParseSchema(schema, &resolver{
+		graphImp:            graphImp,
+		similarityThreshold: similarityThreshold,
+		maxAttempts:         maxAttempts,
+	})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.graph.Handler()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, errStr := decoder.RequestDecoderValJSON[Request](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), stateKey, &requestState{
+			log:     log,
+			r:       r,
+			details: newDetailLoader(graphImp),
+		})
+
+		resp := s.Exec(ctx, req.Query, req.OperationName, req.Variables)
+		if len(resp.Errors) > 0 {
+			log.Info("graphql query answered with errors", slog.Int("errors", len(resp.Errors)))
+		} else {
+			log.Info("graphql query answered")
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+type ctxKey int
+
+const stateKey ctxKey = iota
+
+// requestState is what the resolvers of a query share: the request they
+// answer on behalf of and the details loaded for it.
+type requestState struct {
+	log     *slog.Logger
+	r       *http.Request
+	details *detailLoader
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	return ctx.Value(stateKey).(*requestState)
+}
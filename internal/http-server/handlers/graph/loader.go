@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"sync"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type DetailsLoadingImp interface {
+	GetSongDetails(songIds []int64) (map[int64]*model.SongDetail, error)
+}
+
+// detailLoader batches the detail lookups of a query. The songs of a page
+// are queued as soon as the page is read, and the first detail asked for
+// fetches the details of every queued song in a single call, so a page of N
+// songs costs one lookup rather than N. Loaded details are kept for the rest
+// of the query, a missing one as nil.
+type detailLoader struct {
+	loading DetailsLoadingImp
+
+	mu      sync.Mutex
+	queued  map[int64]bool
+	details map[int64]*model.SongDetail
+}
+
+func newDetailLoader(loading DetailsLoadingImp) *detailLoader {
+	return &detailLoader{
+		loading: loading,
+		queued:  make(map[int64]bool),
+		details: make(map[int64]*model.SongDetail),
+	}
+}
+
+// queue adds the songs to the next batch.
+func (l *detailLoader) queue(songIds ...int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, songId := range songIds {
+		if _, ok := l.details[songId]; !ok {
+			l.queued[songId] = true
+		}
+	}
+}
+
+// load returns the detail of the song, fetching it together with the queued
+// ones unless it is loaded already. The lock is held during the fetch, so
+// concurrent resolvers wait for the batch instead of starting their own.
+func (l *detailLoader) load(songId int64) (*model.SongDetail, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if detail, ok := l.details[songId]; ok {
+		return detail, nil
+	}
+
+	l.queued[songId] = true
+	songIds := make([]int64, 0, len(l.queued))
+	for queuedId := range l.queued {
+		songIds = append(songIds, queuedId)
+	}
+
+	details, err := l.loading.GetSongDetails(songIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, queuedId := range songIds {
+		l.details[queuedId] = details[queuedId]
+		delete(l.queued, queuedId)
+	}
+	return l.details[songId], nil
+}
+
+// store keeps the detail of a song the query has just read or changed.
+func (l *detailLoader) store(songId int64, detail *model.SongDetail) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.details[songId] = detail
+	delete(l.queued, songId)
+}
@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/apikey"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+var (
+	errForbidden    = errors.New("forbidden")
+	errSongNotFound = errors.New("song doesn't exist")
+)
+
+type addSongArgs struct {
+	Input struct {
+		Song  string
+		Group string
+	}
+}
+
+// AddSong adds the song like the REST POST and queues its enrichment.
+func (r *resolver) AddSong(ctx context.Context, args addSongArgs) (*addSongPayloadResolver, error) {
+	state := stateFrom(ctx)
+
+	if err := authorize(ctx, apikey.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	song := &model.Song{
+		SongName:  args.Input.Song,
+		GroupName: args.Input.Group,
+	}
+	if err := validator.New().Struct(song); err != nil {
+		state.log.Error("invalid types", slerr.Err(err))
+		return nil, err
+	}
+
+	job := &model.Job{
+		Kind:        model.JobEnrichSong,
+		MaxAttempts: r.maxAttempts,
+		Author:      auth.Name(ctx),
+		RequestID:   middleware.GetReqID(ctx),
+	}
+
+	err := r.graphImp.AddSongAndEnqueue(song, job)
+	if errors.Is(err, storage.ErrSongAlreadyExists) {
+		state.log.Info("song already exist", slog.String("song: ", song.SongName+":"+song.GroupName))
+		return nil, errors.New("song already exist")
+	}
+	if err != nil {
+		state.log.Error("failed to add song", slerr.Err(err))
+		return nil, errors.New("failed to add song")
+	}
+
+	audit.Record(state.log, r.graphImp, state.r, model.AuditAddSong, song, nil, &audit.SongState{Song: song})
+
+	job.Song = song
+	job.URL = fmt.Sprintf("/api/v1/jobs/%d", job.ID)
+	event.Publish(state.log, r.graphImp, state.r, model.EventSongCreated, song, job)
+
+	// The details are yet to be fetched by the job.
+	state.details.store(song.ID, nil)
+
+	state.log.Info("song added, enrichment queued", slog.Int64("job", job.ID))
+	return &addSongPayloadResolver{
+		song: &songResolver{graphImp: r.graphImp, song: song},
+		job:  job,
+	}, nil
+}
+
+type updateSongDetailArgs struct {
+	Input struct {
+		Song        string
+		Group       string
+		ReleaseDate string
+		Link        string
+		Text        string
+		Message     *string
+	}
+}
+
+// UpdateSongDetail sets the details of the song like the REST PUT.
+func (r *resolver) UpdateSongDetail(ctx context.Context, args updateSongDetailArgs) (*songResolver, error) {
+	state := stateFrom(ctx)
+
+	if err := authorize(ctx, apikey.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	songDetail := &model.SongDetail{
+		ReleaseDate: args.Input.ReleaseDate,
+		Link:        args.Input.Link,
+		Text:        args.Input.Text,
+	}
+	if err := validator.New().Struct(songDetail); err != nil {
+		state.log.Error("invalid types", slerr.Err(err))
+		return nil, err
+	}
+	if err := releasedate.Normalize(songDetail); err != nil {
+		state.log.Error("invalid release date", slerr.Err(err))
+		return nil, errors.New("incorrect value of releaseDate")
+	}
+	sections.Normalize(songDetail)
+
+	song, err := r.findSong(state, args.Input.Song, args.Input.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := r.graphImp.GetSongDetail(song)
+	if errors.Is(err, storage.ErrSongDetailNotFound) {
+		before, err = nil, nil
+	}
+	if err != nil {
+		state.log.Error("failed to get song detail", slerr.Err(err))
+		return nil, errors.New("failed to add song detail")
+	}
+
+	change := &model.DetailChange{
+		Author: auth.Name(ctx),
+	}
+	if args.Input.Message != nil {
+		change.Message = *args.Input.Message
+	}
+
+	err = r.graphImp.AddSongDetail(song, songDetail, change)
+	if errors.Is(err, storage.ErrSongNotFound) {
+		state.log.Info("song doesn't exist", slerr.Err(err))
+		return nil, errSongNotFound
+	}
+	if err != nil {
+		state.log.Error("failed to add song detail", slerr.Err(err))
+		return nil, errors.New("failed to add song detail")
+	}
+
+	operation, eventType := model.AuditPutSongDetail, model.EventDetailUpdated
+	if before == nil {
+		operation, eventType = model.AuditAddSongDetail, model.EventDetailCreated
+	}
+	audit.Record(state.log, r.graphImp, state.r, operation, song, before, songDetail)
+	event.Publish(state.log, r.graphImp, state.r, eventType, song, songDetail)
+
+	song.ReleaseDate = songDetail.ReleaseDate
+	state.details.store(song.ID, songDetail)
+
+	state.log.Info("song detail changed")
+	return &songResolver{graphImp: r.graphImp, song: song}, nil
+}
+
+type deleteSongArgs struct {
+	Song  string
+	Group string
+}
+
+// DeleteSong moves the song to the trash like the REST DELETE.
+func (r *resolver) DeleteSong(ctx context.Context, args deleteSongArgs) (*songResolver, error) {
+	state := stateFrom(ctx)
+
+	if err := authorize(ctx, apikey.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	song, err := r.findSong(state, args.Song, args.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	// The detail is only needed for the audit record, so a missing one is fine.
+	songDetail, _ := r.graphImp.GetSongDetail(song)
+
+	err = r.graphImp.DeleteSong(song, state.log)
+	if errors.Is(err, storage.ErrSongNotFound) {
+		state.log.Info("song doesn't exist", slog.String("song:", song.SongName+":"+song.GroupName))
+		return nil, errSongNotFound
+	}
+	if err != nil {
+		state.log.Error("failed delete song", slerr.Err(err))
+		return nil, errors.New("failed deletion of song")
+	}
+
+	audit.Record(state.log, r.graphImp, state.r, model.AuditDeleteSong, song,
+		&audit.SongState{Song: song, Detail: songDetail}, nil)
+	event.Publish(state.log, r.graphImp, state.r, model.EventSongDeleted, song, songDetail)
+
+	state.details.store(song.ID, songDetail)
+
+	state.log.Info("song deleted", slog.String("song:", song.SongName+":"+song.GroupName))
+	return &songResolver{graphImp: r.graphImp, song: song}, nil
+}
+
+// findSong looks the song up in the library by its exact names, for the id
+// the mutations answer with.
+func (r *resolver) findSong(state *requestState, songName string, groupName string) (*model.Song, error) {
+	filter := &model.LibraryFilter{
+		SongName:  songName,
+		GroupName: groupName,
+		Match:     model.MatchExact,
+	}
+
+	library, err := r.graphImp.GetSongLibrary(filter, &model.LibraryPage{Limit: 1}, state.log)
+	if err != nil {
+		state.log.Error("failed to find song", slerr.Err(err))
+		return nil, errors.New("failed to find song")
+	}
+	if len(library) == 0 {
+		state.log.Info("song doesn't exist", slog.String("song:", songName+":"+groupName))
+		return nil, errSongNotFound
+	}
+
+	return library[0].Node, nil
+}
+
+// authorize checks a mutation against the role of the API key, as the REST
+// routes do by method. Without authentication there is no identity to check.
+func authorize(ctx context.Context, role string) error {
+	identity := auth.FromContext(ctx)
+	if identity != nil && !apikey.Allows(identity.Role, role) {
+		return errForbidden
+	}
+	return nil
+}
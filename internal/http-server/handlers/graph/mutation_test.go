@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/apikey"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// keys resolves the keys named after their roles.
+type keys struct{}
+
+func (keys) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	for _, role := range []string{apikey.RoleReader, apikey.RoleEditor, apikey.RoleAdmin} {
+		if apikey.Hash(role) == hash {
+			return &model.APIKey{Name: role, Role: role}, nil
+		}
+	}
+	return nil, storage.ErrAPIKeyNotFound
+}
+
+// authenticated returns the context of a request made with the key of the
+// role, as the auth middleware leaves it.
+func authenticated(t *testing.T, role string) context.Context {
+	t.Helper()
+
+	var ctx context.Context
+	handler := auth.New(slog.New(slog.NewTextHandler(io.Discard, nil)), keys{}, "")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { ctx = r.Context() }))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("X-API-Key", role)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if ctx == nil {
+		t.Fatalf("key of %s not accepted", role)
+	}
+	return ctx
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		required string
+		wantErr  error
+	}{
+		{name: "reader adds", role: apikey.RoleReader, required: apikey.RoleEditor, wantErr: errForbidden},
+		{name: "editor adds", role: apikey.RoleEditor, required: apikey.RoleEditor},
+		{name: "editor deletes", role: apikey.RoleEditor, required: apikey.RoleAdmin, wantErr: errForbidden},
+		{name: "admin deletes", role: apikey.RoleAdmin, required: apikey.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := authorize(authenticated(t, tt.role), tt.required); !errors.Is(err, tt.wantErr) {
+				t.Errorf("authorize() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Without authentication there is no identity, and the REST routes let
+// every request through as well.
+func TestAuthorizeWithoutAuth(t *testing.T) {
+	for _, required := range []string{apikey.RoleReader, apikey.RoleEditor, apikey.RoleAdmin} {
+		if err := authorize(context.Background(), required); err != nil {
+			t.Errorf("authorize(%s) = %v, want nil", required, err)
+		}
+	}
+}
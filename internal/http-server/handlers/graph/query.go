@@ -0,0 +1,232 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+type resolver struct {
+	graphImp            GraphImp
+	similarityThreshold float64
+	maxAttempts         int
+}
+
+type songsArgs struct {
+	Filter *songFilterInput
+	Sort   *[]sortKeyInput
+	First  *int32
+	After  *string
+	Last   *int32
+	Before *string
+}
+
+type songFilterInput struct {
+	Song         *string
+	Group        *string
+	Match        *string
+	Threshold    *float64
+	ReleasedFrom *string
+	ReleasedTo   *string
+}
+
+type sortKeyInput struct {
+	Field string
+	Desc  *bool
+}
+
+// Songs pages the library like the REST listing: one song more than asked
+// for is read to find out whether there is another page.
+func (r *resolver) Songs(ctx context.Context, args songsArgs) (*songsConnectionResolver, error) {
+	state := stateFrom(ctx)
+
+	filter, err := r.libraryFilter(state.log, args)
+	if err != nil {
+		return nil, err
+	}
+	page, err := libraryPage(state.log, args, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	probe := *page
+	probe.Limit = page.Limit + 1
+
+	library, err := r.graphImp.GetSongLibrary(filter, &probe, state.log)
+	if err != nil {
+		state.log.Error("failed get library", slerr.Err(err))
+		return nil, errors.New("failed get song library")
+	}
+
+	hasMore := int64(len(library)) > page.Limit
+	if hasMore {
+		if page.Backward {
+			library = library[1:]
+		} else {
+			library = library[:page.Limit]
+		}
+	}
+
+	songsNumber, err := r.graphImp.CountNumberOfSong(filter)
+	if err != nil {
+		state.log.Error("can't count songs", slerr.Err(err))
+		songsNumber = 0
+	}
+
+	pageInfo := &model.LibraryPageInfo{
+		TotalCount: songsNumber,
+	}
+	if page.Backward {
+		pageInfo.HasPreviousPage = hasMore
+		pageInfo.HasNextPage = page.Cursor != nil
+	} else {
+		pageInfo.HasPreviousPage = page.Cursor != nil
+		pageInfo.HasNextPage = hasMore
+	}
+	if len(library) > 0 {
+		pageInfo.StartCursor = &library[0].Cursor
+		pageInfo.EndCursor = &library[len(library)-1].Cursor
+	}
+
+	// The details of the whole page are fetched together once any is asked for.
+	songIds := make([]int64, 0, len(library))
+	for _, edge := range library {
+		songIds = append(songIds, edge.Node.ID)
+	}
+	state.details.queue(songIds...)
+
+	return &songsConnectionResolver{
+		graphImp: r.graphImp,
+		connection: &model.SongsConnection{
+			Edges:    library,
+			PageInfo: pageInfo,
+		},
+	}, nil
+}
+
+// librarySortFields maps the SortField enum to the sort keys of the library.
+var librarySortFields = map[string]string{
+	"SONG":         model.SortSong,
+	"GROUP":        model.SortGroup,
+	"RELEASE_DATE": model.SortReleaseDate,
+	"SCORE":        model.SortScore,
+}
+
+// libraryFilter reads the filter and the sort keys of the listing. The score
+// key is only available for a fuzzy match, and is the default sort of one.
+func (r *resolver) libraryFilter(log *slog.Logger, args songsArgs) (*model.LibraryFilter, error) {
+	filter := &model.LibraryFilter{
+		Match:     model.MatchExact,
+		Threshold: r.similarityThreshold,
+	}
+
+	if args.Filter != nil {
+		if args.Filter.Song != nil {
+			filter.SongName = *args.Filter.Song
+		}
+		if args.Filter.Group != nil {
+			filter.GroupName = *args.Filter.Group
+		}
+		if args.Filter.Match != nil {
+			filter.Match = strings.ToLower(*args.Filter.Match)
+		}
+		if threshold := args.Filter.Threshold; threshold != nil {
+			if *threshold < 0 || *threshold > 1 {
+				log.Error("incorrect value of threshold", slog.Float64("threshold", *threshold))
+				return nil, errors.New("incorrect value of threshold")
+			}
+			filter.Threshold = *threshold
+		}
+
+		var err error
+		filter.ReleasedFrom, err = releasedBound(log, args.Filter.ReleasedFrom, "releasedFrom", false)
+		if err == nil {
+			filter.ReleasedTo, err = releasedBound(log, args.Filter.ReleasedTo, "releasedTo", true)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scored := filter.Match == model.MatchFuzzy && (filter.SongName != "" || filter.GroupName != "")
+
+	if args.Sort != nil {
+		seen := make(map[string]bool, len(*args.Sort))
+		for _, key := range *args.Sort {
+			field := librarySortFields[key.Field]
+			if seen[field] || (field == model.SortScore && !scored) {
+				log.Error("incorrect sort key", slog.String("field", key.Field))
+				return nil, errors.New("incorrect value of sort")
+			}
+			seen[field] = true
+			filter.Sort = append(filter.Sort, model.SortKey{Field: field, Desc: key.Desc != nil && *key.Desc})
+		}
+	} else if scored {
+		filter.Sort = []model.SortKey{{Field: model.SortScore, Desc: true}}
+	}
+
+	return filter, nil
+}
+
+// releasedBound reads a release date bound in any accepted format. The end
+// bound of a year or month covers the whole period.
+func releasedBound(log *slog.Logger, value *string, name string, end bool) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	date, precision, err := releasedate.Parse(*value)
+	if err != nil {
+		log.Error("failed converting of "+name+":", slerr.Err(err))
+		return nil, errors.New("incorrect value of " + name)
+	}
+	if end {
+		date = releasedate.End(date, precision)
+	}
+
+	return &date, nil
+}
+
+// libraryPage reads first/after for forward paging or last/before for
+// backward paging. Cursors are checked against the sort keys of the listing.
+func libraryPage(log *slog.Logger, args songsArgs, sort []model.SortKey) (*model.LibraryPage, error) {
+	if (args.First != nil || args.After != nil) && (args.Last != nil || args.Before != nil) {
+		log.Error("forward and backward paging requested together")
+		return nil, errors.New("first/after can't be used together with last/before")
+	}
+
+	page := &model.LibraryPage{Limit: 10}
+	limit, limitName := args.First, "first"
+	cursorStr, cursorName := args.After, "after"
+	if args.Last != nil || args.Before != nil {
+		page.Backward = true
+		limit, limitName = args.Last, "last"
+		cursorStr, cursorName = args.Before, "before"
+	}
+
+	if limit != nil {
+		if *limit < 0 {
+			log.Error("incorrect value of "+limitName, slog.Int("limit", int(*limit)))
+			return nil, errors.New("incorrect value of " + limitName)
+		}
+		page.Limit = int64(*limit)
+	}
+
+	if cursorStr != nil && *cursorStr != "" {
+		c, err := cursor.Decode(*cursorStr, sort)
+		if err != nil {
+			log.Error("failed decoding of "+cursorName+":", slerr.Err(err))
+			return nil, errors.New("incorrect value of " + cursorName)
+		}
+		page.Cursor = c
+	}
+
+	return page, nil
+}
@@ -0,0 +1,275 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type songsConnectionResolver struct {
+	graphImp   GraphImp
+	connection *model.SongsConnection
+}
+
+func (c *songsConnectionResolver) Edges() []*songEdgeResolver {
+	edges := make([]*songEdgeResolver, 0, len(c.connection.Edges))
+	for _, edge := range c.connection.Edges {
+		edges = append(edges, &songEdgeResolver{
+			edge: edge,
+			node: &songResolver{graphImp: c.graphImp, song: edge.Node},
+		})
+	}
+	return edges
+}
+
+func (c *songsConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{pageInfo: c.connection.PageInfo}
+}
+
+type songEdgeResolver struct {
+	edge *model.SongEdge
+	node *songResolver
+}
+
+func (e *songEdgeResolver) Node() *songResolver { return e.node }
+func (e *songEdgeResolver) Cursor() string      { return e.edge.Cursor }
+func (e *songEdgeResolver) Score() *float64     { return e.edge.Score }
+
+type pageInfoResolver struct {
+	pageInfo *model.LibraryPageInfo
+}
+
+func (p *pageInfoResolver) StartCursor() *string  { return p.pageInfo.StartCursor }
+func (p *pageInfoResolver) EndCursor() *string    { return p.pageInfo.EndCursor }
+func (p *pageInfoResolver) HasPreviousPage() bool { return p.pageInfo.HasPreviousPage }
+func (p *pageInfoResolver) HasNextPage() bool     { return p.pageInfo.HasNextPage }
+func (p *pageInfoResolver) TotalCount() int32     { return int32(p.pageInfo.TotalCount) }
+
+type songResolver struct {
+	graphImp GraphImp
+	song     *model.Song
+}
+
+func (s *songResolver) ID() graphql.ID { return graphql.ID(strconv.FormatInt(s.song.ID, 10)) }
+func (s *songResolver) Song() string   { return s.song.SongName }
+func (s *songResolver) Group() string  { return s.song.GroupName }
+func (s *songResolver) ReleaseDate() *string {
+	if s.song.ReleaseDate == "" {
+		return nil
+	}
+	return &s.song.ReleaseDate
+}
+
+// Detail goes through the loader of the query, which fetches the details of
+// the whole page at once.
+func (s *songResolver) Detail(ctx context.Context) (*songDetailResolver, error) {
+	state := stateFrom(ctx)
+
+	detail, err := state.details.load(s.song.ID)
+	if err != nil {
+		state.log.Error("failed to get song details", slerr.Err(err))
+		return nil, errors.New("failed to get song detail")
+	}
+	if detail == nil {
+		return nil, nil
+	}
+	return &songDetailResolver{detail: detail}, nil
+}
+
+type textArgs struct {
+	First    *int32
+	After    *int32
+	Types    *[]string
+	Collapse *bool
+}
+
+// Text pages the sections of the lyrics by position like the REST text of a
+// song. A song without lyrics has no couplets.
+func (s *songResolver) Text(ctx context.Context, args textArgs) (*textConnectionResolver, error) {
+	state := stateFrom(ctx)
+
+	first, after := 2, 0
+	if args.First != nil {
+		first = int(*args.First)
+	}
+	if args.After != nil {
+		after = int(*args.After)
+	}
+	if first < 0 {
+		return nil, errors.New("incorrect value of first")
+	}
+
+	var types map[string]bool
+	if args.Types != nil {
+		types = make(map[string]bool, len(*args.Types))
+		for _, sectionType := range *args.Types {
+			sectionType = strings.ToLower(strings.TrimSpace(sectionType))
+			if !slices.Contains(sections.Types, sectionType) {
+				return nil, errors.New("incorrect value of types")
+			}
+			types[sectionType] = true
+		}
+	}
+
+	lyricsSections, err := s.graphImp.GetSongSections(s.song)
+	if errors.Is(err, storage.ErrSongNotFound) {
+		return nil, errSongNotFound
+	}
+	if errors.Is(err, storage.ErrSongDetailNotFound) {
+		lyricsSections, err = nil, nil
+	}
+	if err != nil {
+		state.log.Error("failed getting text of song", slerr.Err(err))
+		return nil, errors.New("failed getting text of song")
+	}
+
+	if args.Collapse != nil && *args.Collapse {
+		lyricsSections = sections.Collapse(lyricsSections)
+	}
+
+	connection := &model.TextConnection{
+		Edges:    []*model.CoupletEdge{},
+		PageInfo: &model.TextPageInfo{EndCursor: &after},
+	}
+	for _, section := range lyricsSections {
+		if section.Position <= after || (types != nil && !types[section.Type]) {
+			continue
+		}
+		if len(connection.Edges) == first {
+			connection.PageInfo.HasNextPage = true
+			break
+		}
+		connection.PageInfo.EndCursor = &section.Position
+
+		edge := &model.CoupletEdge{
+			Cursor:  section.Position,
+			Section: section,
+		}
+		// A collapsed repeat has no lines of its own.
+		if len(section.Lines) > 0 {
+			node := strings.Join(section.Lines, "\n")
+			edge.Node = &node
+		}
+		connection.Edges = append(connection.Edges, edge)
+	}
+
+	return &textConnectionResolver{connection: connection}, nil
+}
+
+type songDetailResolver struct {
+	detail *model.SongDetail
+}
+
+func (d *songDetailResolver) ReleaseDate() string { return d.detail.ReleaseDate }
+func (d *songDetailResolver) Link() string        { return d.detail.Link }
+func (d *songDetailResolver) Text() string        { return d.detail.Text }
+func (d *songDetailResolver) ReleaseDatePrecision() *string {
+	if d.detail.ReleaseDatePrecision == "" {
+		return nil
+	}
+	return &d.detail.ReleaseDatePrecision
+}
+
+// Sources are listed in the order of the fields.
+func (d *songDetailResolver) Sources() []*detailSourceResolver {
+	sources := make([]*detailSourceResolver, 0, len(d.detail.Sources))
+	for field, provider := range d.detail.Sources {
+		sources = append(sources, &detailSourceResolver{field: field, provider: provider})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].field < sources[j].field })
+	return sources
+}
+
+type detailSourceResolver struct {
+	field    string
+	provider string
+}
+
+func (s *detailSourceResolver) Field() string    { return s.field }
+func (s *detailSourceResolver) Provider() string { return s.provider }
+
+type textConnectionResolver struct {
+	connection *model.TextConnection
+}
+
+func (c *textConnectionResolver) Edges() []*coupletEdgeResolver {
+	edges := make([]*coupletEdgeResolver, 0, len(c.connection.Edges))
+	for _, edge := range c.connection.Edges {
+		edges = append(edges, &coupletEdgeResolver{edge: edge})
+	}
+	return edges
+}
+
+func (c *textConnectionResolver) PageInfo() *textPageInfoResolver {
+	return &textPageInfoResolver{pageInfo: c.connection.PageInfo}
+}
+
+type coupletEdgeResolver struct {
+	edge *model.CoupletEdge
+}
+
+func (e *coupletEdgeResolver) Node() *string { return e.edge.Node }
+func (e *coupletEdgeResolver) Cursor() int32 { return int32(e.edge.Cursor) }
+func (e *coupletEdgeResolver) Section() *lyricsSectionResolver {
+	return &lyricsSectionResolver{section: e.edge.Section}
+}
+
+type textPageInfoResolver struct {
+	pageInfo *model.TextPageInfo
+}
+
+func (p *textPageInfoResolver) HasNextPage() bool { return p.pageInfo.HasNextPage }
+func (p *textPageInfoResolver) EndCursor() *int32 {
+	if p.pageInfo.EndCursor == nil {
+		return nil
+	}
+	endCursor := int32(*p.pageInfo.EndCursor)
+	return &endCursor
+}
+
+type lyricsSectionResolver struct {
+	section *model.LyricsSection
+}
+
+func (s *lyricsSectionResolver) Position() int32 { return int32(s.section.Position) }
+func (s *lyricsSectionResolver) Type() string    { return s.section.Type }
+func (s *lyricsSectionResolver) Lines() []string { return append([]string{}, s.section.Lines...) }
+func (s *lyricsSectionResolver) Label() *string {
+	if s.section.Label == "" {
+		return nil
+	}
+	return &s.section.Label
+}
+func (s *lyricsSectionResolver) RepeatOf() *int32 {
+	if s.section.RepeatOf == 0 {
+		return nil
+	}
+	repeatOf := int32(s.section.RepeatOf)
+	return &repeatOf
+}
+
+type addSongPayloadResolver struct {
+	song *songResolver
+	job  *model.Job
+}
+
+func (p *addSongPayloadResolver) Song() *songResolver { return p.song }
+func (p *addSongPayloadResolver) Job() *jobResolver   { return &jobResolver{job: p.job} }
+
+type jobResolver struct {
+	job *model.Job
+}
+
+func (j *jobResolver) ID() graphql.ID { return graphql.ID(strconv.FormatInt(j.job.ID, 10)) }
+func (j *jobResolver) State() string  { return j.job.State }
+func (j *jobResolver) URL() string    { return j.job.URL }
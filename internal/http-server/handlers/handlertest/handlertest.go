@@ -0,0 +1,117 @@
+// Package handlertest serves requests to the handlers against the memory
+// storage in their tests.
+package handlertest
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage/memory"
+)
+
+// Text is the text of the details Storage adds: a verse, a chorus and
+// another verse.
+const Text = "[Verse]\na\n\n[Chorus]\nb\n\n[Verse]\nc"
+
+// Case is a request to the handler and the status it must be answered with.
+// Check looks into the response of a successful request.
+type Case struct {
+	Name   string
+	Target string
+	Body   string
+	Status int
+	Check  func(t *testing.T, resp *model.Response)
+}
+
+// Logger discards the log of the handlers.
+func Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// Storage is a memory storage holding the songs. A song with a release date
+// gets details with that date and Text.
+func Storage(t *testing.T, songs ...*model.Song) *memory.Storage {
+	t.Helper()
+
+	songStorage := memory.NewStorage()
+	for _, song := range songs {
+		if err := songStorage.AddSongAndEnqueue(song, &model.Job{Kind: model.JobEnrichSong}); err != nil {
+			t.Fatal(err)
+		}
+		if song.ReleaseDate == "" {
+			continue
+		}
+		songDetail := &model.SongDetail{
+			ReleaseDate: song.ReleaseDate,
+			Link:        "https://example.com/" + song.SongName,
+			Text:        Text,
+		}
+		if err := songStorage.AddSongDetail(song, songDetail, &model.DetailChange{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return songStorage
+}
+
+// Run serves the cases in order with the handler routed at pattern, so that
+// a case sees the changes made by the ones before it.
+func Run(t *testing.T, method string, pattern string, handler http.HandlerFunc, cases []Case) {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Method(method, pattern, handler)
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(method, tc.Target, strings.NewReader(tc.Body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != tc.Status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.Status, w.Body)
+			}
+			if tc.Check == nil {
+				return
+			}
+
+			var resp model.Response
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			tc.Check(t, &resp)
+		})
+	}
+}
+
+// Published checks that the last event added to the storage is of the type
+// and about the song.
+func Published(songStorage *memory.Storage, eventType string, song *model.Song) func(t *testing.T, resp *model.Response) {
+	return func(t *testing.T, _ *model.Response) {
+		t.Helper()
+
+		id, err := songStorage.LastEventID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		events, err := songStorage.GetEvents(&model.EventFilter{}, id-1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("no event published")
+		}
+		event := events[0]
+		if event.Type != eventType || event.Song.SongName != song.SongName || event.Song.GroupName != song.GroupName {
+			t.Errorf("event %s of %s by %s, want %s of %s by %s", event.Type, event.Song.SongName, event.Song.GroupName,
+				eventType, song.SongName, song.GroupName)
+		}
+	}
+}
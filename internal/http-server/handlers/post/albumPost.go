@@ -0,0 +1,154 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type AlbumAddingImp interface {
+	AddAlbum(album *model.Album) error
+}
+
+type AlbumTrackAddingImp interface {
+	AddAlbumTrack(albumId int64, song *model.Song, position int) error
+}
+
+type TrackRequest struct {
+	model.Song
+	Position int `json:"position" validate:"gte=0"`
+}
+
+// @Summary      Add Album
+// @Tags         albums
+// @Description  Add a new album of a group to the library.
+// @Accept       json
+// @Produce      json
+// @Param        albumData  body      model.Album      true  "Album Data"
+// @Success      200        {object}  model.Response   "OK"
+// @Failure      400        {object}  model.Response   "Bad request"
+// @Failure      409        {object}  model.Response   "Album already exists"
+// @Failure      500        {object}  model.Response   "Failed to add album"
+// @Security     ApiKeyAuth
+// @Router       /albums [post]
+func AlbumPost(log *slog.Logger, albumAdding AlbumAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.albumPost.AlbumPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		album, errStr := decoder.RequestDecoderValJSON[model.Album](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		log.Info("request body decoded", slog.String("album: ", album.Title+":"+album.GroupName))
+
+		err := albumAdding.AddAlbum(album)
+		if errors.Is(err, storage.ErrAlbumAlreadyExists) {
+			log.Info("album already exist", slog.String("album: ", album.Title+":"+album.GroupName))
+
+			w.WriteHeader(http.StatusConflict) //409
+			render.JSON(w, r, model.StatusError("album already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add album", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add album"))
+			return
+		}
+
+		log.Info("album added", slog.Int64("id", album.ID))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Album:  album,
+		})
+	}
+}
+
+// @Summary      Add Album Track
+// @Tags         albums
+// @Description  Attach a song to the album tracklist at the given position, or at the end when position is 0.
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int64            true  "Album ID"
+// @Param        trackData  body      TrackRequest     true  "Track Data"
+// @Success      200        {object}  model.Response   "OK"
+// @Failure      400        {object}  model.Response   "Bad request"
+// @Failure      404        {object}  model.Response   "Album or song not found"
+// @Failure      409        {object}  model.Response   "Track already in album"
+// @Failure      500        {object}  model.Response   "Failed to add track"
+// @Security     ApiKeyAuth
+// @Router       /albums/{id}/tracks [post]
+func AlbumTrackPost(log *slog.Logger, trackAdding AlbumTrackAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.albumPost.AlbumTrackPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		albumId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		track, errStr := decoder.RequestDecoderValJSON[TrackRequest](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		log.Info("request body decoded", slog.String("song: ", track.SongName+":"+track.GroupName))
+
+		err := trackAdding.AddAlbumTrack(albumId, &track.Song, track.Position)
+		if errors.Is(err, storage.ErrAlbumNotFound) {
+			log.Info("album doesn't exist", slog.Int64("id", albumId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("album doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.String("song: ", track.SongName+":"+track.GroupName))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrTrackAlreadyExists) {
+			log.Info("track already exist", slog.String("song: ", track.SongName+":"+track.GroupName))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("track already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add track", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add track"))
+			return
+		}
+
+		log.Info("track added", slog.Int64("album", albumId))
+		render.JSON(w, r, model.OK())
+	}
+}
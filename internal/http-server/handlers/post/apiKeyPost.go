@@ -0,0 +1,139 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/apikey"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type APIKeyAddingImp interface {
+	AddAPIKey(key *model.APIKey) error
+}
+
+type APIKeyRotatingImp interface {
+	RotateAPIKey(id int64, hash string) (*model.APIKey, error)
+}
+
+// @Summary      Create API Key
+// @Tags         admin
+// @Description  Create an API key with the given role. The key itself is returned only once.
+// @Accept       json
+// @Produce      json
+// @Param        keyData  body      model.APIKey     true  "Key name and role (reader, editor, admin)"
+// @Success      200      {object}  model.Response   "OK"
+// @Failure      400      {object}  model.Response   "Bad request"
+// @Failure      500      {object}  model.Response   "Failed to create key"
+// @Security     ApiKeyAuth
+// @Router       /admin/keys [post]
+func APIKeyPost(log *slog.Logger, keyAdding APIKeyAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.apiKeyPost.APIKeyPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		key, errStr := decoder.RequestDecoderValJSON[model.APIKey](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		plain, hash, err := apikey.Generate()
+		if err != nil {
+			log.Error("failed to generate key", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to create key"))
+			return
+		}
+		key.Hash = hash
+
+		if err := keyAdding.AddAPIKey(key); err != nil {
+			log.Error("failed to add key", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to create key"))
+			return
+		}
+
+		log.Info("api key created", slog.Int64("id", key.ID), slog.String("role", key.Role))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			APIKey: key,
+			Key:    plain,
+		})
+	}
+}
+
+// @Summary      Rotate API Key
+// @Tags         admin
+// @Description  Replace the secret of an API key; the old secret stops working at once.
+// @Produce      json
+// @Param        id      path      int64            true  "Key ID"
+// @Success      200     {object}  model.Response   "OK"
+// @Failure      400     {object}  model.Response   "Bad request"
+// @Failure      404     {object}  model.Response   "Key not found or revoked"
+// @Failure      500     {object}  model.Response   "Failed to rotate key"
+// @Security     ApiKeyAuth
+// @Router       /admin/keys/{id}/rotate [post]
+func APIKeyRotate(log *slog.Logger, keyRotating APIKeyRotatingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.apiKeyPost.APIKeyRotate()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		plain, hash, err := apikey.Generate()
+		if err != nil {
+			log.Error("failed to generate key", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to rotate key"))
+			return
+		}
+
+		key, err := keyRotating.RotateAPIKey(id, hash)
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.Info("api key doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("api key doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to rotate key", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to rotate key"))
+			return
+		}
+
+		log.Info("api key rotated", slog.Int64("id", id))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			APIKey: key,
+			Key:    plain,
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type GroupAddingImp interface {
+	AddGroup(group *model.Group) error
+}
+
+// @Summary      Add Group
+// @Tags         groups
+// @Description  Add a new group to the library.
+// @Accept       json
+// @Produce      json
+// @Param        groupData  body      model.Group      true  "Group Data"
+// @Success      200        {object}  model.Response   "OK"
+// @Failure      400        {object}  model.Response   "Bad request"
+// @Failure      409        {object}  model.Response   "Group already exists"
+// @Failure      500        {object}  model.Response   "Failed to add group"
+// @Security     ApiKeyAuth
+// @Router       /groups [post]
+func GroupPost(log *slog.Logger, groupAdding GroupAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.groupPost.GroupPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		group, errStr := decoder.GroupDecoderValJSON(log, r)
+		if errStr != nil {
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := groupAdding.AddGroup(group)
+		if errors.Is(err, storage.ErrGroupAlreadyExists) {
+			log.Info("group already exist", slog.String("group: ", group.Name))
+
+			w.WriteHeader(http.StatusConflict) //409
+			render.JSON(w, r, model.StatusError("group already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add group", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add group"))
+			return
+		}
+
+		log.Info("group added", slog.Int64("id", group.ID))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Group:  group,
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type PlaylistAddingImp interface {
+	AddPlaylist(playlist *model.Playlist) error
+}
+
+type PlaylistEntryAddingImp interface {
+	AddPlaylistEntry(playlistId int64, song *model.Song, position int) error
+}
+
+type EntryRequest struct {
+	SongID    int64  `json:"songId"`
+	SongName  string `json:"song" validate:"required_without=SongID"`
+	GroupName string `json:"group" validate:"required_without=SongID"`
+	Position  int    `json:"position" validate:"gte=0"`
+}
+
+// @Summary      Add Playlist
+// @Tags         playlists
+// @Description  Create a new empty playlist. A playlist with entries is rejected; they are added one by one to the created playlist.
+// @Accept       json
+// @Produce      json
+// @Param        playlistData  body      model.Playlist   true  "Playlist Data"
+// @Success      200           {object}  model.Response   "OK"
+// @Failure      400           {object}  model.Response   "Bad request"
+// @Failure      409           {object}  model.Response   "Playlist already exists"
+// @Failure      500           {object}  model.Response   "Failed to add playlist"
+// @Security     ApiKeyAuth
+// @Router       /playlists [post]
+func PlaylistPost(log *slog.Logger, playlistAdding PlaylistAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.playlistPost.PlaylistPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		playlist, errStr := decoder.RequestDecoderValJSON[model.Playlist](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		log.Info("request body decoded", slog.String("playlist: ", playlist.Name))
+
+		if len(playlist.Entries) != 0 {
+			log.Error("playlist with entries", slog.Int("entries", len(playlist.Entries)))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("entries are added after the playlist is created"))
+			return
+		}
+
+		err := playlistAdding.AddPlaylist(playlist)
+		if errors.Is(err, storage.ErrPlaylistAlreadyExists) {
+			log.Info("playlist already exist", slog.String("playlist: ", playlist.Name))
+
+			w.WriteHeader(http.StatusConflict) //409
+			render.JSON(w, r, model.StatusError("playlist already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add playlist", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add playlist"))
+			return
+		}
+
+		log.Info("playlist added", slog.Int64("id", playlist.ID))
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Playlist: playlist,
+		})
+	}
+}
+
+// @Summary      Add Playlist Entry
+// @Tags         playlists
+// @Description  Insert a song, referenced by songId or by song and group, at the given position, or at the end when position is 0.
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int64            true  "Playlist ID"
+// @Param        entryData  body      EntryRequest     true  "Entry Data"
+// @Success      200        {object}  model.Response   "OK"
+// @Failure      400        {object}  model.Response   "Bad request"
+// @Failure      404        {object}  model.Response   "Playlist or song not found"
+// @Failure      500        {object}  model.Response   "Failed to add entry"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id}/entries [post]
+func PlaylistEntryPost(log *slog.Logger, entryAdding PlaylistEntryAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.playlistPost.PlaylistEntryPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		playlistId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		entry, errStr := decoder.RequestDecoderValJSON[EntryRequest](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		log.Info("request body decoded", slog.Any("entry: ", entry))
+
+		song := &model.Song{
+			ID:        entry.SongID,
+			SongName:  entry.SongName,
+			GroupName: entry.GroupName,
+		}
+
+		err := entryAdding.AddPlaylistEntry(playlistId, song, entry.Position)
+		if errors.Is(err, storage.ErrPlaylistNotFound) {
+			log.Info("playlist doesn't exist", slog.Int64("id", playlistId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Any("entry: ", entry))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add playlist entry", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add playlist entry"))
+			return
+		}
+
+		log.Info("playlist entry added", slog.Int64("playlist", playlistId))
+		render.JSON(w, r, model.OK())
+	}
+}
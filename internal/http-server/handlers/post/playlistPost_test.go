@@ -0,0 +1,19 @@
+package post
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+)
+
+func TestPlaylistPost(t *testing.T) {
+	handler := PlaylistPost(handlertest.Logger(), handlertest.Storage(t))
+
+	handlertest.Run(t, http.MethodPost, "/playlists", handler, []handlertest.Case{
+		{Name: "new playlist", Target: "/playlists", Body: `{"name": "Mix"}`, Status: http.StatusOK},
+		{Name: "same playlist again", Target: "/playlists", Body: `{"name": "Mix"}`, Status: http.StatusConflict},
+		{Name: "with entries", Target: "/playlists", Status: http.StatusBadRequest,
+			Body: `{"name": "Mix2", "entries": [{"song": {"songName": "Song1", "groupName": "Group1"}}]}`},
+	})
+}
@@ -0,0 +1,139 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type RevisionRestoringImp interface {
+	RestoreRevision(songId int64, revision int64, change *model.DetailChange) (*model.Revision, error)
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	event.SongPublisherImp
+	audit.RecorderImp
+}
+
+type RestoreRequest struct {
+	Message string `json:"message"`
+}
+
+// @Summary      Restore Song Revision
+// @Tags         songslibrary/revisions
+// @Description  Restore an old revision of the song detail; it is stored as a new revision.
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int64           true   "Song ID"
+// @Param        revision  path      int64           true   "Revision number"
+// @Param        request   body      RestoreRequest  false  "Optional message"
+// @Success      200       {object}  model.Response  "OK"
+// @Failure      400       {object}  model.Response  "Bad request"
+// @Failure      404       {object}  model.Response  "Song or revision not found"
+// @Failure      500       {object}  model.Response  "Failed to restore revision"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/revisions/{revision}/restore [post]
+func RevisionRestore(log *slog.Logger, revisionRestoringImp RevisionRestoringImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.revisionRestore.RevisionRestore()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		number, errStr := decoder.IdURLParam(log, r, "revision")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		message := ""
+		if r.ContentLength != 0 {
+			req, errStr := decoder.RequestDecoderValJSON[RestoreRequest](log, r)
+			if errStr != nil {
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError(*errStr))
+				return
+			}
+			message = req.Message
+		}
+
+		song, err := revisionRestoringImp.GetSong(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get song", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to restore revision"))
+			return
+		}
+		before, err := revisionRestoringImp.GetSongDetail(song)
+		if errors.Is(err, storage.ErrSongDetailNotFound) {
+			before, err = nil, nil
+		}
+		if err != nil {
+			log.Error("failed to get song detail", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to restore revision"))
+			return
+		}
+
+		revision, err := revisionRestoringImp.RestoreRevision(songId, number, &model.DetailChange{
+			Author:  auth.Name(r.Context()),
+			Message: message,
+		})
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrRevisionNotFound) {
+			log.Info("revision doesn't exist", slog.Int64("id", songId), slog.Int64("revision", number))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("revision doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to restore revision", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to restore revision"))
+			return
+		}
+
+		audit.Record(log, revisionRestoringImp, r, model.AuditRestoreRevision, song, before, revision.Detail)
+		event.Publish(log, revisionRestoringImp, r, model.EventDetailUpdated, song, revision.Detail)
+
+		log.Info("revision restored", slog.Int64("id", songId), slog.Int64("revision", number))
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Revision: revision,
+		})
+	}
+}
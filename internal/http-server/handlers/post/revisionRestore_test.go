@@ -0,0 +1,22 @@
+package post
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestRevisionRestore(t *testing.T) {
+	song := &model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"}
+	songStorage := handlertest.Storage(t, song)
+	handler := RevisionRestore(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPost, "/song/{id}/revisions/{revision}/restore", handler, []handlertest.Case{
+		{Name: "first revision", Target: "/song/1/revisions/1/restore", Status: http.StatusOK,
+			Check: handlertest.Published(songStorage, model.EventDetailUpdated, song)},
+		{Name: "unknown revision", Target: "/song/1/revisions/9/restore", Status: http.StatusNotFound},
+		{Name: "unknown song", Target: "/song/9/revisions/1/restore", Status: http.StatusNotFound},
+	})
+}
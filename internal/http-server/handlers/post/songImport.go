@@ -0,0 +1,182 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/lib/songimport"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// maxImportSize limits the body of an import request.
+const maxImportSize = 10 << 20
+
+type SongImportingImp interface {
+	ImportSongs(rows []*model.ImportRow, dryRun bool, change *model.DetailChange) ([]string, error)
+	audit.RecorderImp
+	event.PublisherImp
+}
+
+// @Summary      Import Songs
+// @Tags         songslibrary/song
+// @Description  Add songs with optional details in bulk from CSV (with a header row), a JSON array or NDJSON. Every row is validated and reported as created, duplicate or invalid; nothing is stored on a dry run.
+// @Accept       json
+// @Accept       text/csv
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        format  query     string  false  "Input format, taken from Content-Type by default"  Enums(csv, json, ndjson)
+// @Param        dryRun  query     bool    false  "Validate and report without storing"
+// @Param        songs   body      string  true   "Songs to import"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      413     {object}  model.Response  "Request body too large"
+// @Failure      500     {object}  model.Response  "Failed to import songs"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/import [post]
+func SongImport(log *slog.Logger, songImporting SongImportingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.songImport.SongImport()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = songimport.FormatOf(r.Header.Get("Content-Type"))
+		}
+		if format != songimport.FormatCSV && format != songimport.FormatJSON && format != songimport.FormatNDJSON {
+			log.Error("unknown import format", slog.String("format", format))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of format"))
+			return
+		}
+
+		var dryRun bool
+		if dryRunStr := r.URL.Query().Get("dryRun"); dryRunStr != "" {
+			var err error
+			dryRun, err = strconv.ParseBool(dryRunStr)
+			if err != nil {
+				log.Error("failed converting of dryRun:", slerr.Err(err))
+
+				w.WriteHeader(http.StatusBadRequest) // 400
+				render.JSON(w, r, model.StatusError("incorrect value of dryRun"))
+				return
+			}
+		}
+
+		defer r.Body.Close()
+		input, err := songimport.Parse(format, http.MaxBytesReader(w, r.Body, maxImportSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Error("import body too large", slerr.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge) // 413
+			render.JSON(w, r, model.StatusError("request body too large"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to parse import", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("bad request"))
+			return
+		}
+
+		report := &model.ImportReport{
+			DryRun: dryRun,
+			Rows:   make([]*model.ImportRowResult, len(input)),
+		}
+		var rows []*model.ImportRow
+		var results []*model.ImportRowResult
+		validate := validator.New()
+		for i, row := range input {
+			result := &model.ImportRowResult{Row: row.Line}
+			report.Rows[i] = result
+			if row.Err == nil {
+				result.Song = row.Data.Song.SongName
+				result.Group = row.Data.Song.GroupName
+			}
+
+			if err := validateImportRow(validate, row); err != nil {
+				result.Status = model.ImportInvalid
+				result.Error = err.Error()
+				report.Invalid++
+				continue
+			}
+			rows = append(rows, row.Data)
+			results = append(results, result)
+		}
+
+		if len(rows) > 0 {
+			statuses, err := songImporting.ImportSongs(rows, dryRun, &model.DetailChange{
+				Author:  auth.Name(r.Context()),
+				Message: "imported",
+			})
+			if err != nil {
+				log.Error("failed to import songs", slerr.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError) // 500
+				render.JSON(w, r, model.StatusError("failed to import songs"))
+				return
+			}
+
+			for i, status := range statuses {
+				results[i].Status = status
+				if status != model.ImportCreated {
+					report.Duplicates++
+					continue
+				}
+				report.Created++
+				if !dryRun {
+					audit.Record(log, songImporting, r, model.AuditAddSong, rows[i].Song, nil,
+						&audit.SongState{Song: rows[i].Song, Detail: rows[i].Detail})
+					event.Publish(log, songImporting, r, model.EventSongCreated, rows[i].Song, nil)
+				}
+			}
+		}
+
+		log.Info("songs imported", slog.Bool("dryRun", dryRun), slog.Int("created", report.Created),
+			slog.Int("duplicates", report.Duplicates), slog.Int("invalid", report.Invalid))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Import: report,
+		})
+	}
+}
+
+// validateImportRow checks the row with the same rules as the single song
+// endpoints and normalizes its release date and sections.
+func validateImportRow(validate *validator.Validate, row *songimport.Row) error {
+	if row.Err != nil {
+		return row.Err
+	}
+	if err := validate.Struct(row.Data.Song); err != nil {
+		return err
+	}
+	if row.Data.Detail == nil {
+		return nil
+	}
+	if err := validate.Struct(row.Data.Detail); err != nil {
+		return err
+	}
+	if err := releasedate.Normalize(row.Data.Detail); err != nil {
+		return errors.New("incorrect value of releaseDate")
+	}
+	sections.Normalize(row.Data.Detail)
+	return nil
+}
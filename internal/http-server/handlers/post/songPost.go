@@ -2,37 +2,42 @@ package post
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 
-	"github.com/nabishec/restapi/internal/clients"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
 	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
 	"github.com/nabishec/restapi/internal/model"
 	"github.com/nabishec/restapi/internal/storage"
 )
 
 type SongAddingImp interface {
-	AddSong(song *model.Song) error
-	AddSongDetail(song *model.Song, songDetail *model.SongDetail) error
+	AddSongAndEnqueue(song *model.Song, job *model.Job) error
+	audit.RecorderImp
+	event.PublisherImp
 }
 
 // @Summary      Add Song
 // @Tags         songslibrary/song
-// @Description  Add a new song to the library and fetch its details from an external API.
+// @Description  Add a new song to the library and queue a job fetching its details from an external API; the job is retried with a backoff while the API fails, and its state is reported at the URL in the Location header.
 // @Accept       json
 // @Produce      json
 // @Param        songData  body      model.Song       true  "Song Data"      Example: {"songName": "Song1", "groupName": "Group1", "releaseDate": "2022-01-01"}
-// @Success      200       {object}  model.Response    "OK"
+// @Success      202       {object}  model.Response    "Accepted"
+// @Header       202       {string}  Location          "URL of the job"
 // @Failure      400       {object}  model.Response       "Bad request"
 // @Failure      409       {object}  model.Response       "Song already exists"
 // @Failure      500       {object}  model.Response       "Failed to add song"
-// @Failure      207       {object}  model.Response       "Failed to get song details"
+// @Security     ApiKeyAuth
 // @Router       /songslibrary/song [post]
-func SongPost(log *slog.Logger, songAdding SongAddingImp) http.HandlerFunc {
+func SongPost(log *slog.Logger, songAdding SongAddingImp, maxAttempts int) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.post.addsong.SongPost()"
 
@@ -49,7 +54,14 @@ func SongPost(log *slog.Logger, songAdding SongAddingImp) http.HandlerFunc {
 			return
 		}
 
-		err := songAdding.AddSong(song)
+		job := &model.Job{
+			Kind:        model.JobEnrichSong,
+			MaxAttempts: maxAttempts,
+			Author:      auth.Name(r.Context()),
+			RequestID:   middleware.GetReqID(r.Context()),
+		}
+
+		err := songAdding.AddSongAndEnqueue(song, job)
 		if errors.Is(err, storage.ErrSongAlreadyExists) {
 			log.Info("song already exist", slog.String("song: ", song.SongName+
 				":"+song.GroupName))
@@ -67,25 +79,24 @@ func SongPost(log *slog.Logger, songAdding SongAddingImp) http.HandlerFunc {
 			return
 		}
 
-		log.Info("song added")
-		songDetail, err := clients.GetSongDetailsOfExternalApi(song)
-		if err != nil {
-			log.Error("failed to get song details", slerr.Err(err))
+		audit.Record(log, songAdding, r, model.AuditAddSong, song, nil, &audit.SongState{Song: song})
 
-			w.WriteHeader(http.StatusMultiStatus) // 207
-			render.JSON(w, r, model.StatusError("failed to get song details"))
-			return
-		}
-		err = songAdding.AddSongDetail(song, songDetail)
-		if err != nil {
-			log.Error("failed to add song details", slerr.Err(err))
+		job.Song = song
+		job.URL = jobURL(job.ID)
+		event.Publish(log, songAdding, r, model.EventSongCreated, song, job)
 
-			w.WriteHeader(http.StatusMultiStatus) // 207
-			render.JSON(w, r, model.StatusError("failed to add song details"))
-			return
-		}
-
-		log.Info("song added")
-		render.JSON(w, r, model.OK())
+		log.Info("song added, enrichment queued", slog.Int64("job", job.ID))
+		w.Header().Set("Location", job.URL)
+		w.WriteHeader(http.StatusAccepted) // 202
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Song:   song,
+			Job:    job,
+		})
 	}
 }
+
+// jobURL is the URL reporting the state of the job.
+func jobURL(id int64) string {
+	return fmt.Sprintf("/api/v1/jobs/%d", id)
+}
@@ -0,0 +1,54 @@
+package post
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestSongPost(t *testing.T) {
+	handler := SongPost(handlertest.Logger(), handlertest.Storage(t), 3)
+
+	added := func(t *testing.T, resp *model.Response) {
+		if resp.Song == nil || resp.Song.ID == 0 {
+			t.Errorf("response has no song: %+v", resp)
+		}
+	}
+	handlertest.Run(t, http.MethodPost, "/song", handler, []handlertest.Case{
+		{Name: "new song", Target: "/song", Body: `{"song": "Song1", "group": "Group1"}`, Status: http.StatusAccepted, Check: added},
+		{Name: "same song again", Target: "/song", Body: `{"song": "Song1", "group": "Group1"}`, Status: http.StatusConflict},
+		{Name: "same song of another group", Target: "/song", Body: `{"song": "Song1", "group": "Group2"}`, Status: http.StatusAccepted, Check: added},
+		{Name: "no group", Target: "/song", Body: `{"song": "Song2"}`, Status: http.StatusBadRequest},
+		{Name: "not json", Target: "/song", Body: `song`, Status: http.StatusBadRequest},
+	})
+}
+
+func TestSongPostQueuesJob(t *testing.T) {
+	handler := SongPost(handlertest.Logger(), handlertest.Storage(t), 3)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/song", strings.NewReader(`{"song": "Song1", "group": "Group1"}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+
+	var resp model.Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	job := resp.Job
+	if job == nil || job.Kind != model.JobEnrichSong || job.State != model.JobQueued || job.MaxAttempts != 3 {
+		t.Fatalf("job = %+v, want a queued enrichment with 3 attempts", job)
+	}
+	if job.SongID != resp.Song.ID {
+		t.Errorf("job is for song %d, want %d", job.SongID, resp.Song.ID)
+	}
+	if location := w.Header().Get("Location"); location == "" || location != job.URL {
+		t.Errorf("Location = %q, want the job URL %q", location, job.URL)
+	}
+}
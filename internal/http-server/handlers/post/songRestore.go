@@ -0,0 +1,88 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type SongRestoringImp interface {
+	RestoreSong(id int64) (*model.Song, error)
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	event.PublisherImp
+	audit.RecorderImp
+}
+
+// @Summary      Restore Song
+// @Tags         trash
+// @Description  Take a deleted song out of the trash together with its details.
+// @Produce      json
+// @Param        id      path      int64           true  "Song ID"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song not in trash"
+// @Failure      409     {object}  model.Response  "Song with the same name exists"
+// @Failure      500     {object}  model.Response  "Failed to restore song"
+// @Security     ApiKeyAuth
+// @Router       /trash/{id}/restore [post]
+func SongRestore(log *slog.Logger, songRestoringImp SongRestoringImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.songRestore.SongRestore()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		song, err := songRestoringImp.RestoreSong(id)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song isn't in trash", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song isn't in trash"))
+			return
+		}
+		if errors.Is(err, storage.ErrSongAlreadyExists) {
+			log.Info("song already exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("song already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to restore song", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to restore song"))
+			return
+		}
+
+		// The details come back with the song; a song without them has none to send.
+		songDetail, _ := songRestoringImp.GetSongDetail(song)
+		audit.Record(log, songRestoringImp, r, model.AuditRestoreSong, song, nil,
+			&audit.SongState{Song: song, Detail: songDetail})
+		event.Publish(log, songRestoringImp, r, model.EventSongCreated, song, songDetail)
+
+		log.Info("song restored", slog.Int64("id", id))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Song:   song,
+		})
+	}
+}
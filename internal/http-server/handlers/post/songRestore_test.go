@@ -0,0 +1,24 @@
+package post
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestSongRestore(t *testing.T) {
+	song := &model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"}
+	songStorage := handlertest.Storage(t, song)
+	if err := songStorage.DeleteSong(song, handlertest.Logger()); err != nil {
+		t.Fatal(err)
+	}
+	handler := SongRestore(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPost, "/trash/{id}/restore", handler, []handlertest.Case{
+		{Name: "deleted song", Target: "/trash/1/restore", Status: http.StatusOK,
+			Check: handlertest.Published(songStorage, model.EventSongCreated, song)},
+		{Name: "restored song", Target: "/trash/1/restore", Status: http.StatusNotFound},
+	})
+}
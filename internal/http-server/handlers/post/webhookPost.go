@@ -0,0 +1,131 @@
+package post
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/webhook"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type WebhookAddingImp interface {
+	AddWebhook(hook *model.Webhook) error
+}
+
+type DeliveryReplayingImp interface {
+	ReplayDelivery(id int64) (*model.WebhookDelivery, error)
+}
+
+// @Summary      Create Webhook
+// @Tags         admin
+// @Description  Subscribe a URL to events: song.created, song.deleted, detail.created, detail.updated and enrichment.failed. Every delivery is a POST of the event signed in X-Webhook-Signature with "sha256=" and the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>"; a secret is generated unless given, and is returned only once.
+// @Accept       json
+// @Produce      json
+// @Param        webhook  body      model.Webhook    true  "URL, event types and an optional secret of at least 16 characters"
+// @Success      200      {object}  model.Response   "OK"
+// @Failure      400      {object}  model.Response   "Bad request"
+// @Failure      500      {object}  model.Response   "Failed to create webhook"
+// @Security     ApiKeyAuth
+// @Router       /admin/webhooks [post]
+func WebhookPost(log *slog.Logger, webhookAdding WebhookAddingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.webhookPost.WebhookPost()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hook, errStr := decoder.RequestDecoderValJSON[model.Webhook](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		if hook.Secret == "" {
+			secret, err := webhook.GenerateSecret()
+			if err != nil {
+				log.Error("failed to generate secret", slerr.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError) // 500
+				render.JSON(w, r, model.StatusError("failed to create webhook"))
+				return
+			}
+			hook.Secret = secret
+		}
+
+		if err := webhookAdding.AddWebhook(hook); err != nil {
+			log.Error("failed to add webhook", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to create webhook"))
+			return
+		}
+
+		log.Info("webhook created", slog.Int64("id", hook.ID), slog.Any("events", hook.Events))
+		render.JSON(w, r, model.Response{
+			Status:  "OK",
+			Webhook: hook,
+		})
+	}
+}
+
+// @Summary      Replay Webhook Delivery
+// @Tags         admin
+// @Description  Send the event of a delivery to its webhook once more, as a new delivery queued at once.
+// @Produce      json
+// @Param        id      path      int64            true  "Delivery ID"
+// @Success      202     {object}  model.Response   "Queued"
+// @Failure      400     {object}  model.Response   "Bad request"
+// @Failure      404     {object}  model.Response   "Delivery not found"
+// @Failure      500     {object}  model.Response   "Failed to replay delivery"
+// @Security     ApiKeyAuth
+// @Router       /admin/webhooks/deliveries/{id}/replay [post]
+func DeliveryReplay(log *slog.Logger, deliveryReplaying DeliveryReplayingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.post.webhookPost.DeliveryReplay()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		delivery, err := deliveryReplaying.ReplayDelivery(id)
+		if errors.Is(err, storage.ErrDeliveryNotFound) {
+			log.Info("delivery doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("delivery doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to replay delivery", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to replay delivery"))
+			return
+		}
+
+		log.Info("delivery replayed", slog.Int64("id", id), slog.Int64("delivery", delivery.ID))
+		w.WriteHeader(http.StatusAccepted) // 202
+		render.JSON(w, r, model.Response{
+			Status:   "OK",
+			Delivery: delivery,
+		})
+	}
+}
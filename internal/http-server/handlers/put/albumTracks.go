@@ -0,0 +1,95 @@
+package put
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type AlbumTracksPutImp interface {
+	SetAlbumTracks(albumId int64, songs []*model.Song) error
+}
+
+type TracksRequest struct {
+	Tracks []*model.Song `json:"tracks" validate:"required,dive,required"`
+}
+
+// @Summary      Replace Album Tracks
+// @Tags         albums
+// @Description  Replace the album tracklist; tracks are stored in the order given.
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int64            true  "Album ID"
+// @Param        request  body      TracksRequest    true  "Ordered tracklist"
+// @Success      200      {object}  model.Response   "OK"
+// @Failure      400      {object}  model.Response   "Bad request"
+// @Failure      404      {object}  model.Response   "Album or song not found"
+// @Failure      409      {object}  model.Response   "Song listed twice"
+// @Failure      500      {object}  model.Response   "Failed to set tracks"
+// @Security     ApiKeyAuth
+// @Router       /albums/{id}/tracks [put]
+func AlbumTracks(log *slog.Logger, albumTracksPutImp AlbumTracksPutImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.albumTracks()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		albumId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		req, errStr := decoder.RequestDecoderValJSON[TracksRequest](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		log.Info("request body decoded", slog.Int("tracks", len(req.Tracks)))
+
+		err := albumTracksPutImp.SetAlbumTracks(albumId, req.Tracks)
+		if errors.Is(err, storage.ErrAlbumNotFound) {
+			log.Info("album doesn't exist", slog.Int64("id", albumId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("album doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slerr.Err(err))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrTrackAlreadyExists) {
+			log.Info("song listed twice", slerr.Err(err))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("song listed twice"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to set album tracks", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to set album tracks"))
+			return
+		}
+
+		log.Info("album tracks changed", slog.Int64("album", albumId))
+		render.JSON(w, r, model.OK())
+	}
+}
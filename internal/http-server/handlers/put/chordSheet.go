@@ -0,0 +1,149 @@
+package put
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
+	"github.com/nabishec/restapi/internal/lib/chordpro"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// maxChordSheetSize limits the size of an uploaded ChordPro file.
+const maxChordSheetSize = 1 << 20
+
+type ChordSheetPutImp interface {
+	PutChordSheet(songId int64, sheet *model.ChordSheet, change *model.DetailChange) error
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	event.SongPublisherImp
+	audit.RecorderImp
+}
+
+// @Summary      Upload Chord Sheet
+// @Tags         songslibrary/chords
+// @Description  Replace the chord sheet of a song with a ChordPro file. The text of the song detail becomes the chord-free lyrics.
+// @Accept       plain
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        sheet   body      string  true  "ChordPro file"  Example: "{title: Song1}\n[Am]Hello [G]world"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song not found"
+// @Failure      413     {object}  model.Response  "Request body too large"
+// @Failure      500     {object}  model.Response  "Failed to save chord sheet"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/chords [put]
+func ChordSheet(log *slog.Logger, chordSheetPut ChordSheetPutImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.chordSheet.ChordSheet()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxChordSheetSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Error("chord sheet too large", slerr.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge) // 413
+			render.JSON(w, r, model.StatusError("request body too large"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to read request body", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("bad request"))
+			return
+		}
+
+		sheet, err := chordpro.Parse(string(body))
+		if err != nil {
+			log.Error("failed to parse chordpro", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect chordpro file"))
+			return
+		}
+		sheet.SongID = songId
+
+		song, err := chordSheetPut.GetSong(songId)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get song", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to save chord sheet"))
+			return
+		}
+		before, err := chordSheetPut.GetSongDetail(song)
+		if errors.Is(err, storage.ErrSongDetailNotFound) {
+			before, err = nil, nil
+		}
+		if err != nil {
+			log.Error("failed to get song detail", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to save chord sheet"))
+			return
+		}
+
+		err = chordSheetPut.PutChordSheet(songId, sheet, &model.DetailChange{
+			Author:  auth.Name(r.Context()),
+			Message: "text from chord sheet",
+		})
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to save chord sheet", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to save chord sheet"))
+			return
+		}
+
+		// The sheet rewrites the text of the detail; the rest of it is kept.
+		after, err := chordSheetPut.GetSongDetail(song)
+		if err != nil {
+			log.Error("failed to get song detail", slerr.Err(err))
+		}
+		audit.Record(log, chordSheetPut, r, model.AuditPutChordSheet, song, before, after)
+		event.Publish(log, chordSheetPut, r, model.EventDetailUpdated, song, sheet)
+
+		log.Info("chord sheet saved", slog.Int64("id", songId), slog.Int("sections", len(sheet.Sections)))
+		render.JSON(w, r, model.Response{
+			Status:     "OK",
+			ChordSheet: sheet,
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package put
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestChordSheet(t *testing.T) {
+	song := &model.Song{SongName: "Song1", GroupName: "Group1", ReleaseDate: "2006-07-16"}
+	songStorage := handlertest.Storage(t, song)
+	handler := ChordSheet(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPut, "/song/{id}/chords", handler, []handlertest.Case{
+		{Name: "chord sheet", Target: "/song/1/chords", Body: "{title: Song1}\n[Am]Hello [G]world", Status: http.StatusOK,
+			Check: handlertest.Published(songStorage, model.EventDetailUpdated, song)},
+		{Name: "unknown song", Target: "/song/9/chords", Body: "[Am]Hello", Status: http.StatusNotFound},
+	})
+}
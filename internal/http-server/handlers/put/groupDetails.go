@@ -0,0 +1,87 @@
+package put
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type GroupPutImp interface {
+	PutGroup(group *model.Group) error
+}
+
+// @Summary      Update Group
+// @Tags         groups
+// @Description  Replace the details of a group.
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int64            true  "Group ID"
+// @Param        groupData  body      model.Group      true  "Group Data"
+// @Success      200        {object}  model.Response   "OK"
+// @Failure      400        {object}  model.Response   "Bad request"
+// @Failure      404        {object}  model.Response   "Group not found"
+// @Failure      409        {object}  model.Response   "Group name already taken"
+// @Failure      500        {object}  model.Response   "Failed to update group"
+// @Security     ApiKeyAuth
+// @Router       /groups/{id} [put]
+func GroupDetail(log *slog.Logger, groupPutImp GroupPutImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.groupDetail()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		group, errStr := decoder.GroupDecoderValJSON(log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		group.ID = id
+
+		err := groupPutImp.PutGroup(group)
+		if errors.Is(err, storage.ErrGroupNotFound) {
+			log.Info("group doesn't exist", slog.Int64("id", id))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("group doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrGroupAlreadyExists) {
+			log.Info("group name already taken", slog.String("group: ", group.Name))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("group already exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to update group", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to update group"))
+			return
+		}
+
+		log.Info("group changed", slog.Int64("id", id))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Group:  group,
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package put
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// maxLyricsSize limits the size of an uploaded LRC file.
+const maxLyricsSize = 1 << 20
+
+type LyricsPutImp interface {
+	PutLyrics(songId int64, lyrics *model.Lyrics) error
+	event.SongPublisherImp
+}
+
+// @Summary      Upload Song Lyrics
+// @Tags         songslibrary/lyrics
+// @Description  Replace the time-synced lyrics of a song with an LRC file; enhanced LRC word timestamps are kept. The song text is then derived from the lyrics.
+// @Accept       plain
+// @Produce      json
+// @Param        id      path      int64   true  "Song ID"
+// @Param        lyrics  body      string  true  "LRC file"  Example: "[00:12.00]Line one"
+// @Success      200     {object}  model.Response  "OK"
+// @Failure      400     {object}  model.Response  "Bad request"
+// @Failure      404     {object}  model.Response  "Song not found"
+// @Failure      413     {object}  model.Response  "Request body too large"
+// @Failure      500     {object}  model.Response  "Failed to save lyrics"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/lyrics [put]
+func Lyrics(log *slog.Logger, lyricsPut LyricsPutImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.lyrics.Lyrics()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLyricsSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Error("lyrics too large", slerr.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge) // 413
+			render.JSON(w, r, model.StatusError("request body too large"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to read request body", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("bad request"))
+			return
+		}
+
+		lyrics, err := lrc.Parse(string(body))
+		if err != nil {
+			log.Error("failed to parse lrc", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect lrc file"))
+			return
+		}
+		lyrics.SongID = songId
+
+		err = lyricsPut.PutLyrics(songId, lyrics)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to save lyrics", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to save lyrics"))
+			return
+		}
+
+		event.PublishById(log, lyricsPut, r, model.EventDetailUpdated, songId, lyrics)
+
+		log.Info("lyrics saved", slog.Int64("id", songId), slog.Int("lines", len(lyrics.Lines)))
+		render.JSON(w, r, model.Response{
+			Status: "OK",
+			Lyrics: lyrics,
+		})
+	}
+}
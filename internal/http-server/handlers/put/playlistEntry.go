@@ -0,0 +1,94 @@
+package put
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type PlaylistEntryMovingImp interface {
+	MovePlaylistEntry(playlistId int64, from int, to int) error
+}
+
+type MoveRequest struct {
+	Position int `json:"position" validate:"required,gt=0"`
+}
+
+// @Summary      Move Playlist Entry
+// @Tags         playlists
+// @Description  Move the entry at the given position to a new position.
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int64            true  "Playlist ID"
+// @Param        position  path      int              true  "Current position of the entry"
+// @Param        request   body      MoveRequest      true  "New position"
+// @Success      200       {object}  model.Response   "OK"
+// @Failure      400       {object}  model.Response   "Bad request"
+// @Failure      404       {object}  model.Response   "Playlist or entry not found"
+// @Failure      500       {object}  model.Response   "Failed to move entry"
+// @Security     ApiKeyAuth
+// @Router       /playlists/{id}/entries/{position} [put]
+func PlaylistEntry(log *slog.Logger, entryMovingImp PlaylistEntryMovingImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.playlistEntry()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		playlistId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		from, errStr := decoder.IdURLParam(log, r, "position")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		req, errStr := decoder.RequestDecoderValJSON[MoveRequest](log, r)
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		err := entryMovingImp.MovePlaylistEntry(playlistId, int(from), req.Position)
+		if errors.Is(err, storage.ErrPlaylistNotFound) {
+			log.Info("playlist doesn't exist", slog.Int64("id", playlistId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrEntryNotFound) {
+			log.Info("playlist entry doesn't exist", slog.Int64("position", from))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("playlist entry doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to move playlist entry", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to move playlist entry"))
+			return
+		}
+
+		log.Info("playlist entry moved", slog.Int64("playlist", playlistId))
+		render.JSON(w, r, model.OK())
+	}
+}
@@ -10,29 +10,41 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
+	"github.com/nabishec/restapi/internal/http-server/handlers/audit"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
 	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
 )
 
 type SongPutImp interface {
-	PutSongDetail(song *model.Song, songDetail *model.SongDetail) error
-	AddSongDetail(song *model.Song, songDetail *model.SongDetail) error
+	PutSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error
+	AddSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	audit.RecorderImp
+	event.PublisherImp
 }
 
 type Request struct {
 	SongData      model.Song       `json:"dataSong" validate:"required"`
 	NewSongDetail model.SongDetail `json:"songDetail" validate:"required"`
+	Message       string           `json:"message"`
 }
 
 // @Summary      Add Song Detail
 // @Tags         songslibrary/song
-// @Description  Add the details of a new song to the library.
+// @Description  Add the details of a new song to the library. Every change is kept as a revision with the optional message. The release date is accepted as 2006-07-16, 16.07.2006, 16/07/2006, 2006-07, 2006 and a few other common formats. The text is split into sections at markers like [Chorus] or Verse 1:, or the sections may be given instead of the text.
 // @Accept       json
 // @Produce      json
 // @Param        request body      Request true  "Request with song data and details" Example: {"dataSong": {"song": "Song1", "group": "Group1"}, "songDetail": {"releaseDate": "2022-01-01", "link": "http://example.com", "text": "This is a great song"}}
 // @Success      200         {object}  model.Response    "OK"
 // @Failure      400         {object}  model.Response       "Bad request"
+// @Failure      404         {object}  model.Response       "Song not found"
 // @Failure      500         {object}  model.Response       "Failed to add song detail"
+// @Security     ApiKeyAuth
 // @Router       /songslibrary/song [put]
 func SongDetail(log *slog.Logger, songPutImp SongPutImp) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +81,49 @@ func SongDetail(log *slog.Logger, songPutImp SongPutImp) http.HandlerFunc {
 			return
 		}
 
-		err = songPutImp.AddSongDetail(&req.SongData, &req.NewSongDetail)
+		if err := releasedate.Normalize(&req.NewSongDetail); err != nil {
+			log.Error("invalid release date", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("incorrect value of releaseDate"))
+			return
+		}
+
+		sections.Normalize(&req.NewSongDetail)
+		// Details entered by hand come from no provider.
+		req.NewSongDetail.Sources = nil
+
+		before, err := songPutImp.GetSongDetail(&req.SongData)
+		if errors.Is(err, storage.ErrSongDetailNotFound) {
+			before, err = nil, nil
+		}
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slerr.Err(err))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get song detail", slerr.Err(err))
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to add song detail"))
+			return
+		}
+
+		change := &model.DetailChange{
+			Author:  auth.Name(r.Context()),
+			Message: req.Message,
+		}
+
+		err = songPutImp.AddSongDetail(&req.SongData, &req.NewSongDetail, change)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slerr.Err(err))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
 		if err != nil {
 			log.Error("failed to add song detail", slerr.Err(err))
 			w.WriteHeader(http.StatusInternalServerError) // 500
@@ -78,6 +132,13 @@ func SongDetail(log *slog.Logger, songPutImp SongPutImp) http.HandlerFunc {
 
 		}
 
+		operation, eventType := model.AuditPutSongDetail, model.EventDetailUpdated
+		if before == nil {
+			operation, eventType = model.AuditAddSongDetail, model.EventDetailCreated
+		}
+		audit.Record(log, songPutImp, r, operation, &req.SongData, before, &req.NewSongDetail)
+		event.Publish(log, songPutImp, r, eventType, &req.SongData, &req.NewSongDetail)
+
 		log.Info("song detail changed")
 		render.JSON(w, r, model.OK())
 	}
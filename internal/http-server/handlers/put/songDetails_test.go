@@ -0,0 +1,82 @@
+package put
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/http-server/handlers/handlertest"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage/memory"
+)
+
+// detailBody is a request for the details of Song1 of Group1.
+func detailBody(songDetail string) string {
+	return fmt.Sprintf(`{"dataSong": {"song": "Song1", "group": "Group1"}, "songDetail": %s}`, songDetail)
+}
+
+// storedDetail checks the release date and the text the song is stored with.
+func storedDetail(songStorage *memory.Storage, releaseDate string, text string) func(t *testing.T, resp *model.Response) {
+	return func(t *testing.T, resp *model.Response) {
+		songDetail, err := songStorage.GetSongDetail(&model.Song{SongName: "Song1", GroupName: "Group1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if songDetail.ReleaseDate != releaseDate || songDetail.Text != text {
+			t.Errorf("stored %q %q, want %q %q", songDetail.ReleaseDate, songDetail.Text, releaseDate, text)
+		}
+	}
+}
+
+func TestSongDetail(t *testing.T) {
+	songStorage := handlertest.Storage(t, &model.Song{SongName: "Song1", GroupName: "Group1"})
+	handler := SongDetail(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPut, "/song", handler, []handlertest.Case{
+		{Name: "first details", Target: "/song", Status: http.StatusOK,
+			Body:  detailBody(`{"releaseDate": "2006-07-16", "link": "https://example.com", "text": "a\nb"}`),
+			Check: storedDetail(songStorage, "2006-07-16", "a\nb")},
+		{Name: "changed details", Target: "/song", Status: http.StatusOK,
+			Body:  detailBody(`{"releaseDate": "2007-01-02", "link": "https://example.com", "text": "c"}`),
+			Check: storedDetail(songStorage, "2007-01-02", "c")},
+		{Name: "unknown song", Target: "/song", Status: http.StatusNotFound,
+			Body: `{"dataSong": {"song": "Song2", "group": "Group1"},
+				"songDetail": {"releaseDate": "2006-07-16", "link": "https://example.com", "text": "a"}}`},
+		{Name: "no link", Target: "/song", Status: http.StatusBadRequest,
+			Body: detailBody(`{"releaseDate": "2006-07-16", "text": "a"}`)},
+		{Name: "not json", Target: "/song", Status: http.StatusBadRequest, Body: `song`},
+	})
+}
+
+func TestSongDetailReleaseDate(t *testing.T) {
+	songStorage := handlertest.Storage(t, &model.Song{SongName: "Song1", GroupName: "Group1"})
+	handler := SongDetail(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPut, "/song", handler, []handlertest.Case{
+		{Name: "dotted day", Target: "/song", Status: http.StatusOK,
+			Body:  detailBody(`{"releaseDate": "16.07.2006", "link": "https://example.com", "text": "a"}`),
+			Check: storedDetail(songStorage, "2006-07-16", "a")},
+		{Name: "year only", Target: "/song", Status: http.StatusOK,
+			Body:  detailBody(`{"releaseDate": "2006", "link": "https://example.com", "text": "a"}`),
+			Check: storedDetail(songStorage, "2006", "a")},
+		{Name: "unreadable date", Target: "/song", Status: http.StatusBadRequest,
+			Body: detailBody(`{"releaseDate": "soon", "link": "https://example.com", "text": "a"}`)},
+	})
+}
+
+func TestSongDetailSections(t *testing.T) {
+	songStorage := handlertest.Storage(t, &model.Song{SongName: "Song1", GroupName: "Group1"})
+	handler := SongDetail(handlertest.Logger(), songStorage)
+
+	handlertest.Run(t, http.MethodPut, "/song", handler, []handlertest.Case{
+		{Name: "sections instead of text", Target: "/song", Status: http.StatusOK,
+			Body: detailBody(`{"releaseDate": "2006-07-16", "link": "https://example.com",
+				"sections": [{"type": "verse", "lines": ["a"]}, {"type": "chorus", "lines": ["b"]}]}`),
+			Check: storedDetail(songStorage, "2006-07-16", "a\n\n[Chorus]\nb")},
+		{Name: "unknown section type", Target: "/song", Status: http.StatusBadRequest,
+			Body: detailBody(`{"releaseDate": "2006-07-16", "link": "https://example.com",
+				"sections": [{"type": "refrain", "lines": ["a"]}]}`)},
+		{Name: "neither text nor sections", Target: "/song", Status: http.StatusBadRequest,
+			Body: detailBody(`{"releaseDate": "2006-07-16", "link": "https://example.com"}`)},
+	})
+}
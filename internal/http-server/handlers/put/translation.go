@@ -0,0 +1,127 @@
+package put
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/http-server/handlers/decoder"
+	"github.com/nabishec/restapi/internal/http-server/handlers/event"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// maxTranslationSize limits the size of an uploaded translation.
+const maxTranslationSize = 1 << 20
+
+type TranslationPutImp interface {
+	PutTranslation(translation *model.Translation) error
+	event.SongPublisherImp
+}
+
+// @Summary      Upload Song Translation
+// @Tags         songslibrary/translations
+// @Description  Replace the translation of a song to a language given by its BCP-47 tag. The text must have a section for every section of the original lyrics, in the same order; section markers may be translated, e.g. [Припев], and a repeated section may be left as a bare marker.
+// @Accept       plain
+// @Produce      json
+// @Param        id           path      int64   true  "Song ID"
+// @Param        lang         path      string  true  "BCP-47 language tag"  Example: "ru"
+// @Param        translation  body      string  true  "Translated lyrics"
+// @Success      200          {object}  model.Response  "OK"
+// @Failure      400          {object}  model.Response  "Bad request"
+// @Failure      404          {object}  model.Response  "Song not found"
+// @Failure      409          {object}  model.Response  "Song has no text to translate"
+// @Failure      413          {object}  model.Response  "Request body too large"
+// @Failure      500          {object}  model.Response  "Failed to save translation"
+// @Security     ApiKeyAuth
+// @Router       /songslibrary/song/{id}/translations/{lang} [put]
+func Translation(log *slog.Logger, translationPut TranslationPutImp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.put.translation.Translation()"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		songId, errStr := decoder.IdURLParam(log, r, "id")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+		language, errStr := decoder.LanguageURLParam(log, r, "lang")
+		if errStr != nil {
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError(*errStr))
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTranslationSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Error("translation too large", slerr.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge) // 413
+			render.JSON(w, r, model.StatusError("request body too large"))
+			return
+		}
+		if err != nil || strings.TrimSpace(string(body)) == "" {
+			log.Error("failed to read request body", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("bad request"))
+			return
+		}
+
+		translation := &model.Translation{
+			SongID:   songId,
+			Language: language,
+			Text:     string(body),
+		}
+
+		err = translationPut.PutTranslation(translation)
+		if errors.Is(err, storage.ErrSongNotFound) {
+			log.Info("song doesn't exist", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusNotFound) // 404
+			render.JSON(w, r, model.StatusError("song doesn't exist"))
+			return
+		}
+		if errors.Is(err, storage.ErrSongDetailNotFound) {
+			log.Info("song has no text", slog.Int64("id", songId))
+
+			w.WriteHeader(http.StatusConflict) // 409
+			render.JSON(w, r, model.StatusError("song has no text to translate"))
+			return
+		}
+		if errors.Is(err, storage.ErrTranslationNotAligned) {
+			log.Info("translation not aligned", slerr.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest) // 400
+			render.JSON(w, r, model.StatusError("sections of the translation don't match the song"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to save translation", slerr.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError) // 500
+			render.JSON(w, r, model.StatusError("failed to save translation"))
+			return
+		}
+
+		event.PublishById(log, translationPut, r, model.EventDetailUpdated, songId, translation)
+
+		log.Info("translation saved", slog.Int64("id", songId), slog.String("language", language))
+		render.JSON(w, r, model.Response{
+			Status:      "OK",
+			Translation: translation,
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/nabishec/restapi/internal/lib/apikey"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type KeyResolverImp interface {
+	GetAPIKeyByHash(hash string) (*model.APIKey, error)
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	KeyID int64
+	Name  string
+	Role  string
+}
+
+type ctxKey int
+
+const (
+	identityKey ctxKey = iota
+	slotKey
+)
+
+// WithSlot prepares ctx to carry the identity set further down the chain, so
+// middleware that runs before New (such as the request logger) can read it
+// after the request is served.
+func WithSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, slotKey, new(*Identity))
+}
+
+func FromContext(ctx context.Context) *Identity {
+	if identity, ok := ctx.Value(identityKey).(*Identity); ok {
+		return identity
+	}
+	if slot, ok := ctx.Value(slotKey).(**Identity); ok {
+		return *slot
+	}
+	return nil
+}
+
+// Name returns the name of the caller, or "" when the request is anonymous.
+func Name(ctx context.Context) string {
+	if identity := FromContext(ctx); identity != nil {
+		return identity.Name
+	}
+	return ""
+}
+
+func withIdentity(ctx context.Context, identity *Identity) context.Context {
+	if slot, ok := ctx.Value(slotKey).(**Identity); ok {
+		*slot = identity
+	}
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// New authenticates requests by the key in the X-API-Key header or in an
+// "Authorization: Bearer" header. adminKey, when set, is accepted as an admin
+// key without a lookup so that the first keys can be created.
+func New(log *slog.Logger, keyResolver KeyResolverImp, adminKey string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		log.Info("auth middleware enabled")
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := requestKey(r)
+			if key == "" {
+				log.Info("request without api key", slog.String("request_id", middleware.GetReqID(r.Context())))
+
+				w.WriteHeader(http.StatusUnauthorized) // 401
+				render.JSON(w, r, model.StatusError("api key required"))
+				return
+			}
+
+			var identity *Identity
+			if adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+				identity = &Identity{Name: "bootstrap", Role: apikey.RoleAdmin}
+			} else {
+				stored, err := keyResolver.GetAPIKeyByHash(apikey.Hash(key))
+				if errors.Is(err, storage.ErrAPIKeyNotFound) {
+					log.Info("unknown api key", slog.String("request_id", middleware.GetReqID(r.Context())))
+
+					w.WriteHeader(http.StatusUnauthorized) // 401
+					render.JSON(w, r, model.StatusError("invalid api key"))
+					return
+				}
+				if err != nil {
+					log.Error("failed to check api key", slerr.Err(err))
+
+					w.WriteHeader(http.StatusInternalServerError) // 500
+					render.JSON(w, r, model.StatusError("failed to check api key"))
+					return
+				}
+				identity = &Identity{KeyID: stored.ID, Name: stored.Name, Role: stored.Role}
+			}
+
+			next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), identity)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// MethodPolicy lets readers read, editors create and change, and admins delete.
+func MethodPolicy(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return apikey.RoleReader
+	case http.MethodDelete:
+		return apikey.RoleAdmin
+	}
+	return apikey.RoleEditor
+}
+
+// Authorize rejects requests whose identity role is below the one policy requires.
+func Authorize(policy func(r *http.Request) string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			identity := FromContext(r.Context())
+			if identity == nil || !apikey.Allows(identity.Role, policy(r)) {
+				w.WriteHeader(http.StatusForbidden) // 403
+				render.JSON(w, r, model.StatusError("forbidden"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Require is Authorize with the same role for every request.
+func Require(role string) func(next http.Handler) http.Handler {
+	return Authorize(func(*http.Request) string { return role })
+}
+
+func requestKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	header := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/nabishec/restapi/internal/http-server/middleware/auth"
 )
 
 func New(log *slog.Logger) func(next http.Handler) http.Handler {
@@ -27,8 +28,16 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 			)
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
+			r = r.WithContext(auth.WithSlot(r.Context()))
+
 			t1 := time.Now()
 			defer func() {
+				if identity := auth.FromContext(r.Context()); identity != nil {
+					entry = entry.With(
+						slog.String("identity", identity.Name),
+						slog.String("role", identity.Role),
+					)
+				}
 				entry.Info("request completed",
 					slog.Int("status", ww.Status()),
 					slog.Int("bytes", ww.BytesWritten()),
@@ -0,0 +1,281 @@
+package chordpro
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var ErrInvalidChordPro = errors.New("invalid chordpro")
+
+var (
+	directive = regexp.MustCompile(`^\{\s*([A-Za-z_]+)\s*(?:[:\s]\s*(.*?))?\s*\}$`)
+	chordTag  = regexp.MustCompile(`\[([^\[\]]*)\]`)
+)
+
+// environments maps the ChordPro section directives, long and short, to the
+// section type and whether they start it.
+var environments = map[string]struct {
+	sectionType string
+	start       bool
+}{
+	"start_of_chorus": {"chorus", true}, "soc": {"chorus", true},
+	"end_of_chorus": {"chorus", false}, "eoc": {"chorus", false},
+	"start_of_verse": {"verse", true}, "sov": {"verse", true},
+	"end_of_verse": {"verse", false}, "eov": {"verse", false},
+	"start_of_bridge": {"bridge", true}, "sob": {"bridge", true},
+	"end_of_bridge": {"bridge", false}, "eob": {"bridge", false},
+	"start_of_tab": {"tab", true}, "sot": {"tab", true},
+	"end_of_tab": {"tab", false}, "eot": {"tab", false},
+	"start_of_grid": {"grid", true}, "sog": {"grid", true},
+	"end_of_grid": {"grid", false}, "eog": {"grid", false},
+}
+
+// metaDirectives are kept in ChordSheet.Meta.
+var metaDirectives = map[string]bool{
+	"album": true, "year": true, "capo": true, "tempo": true, "time": true,
+	"composer": true, "lyricist": true, "copyright": true, "duration": true,
+}
+
+// Parse reads a ChordPro sheet. Lines outside the section environments are
+// grouped into unmarked sections separated by blank lines.
+func Parse(text string) (*model.ChordSheet, error) {
+	const op = "internal.lib.chordpro.Parse()"
+
+	sheet := &model.ChordSheet{}
+	var section *model.ChordSection
+	inEnvironment := false
+
+	closeSection := func() {
+		if section != nil && len(section.Lines) > 0 {
+			for len(section.Lines) > 0 && isBlank(section.Lines[len(section.Lines)-1]) {
+				section.Lines = section.Lines[:len(section.Lines)-1]
+			}
+			sheet.Sections = append(sheet.Sections, section)
+		}
+		section = nil
+	}
+
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := directive.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			name, value := strings.ToLower(match[1]), strings.TrimSpace(match[2])
+
+			if env, ok := environments[name]; ok {
+				if env.start {
+					if inEnvironment {
+						return nil, fmt.Errorf("%s:%w: nested section on line %d", op, ErrInvalidChordPro, n+1)
+					}
+					closeSection()
+					section = &model.ChordSection{Type: env.sectionType, Label: value}
+					inEnvironment = true
+				} else {
+					if !inEnvironment || section.Type != env.sectionType {
+						return nil, fmt.Errorf("%s:%w: unexpected end of %s on line %d", op, ErrInvalidChordPro, env.sectionType, n+1)
+					}
+					// Keep an empty environment, e.g. a chorus only referred to by label.
+					if len(section.Lines) == 0 {
+						section.Lines = []*model.ChordLine{}
+						sheet.Sections = append(sheet.Sections, section)
+					}
+					closeSection()
+					inEnvironment = false
+				}
+				continue
+			}
+
+			switch name {
+			case "title", "t":
+				sheet.Title = value
+			case "subtitle", "st":
+				sheet.Subtitle = value
+			case "artist":
+				sheet.Artist = value
+			case "key":
+				sheet.Key = value
+			case "comment", "c", "comment_italic", "ci", "comment_box", "cb":
+				if section == nil {
+					section = &model.ChordSection{}
+				}
+				section.Lines = append(section.Lines, &model.ChordLine{Text: value, Comment: true})
+			default:
+				if metaDirectives[name] {
+					if sheet.Meta == nil {
+						sheet.Meta = make(map[string]string)
+					}
+					sheet.Meta[name] = value
+				}
+			}
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			if inEnvironment {
+				if section != nil && len(section.Lines) > 0 {
+					section.Lines = append(section.Lines, &model.ChordLine{})
+				}
+			} else {
+				closeSection()
+			}
+			continue
+		}
+
+		if section == nil {
+			section = &model.ChordSection{}
+		}
+		section.Lines = append(section.Lines, parseLine(line))
+	}
+
+	if inEnvironment {
+		return nil, fmt.Errorf("%s:%w: section %s isn't closed", op, ErrInvalidChordPro, section.Type)
+	}
+	closeSection()
+
+	if len(sheet.Sections) == 0 {
+		return nil, fmt.Errorf("%s:%w: no lyrics", op, ErrInvalidChordPro)
+	}
+
+	return sheet, nil
+}
+
+// parseLine takes the inline chords out of a lyric line.
+func parseLine(line string) *model.ChordLine {
+	result := &model.ChordLine{}
+
+	var text strings.Builder
+	last := 0
+	for _, match := range chordTag.FindAllStringSubmatchIndex(line, -1) {
+		text.WriteString(line[last:match[0]])
+		last = match[1]
+
+		chord := strings.TrimSpace(line[match[2]:match[3]])
+		if chord == "" {
+			continue
+		}
+		result.Chords = append(result.Chords, &model.ChordPosition{
+			Position: utf8.RuneCountInString(text.String()),
+			Chord:    chord,
+		})
+	}
+	text.WriteString(line[last:])
+
+	result.Text = text.String()
+	if strings.TrimSpace(result.Text) == "" {
+		result.Text = ""
+	}
+	return result
+}
+
+func isBlank(line *model.ChordLine) bool {
+	return line.Text == "" && len(line.Chords) == 0
+}
+
+// Format writes the sheet back as ChordPro.
+func Format(sheet *model.ChordSheet) string {
+	var b strings.Builder
+
+	for _, meta := range [][2]string{{"title", sheet.Title}, {"subtitle", sheet.Subtitle},
+		{"artist", sheet.Artist}, {"key", sheet.Key}} {
+		if meta[1] != "" {
+			fmt.Fprintf(&b, "{%s: %s}\n", meta[0], meta[1])
+		}
+	}
+	names := make([]string, 0, len(sheet.Meta))
+	for name := range sheet.Meta {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "{%s: %s}\n", name, sheet.Meta[name])
+	}
+
+	for _, section := range sheet.Sections {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if section.Type != "" {
+			b.WriteString("{start_of_" + section.Type)
+			if section.Label != "" {
+				b.WriteString(": " + section.Label)
+			}
+			b.WriteString("}\n")
+		}
+		for _, line := range section.Lines {
+			if line.Comment {
+				fmt.Fprintf(&b, "{comment: %s}\n", line.Text)
+				continue
+			}
+			b.WriteString(formatLine(line) + "\n")
+		}
+		if section.Type != "" {
+			b.WriteString("{end_of_" + section.Type + "}\n")
+		}
+	}
+
+	return b.String()
+}
+
+// formatLine puts the chords back into the line at their positions.
+func formatLine(line *model.ChordLine) string {
+	text := []rune(line.Text)
+
+	var b strings.Builder
+	last := 0
+	for _, chord := range line.Chords {
+		position := max(chord.Position, last)
+		for len(text) < position {
+			text = append(text, ' ')
+		}
+		b.WriteString(string(text[last:position]))
+		b.WriteString("[" + chord.Chord + "]")
+		last = position
+	}
+	b.WriteString(string(text[last:]))
+
+	return b.String()
+}
+
+// PlainText derives the chord-free lyrics: sections are separated by a blank
+// line, and chord-only and comment lines are left out.
+func PlainText(sheet *model.ChordSheet) string {
+	var blocks []string
+	for _, section := range sheet.Sections {
+		if section.Type == "tab" || section.Type == "grid" {
+			continue
+		}
+
+		var lines []string
+		for _, line := range section.Lines {
+			if line.Comment {
+				continue
+			}
+			text := strings.Join(strings.Fields(line.Text), " ")
+			if text == "" && len(line.Chords) > 0 {
+				continue
+			}
+			if text == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+				continue
+			}
+			lines = append(lines, text)
+		}
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
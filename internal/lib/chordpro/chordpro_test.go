@@ -0,0 +1,191 @@
+package chordpro
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		sections []string
+		err      bool
+	}{
+		{
+			name:     "chords over lyrics",
+			text:     "[Am]Hello [C/G]world\n[G]",
+			sections: []string{": Am@0 C/G@6 Hello world | G@0"},
+		},
+		{
+			name:     "unmarked blocks",
+			text:     "a\n\n\nb\nc",
+			sections: []string{": a", ": b | c"},
+		},
+		{
+			name:     "environments with labels",
+			text:     "{start_of_verse: Verse 1}\na\n\nb\n{end_of_verse}\n{soc}\nc\n{eoc}",
+			sections: []string{"verse Verse 1: a |  | b", "chorus: c"},
+		},
+		{
+			name:     "empty chorus is kept",
+			text:     "{start_of_chorus: Chorus}\n{end_of_chorus}\na",
+			sections: []string{"chorus Chorus:", ": a"},
+		},
+		{
+			name:     "comments and skipped lines",
+			text:     "# a note\n{c: Slowly}\n{unknown: x}\na",
+			sections: []string{": (Slowly) | a"},
+		},
+		{
+			name:     "characters counted as runes",
+			text:     "При[Am]вет",
+			sections: []string{": Am@3 Привет"},
+		},
+		{name: "nested sections", text: "{soc}\n{sov}\na\n{eov}\n{eoc}", err: true},
+		{name: "end of another section", text: "{soc}\na\n{eov}", err: true},
+		{name: "section not closed", text: "{soc}\na", err: true},
+		{name: "no lyrics", text: "{title: Song1}\n", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := Parse(tt.text)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidChordPro) {
+					t.Fatalf("error = %v, want %v", err, ErrInvalidChordPro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sections []string
+			for _, section := range sheet.Sections {
+				sections = append(sections, describe(section))
+			}
+			if !slices.Equal(sections, tt.sections) {
+				t.Errorf("sections = %q, want %q", sections, tt.sections)
+			}
+		})
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	sheet, err := Parse("{title: Song1}\n{st: Sub}\n{artist: Group1}\n{key: Am}\n{capo: 2}\n{tempo: 90}\na")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := fmt.Sprintf("%s|%s|%s|%s|%v", sheet.Title, sheet.Subtitle, sheet.Artist, sheet.Key, sheet.Meta)
+	if want := "Song1|Sub|Group1|Am|map[capo:2 tempo:90]"; got != want {
+		t.Errorf("meta = %q, want %q", got, want)
+	}
+}
+
+// TestFormat checks that a formatted sheet parses back to the same text.
+func TestFormat(t *testing.T) {
+	text := "{title: Song1}\n{key: G}\n{capo: 2}\n\n" +
+		"{start_of_verse: Verse 1}\n[G]Hello [D]world\n{comment: Softly}\n{end_of_verse}\n\n" +
+		"a    [C]\n"
+
+	sheet, err := Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(sheet); got != text {
+		t.Errorf("Format() = %q, want %q", got, text)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	sheet, err := Parse("{sov}\n[G]a  b\n[C]\n{c: x}\n{eov}\n{sot}\ne|---\n{eot}\nc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := PlainText(sheet), "a b\n\nc"; got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestTransposeChord(t *testing.T) {
+	tests := []struct {
+		chord     string
+		semitones int
+		want      string
+	}{
+		{chord: "C", semitones: 2, want: "D"},
+		{chord: "Am7/G", semitones: 3, want: "Cm7/A#"},
+		{chord: "Bb", semitones: 1, want: "B"},
+		{chord: "Eb", semitones: 1, want: "E"},
+		{chord: "Db", semitones: 2, want: "Eb"},
+		{chord: "C", semitones: -1, want: "B"},
+		{chord: "G#", semitones: 14, want: "A#"},
+		{chord: "N.C.", semitones: 2, want: "N.C."},
+	}
+
+	for _, tt := range tests {
+		if got := TransposeChord(tt.chord, tt.semitones); got != tt.want {
+			t.Errorf("TransposeChord(%q, %d) = %q, want %q", tt.chord, tt.semitones, got, tt.want)
+		}
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	sheet, err := Parse("{key: F}\n[F]a [C]b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		semitones int
+		key       string
+		section   string
+	}{
+		{semitones: 1, key: "F#", section: ": F#@0 C#@2 a b"},
+		{semitones: -2, key: "Eb", section: ": Eb@0 Bb@2 a b"},
+		{semitones: 12, key: "F", section: ": F@0 C@2 a b"},
+	}
+
+	for _, tt := range tests {
+		transposed := Transpose(sheet, tt.semitones)
+		if transposed.Key != tt.key || describe(transposed.Sections[0]) != tt.section {
+			t.Errorf("Transpose(%d) = %s %q, want %s %q", tt.semitones,
+				transposed.Key, describe(transposed.Sections[0]), tt.key, tt.section)
+		}
+	}
+	if got := describe(sheet.Sections[0]); got != ": F@0 C@2 a b" {
+		t.Errorf("original sheet changed: %q", got)
+	}
+}
+
+// describe writes a section as "type label: line | line", a line being its
+// chords at their positions followed by its text.
+func describe(section *model.ChordSection) string {
+	var lines []string
+	for _, line := range section.Lines {
+		var parts []string
+		for _, chord := range line.Chords {
+			parts = append(parts, fmt.Sprintf("%s@%d", chord.Chord, chord.Position))
+		}
+		text := line.Text
+		if line.Comment {
+			text = "(" + text + ")"
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	head := strings.TrimSpace(section.Type + " " + section.Label)
+	if len(lines) == 0 {
+		return head + ":"
+	}
+	return head + ": " + strings.Join(lines, " | ")
+}
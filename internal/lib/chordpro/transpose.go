@@ -0,0 +1,96 @@
+package chordpro
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var (
+	sharpNotes = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNotes  = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+)
+
+var noteIndex = map[string]int{
+	"C": 0, "B#": 0, "C#": 1, "Db": 1, "D": 2, "D#": 3, "Eb": 3, "E": 4, "Fb": 4,
+	"E#": 5, "F": 5, "F#": 6, "Gb": 6, "G": 7, "G#": 8, "Ab": 8, "A": 9,
+	"A#": 10, "Bb": 10, "B": 11, "Cb": 11,
+}
+
+var chordPattern = regexp.MustCompile(`^([A-G][#b]?)([^/]*)(?:/([A-G][#b]?))?$`)
+
+// flatKeys are the major and minor keys, by the index of their root, that
+// are written with flats.
+var flatKeys = map[bool]map[int]bool{
+	false: {1: true, 3: true, 5: true, 8: true, 10: true},
+	true:  {0: true, 2: true, 3: true, 5: true, 7: true, 10: true},
+}
+
+// Transpose returns a copy of the sheet with its chords and key moved by the
+// given number of semitones. The chords are spelled with sharps or flats as
+// the new key is; without a key each chord keeps its own spelling.
+func Transpose(sheet *model.ChordSheet, semitones int) *model.ChordSheet {
+	var notes []string
+	if match := chordPattern.FindStringSubmatch(sheet.Key); match != nil {
+		minor := strings.HasPrefix(match[2], "m") && !strings.HasPrefix(match[2], "maj")
+		notes = sharpNotes
+		if flatKeys[minor][move(noteIndex[match[1]], semitones)] {
+			notes = flatNotes
+		}
+	}
+
+	transposed := *sheet
+	transposed.Key = transposeChord(sheet.Key, semitones, notes)
+	transposed.Sections = make([]*model.ChordSection, 0, len(sheet.Sections))
+
+	for _, section := range sheet.Sections {
+		s := *section
+		s.Lines = make([]*model.ChordLine, 0, len(section.Lines))
+		for _, line := range section.Lines {
+			l := *line
+			l.Chords = nil
+			for _, chord := range line.Chords {
+				l.Chords = append(l.Chords, &model.ChordPosition{
+					Position: chord.Position,
+					Chord:    transposeChord(chord.Chord, semitones, notes),
+				})
+			}
+			s.Lines = append(s.Lines, &l)
+		}
+		transposed.Sections = append(transposed.Sections, &s)
+	}
+
+	return &transposed
+}
+
+// TransposeChord moves the root and the bass note of a chord like Am7/G by
+// the given number of semitones. A chord written with flats keeps flats.
+// Anything that isn't a chord, e.g. N.C., is returned as is.
+func TransposeChord(chord string, semitones int) string {
+	return transposeChord(chord, semitones, nil)
+}
+
+func transposeChord(chord string, semitones int, notes []string) string {
+	match := chordPattern.FindStringSubmatch(chord)
+	if match == nil {
+		return chord
+	}
+
+	if notes == nil {
+		notes = sharpNotes
+		if strings.HasSuffix(match[1], "b") || strings.HasSuffix(match[3], "b") {
+			notes = flatNotes
+		}
+	}
+
+	result := notes[move(noteIndex[match[1]], semitones)] + match[2]
+	if match[3] != "" {
+		result += "/" + notes[move(noteIndex[match[3]], semitones)]
+	}
+	return result
+}
+
+func move(index int, semitones int) int {
+	return ((index+semitones)%12 + 12) % 12
+}
@@ -0,0 +1,93 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const offsetPrefix = "offset:"
+
+// SortString describes sort keys the way the sort query parameter does,
+// e.g. "-score".
+func SortString(keys []model.SortKey) string {
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Desc {
+			fields = append(fields, "-"+key.Field)
+		} else {
+			fields = append(fields, key.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func Encode(c *model.LibraryCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor handed out for the given sort keys. A cursor issued
+// for another ordering is rejected, since its values can't be compared.
+func Decode(s string, keys []model.SortKey) (*model.LibraryCursor, error) {
+	const op = "internal.lib.cursor.Decode()"
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+
+	var c model.LibraryCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+	if c.Sort != SortString(keys) || len(c.Values) != len(keys) {
+		return nil, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+	for i, key := range keys {
+		var ok bool
+		if key.Field == model.SortScore {
+			_, ok = c.Values[i].(float64)
+		} else {
+			_, ok = c.Values[i].(string)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+		}
+	}
+
+	return &c, nil
+}
+
+// EncodeOffset makes an opaque cursor for connections paged by offset.
+func EncodeOffset(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offsetPrefix + strconv.FormatInt(offset, 10)))
+}
+
+func DecodeOffset(s string) (int64, error) {
+	const op = "internal.lib.cursor.DecodeOffset()"
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+
+	value, ok := strings.CutPrefix(string(data), offsetPrefix)
+	if !ok {
+		return 0, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%s:%w", op, ErrInvalidCursor)
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// MaxCells bounds the table of common subsequences, which takes a cell for
+// every pair of lines that differ between the texts.
+const MaxCells = 1 << 22
+
+var ErrTooLarge = errors.New("texts are too large to compare")
+
+// Lines compares two texts line by line using their longest common
+// subsequence. Line numbers are 1-based; OldLine is unset for inserted lines
+// and NewLine for deleted ones. The lines the texts start and end with in
+// common are matched first; the rest must fit in MaxCells.
+func Lines(oldText string, newText string) ([]*model.DiffLine, error) {
+	const op = "internal.lib.diff.Lines()"
+
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	n, m := len(a)-prefix-suffix, len(b)-prefix-suffix
+	if n > 0 && m > MaxCells/n {
+		return nil, fmt.Errorf("%s:%w: %d by %d lines", op, ErrTooLarge, n, m)
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[prefix+i:] and
+	// b[prefix+j:] within the changed lines.
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[prefix+i] == b[prefix+j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]*model.DiffLine, 0, max(len(a), len(b)))
+	for k := 0; k < prefix; k++ {
+		lines = append(lines, &model.DiffLine{Op: OpEqual, Text: a[k], OldLine: k + 1, NewLine: k + 1})
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[prefix+i] == b[prefix+j]:
+			lines = append(lines, &model.DiffLine{Op: OpEqual, Text: a[prefix+i], OldLine: prefix + i + 1, NewLine: prefix + j + 1})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, &model.DiffLine{Op: OpDelete, Text: a[prefix+i], OldLine: prefix + i + 1})
+			i++
+		default:
+			lines = append(lines, &model.DiffLine{Op: OpInsert, Text: b[prefix+j], NewLine: prefix + j + 1})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		oldLine, newLine := len(a)-suffix+k, len(b)-suffix+k
+		lines = append(lines, &model.DiffLine{Op: OpEqual, Text: a[oldLine], OldLine: oldLine + 1, NewLine: newLine + 1})
+	}
+
+	return lines, nil
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
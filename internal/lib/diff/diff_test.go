@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []string
+	}{
+		{name: "same texts", oldText: "a\nb", newText: "a\nb", want: []string{"=a 1 1", "=b 2 2"}},
+		{name: "both empty", oldText: "", newText: "", want: nil},
+		{name: "from empty", oldText: "", newText: "a", want: []string{"+a 0 1"}},
+		{name: "to empty", oldText: "a", newText: "", want: []string{"-a 1 0"}},
+		{
+			name:    "changed line in the middle",
+			oldText: "a\nb\nc",
+			newText: "a\nx\nc",
+			want:    []string{"=a 1 1", "-b 2 0", "+x 0 2", "=c 3 3"},
+		},
+		{
+			name:    "inserted and deleted lines",
+			oldText: "a\nb\nc\nd",
+			newText: "b\nc\ne\nd\nf",
+			want:    []string{"-a 1 0", "=b 2 1", "=c 3 2", "+e 0 3", "=d 4 4", "+f 0 5"},
+		},
+		{name: "windows line endings", oldText: "a\r\nb", newText: "a\nb", want: []string{"=a 1 1", "=b 2 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Lines(tt.oldText, tt.newText)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, line := range lines {
+				op := map[string]string{OpEqual: "=", OpInsert: "+", OpDelete: "-"}[line.Op]
+				got = append(got, fmt.Sprintf("%s%s %d %d", op, line.Text, line.OldLine, line.NewLine))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesTooLarge(t *testing.T) {
+	oldText := strings.Repeat("a\n", 3000)
+	newText := strings.Repeat("b\n", 3000)
+	if _, err := Lines(oldText, newText); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("error = %v, want %v", err, ErrTooLarge)
+	}
+
+	// Long texts that differ in a few lines are matched around them.
+	newText = oldText + "b\n" + oldText
+	if _, err := Lines(oldText+oldText, newText); err != nil {
+		t.Errorf("error = %v, want none", err)
+	}
+}
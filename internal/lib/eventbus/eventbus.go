@@ -0,0 +1,45 @@
+package eventbus
+
+import "sync"
+
+// Bus wakes its subscribers when events are added to the log. The wake-up
+// carries nothing: subscribers read the events from the log themselves, so
+// those added while one was busy are read with the next ones.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func New() *Bus {
+	return &Bus{subscribers: make(map[chan struct{}]struct{})}
+}
+
+// Subscribe returns the channel the subscriber is woken on and the function
+// to unsubscribe with.
+func (b *Bus) Subscribe() (<-chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	b.subscribers[wake] = struct{}{}
+	b.mu.Unlock()
+
+	return wake, func() {
+		b.mu.Lock()
+		delete(b.subscribers, wake)
+		b.mu.Unlock()
+	}
+}
+
+// Notify wakes all the subscribers. A subscriber not yet done with the last
+// wake-up isn't woken twice.
+func (b *Bus) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for wake := range b.subscribers {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatTSV    = "tsv"
+)
+
+// ContentTypes maps the export formats to the media types of their files.
+var ContentTypes = map[string]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatJSON:   "application/json",
+	FormatNDJSON: "application/x-ndjson",
+	FormatTSV:    "text/tab-separated-values; charset=utf-8",
+}
+
+// columns of CSV and TSV files are named the way the import reads them.
+var columns = []string{"id", "song", "group", "releaseDate", "releaseDatePrecision", "link", "text"}
+
+// Writer writes songs one by one; Close completes the file and must be
+// called even when no song was written.
+type Writer interface {
+	Write(song *model.ExportedSong) error
+	Close() error
+}
+
+// NewWriter returns nil for an unknown format.
+func NewWriter(format string, w io.Writer) Writer {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}
+	case FormatTSV:
+		return &tsvWriter{w: w}
+	case FormatJSON:
+		return &jsonWriter{w: w}
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}
+	}
+	return nil
+}
+
+func record(song *model.ExportedSong) []string {
+	return []string{strconv.FormatInt(song.ID, 10), song.SongName, song.GroupName,
+		song.ReleaseDate, song.ReleaseDatePrecision, song.Link, song.Text}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	started bool
+}
+
+func (c *csvWriter) header() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) Write(song *model.ExportedSong) error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	return c.w.Write(record(song))
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// tsvEscaper escapes the characters TSV can't hold in a field, the way
+// PostgreSQL COPY does in its text format.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+type tsvWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (t *tsvWriter) writeLine(fields []string) error {
+	for i, field := range fields {
+		fields[i] = tsvEscaper.Replace(field)
+	}
+	_, err := io.WriteString(t.w, strings.Join(fields, "\t")+"\n")
+	return err
+}
+
+func (t *tsvWriter) header() error {
+	if t.started {
+		return nil
+	}
+	t.started = true
+	return t.writeLine(append([]string(nil), columns...))
+}
+
+func (t *tsvWriter) Write(song *model.ExportedSong) error {
+	if err := t.header(); err != nil {
+		return err
+	}
+	return t.writeLine(record(song))
+}
+
+func (t *tsvWriter) Close() error {
+	return t.header()
+}
+
+// jsonWriter writes a JSON array without holding it in memory.
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) Write(song *model.ExportedSong) error {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if j.count == 0 {
+		prefix = "[\n"
+	}
+	j.count++
+
+	_, err = io.WriteString(j.w, prefix+string(data))
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	suffix := "\n]\n"
+	if j.count == 0 {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(j.w, suffix)
+	return err
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(song *model.ExportedSong) error {
+	return n.encoder.Encode(song)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package langtag
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidTag = errors.New("invalid language tag")
+
+var mul = language.Make("mul")
+
+// Canonical checks a BCP-47 language tag and writes it in its canonical
+// form, e.g. en-US for en_us.
+func Canonical(tag string) (string, error) {
+	const op = "internal.lib.langtag.Canonical()"
+
+	t, err := language.Parse(tag)
+	if err != nil || t == language.Und {
+		return "", fmt.Errorf("%s:%w: %q", op, ErrInvalidTag, tag)
+	}
+	return t.String(), nil
+}
+
+// Accepted lists the languages of an Accept-Language header from the most
+// preferred one; a malformed header accepts nothing.
+func Accepted(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	accepted := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		// The wildcard * comes as mul and asks for no language in particular.
+		if tag != language.Und && tag != mul {
+			accepted = append(accepted, tag.String())
+		}
+	}
+	return accepted
+}
+
+// Match picks the available language serving the wanted ones best, which
+// are given from the most preferred. A regional variant serves its language,
+// so ru-RU is picked for ru.
+func Match(available []string, wanted ...string) (string, bool) {
+	if len(available) == 0 || len(wanted) == 0 {
+		return "", false
+	}
+
+	supported := make([]language.Tag, 0, len(available))
+	for _, tag := range available {
+		supported = append(supported, language.Make(tag))
+	}
+	desired := make([]language.Tag, 0, len(wanted))
+	for _, tag := range wanted {
+		desired = append(desired, language.Make(tag))
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence < language.High {
+		return "", false
+	}
+	return available[index], true
+}
@@ -0,0 +1,274 @@
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var (
+	ErrInvalidLRC  = errors.New("invalid lrc")
+	ErrInvalidTime = errors.New("invalid time")
+)
+
+var (
+	timeTag = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	wordTag = regexp.MustCompile(`<(\d+):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+	metaTag = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+	timeArg = regexp.MustCompile(`^(\d+):(\d{1,2})(?:\.(\d{1,3}))?$`)
+)
+
+// tagOrder is the order metadata tags are written in; others follow by name.
+var tagOrder = []string{"ti", "ar", "al", "au", "by", "length", "re", "ve"}
+
+// ParseTime reads a playback offset written as mm:ss, mm:ss.xx or mm:ss.xxx
+// and returns it in milliseconds.
+func ParseTime(s string) (int64, error) {
+	const op = "internal.lib.lrc.ParseTime()"
+
+	match := timeArg.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, fmt.Errorf("%s:%w: %q", op, ErrInvalidTime, s)
+	}
+	ms, ok := millis(match[1], match[2], match[3])
+	if !ok {
+		return 0, fmt.Errorf("%s:%w: %q", op, ErrInvalidTime, s)
+	}
+	return ms, nil
+}
+
+// FormatTime writes milliseconds as mm:ss.xx, or as mm:ss.xxx when the time
+// isn't a whole number of hundredths.
+func FormatTime(ms int64) string {
+	minutes, rest := ms/60000, ms%60000
+	if rest%10 != 0 {
+		return fmt.Sprintf("%02d:%02d.%03d", minutes, rest/1000, rest%1000)
+	}
+	return fmt.Sprintf("%02d:%02d.%02d", minutes, rest/1000, rest%1000/10)
+}
+
+func millis(minutes string, seconds string, fraction string) (int64, bool) {
+	m, err := strconv.ParseInt(minutes, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	s, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil || s >= 60 {
+		return 0, false
+	}
+
+	ms := (m*60 + s) * 1000
+	if fraction != "" {
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		ms += f
+	}
+	return ms, true
+}
+
+// Parse reads an LRC file. A line may carry several timestamps, e.g. for a
+// repeated chorus, and enhanced LRC word timestamps like <00:12.50>. The
+// offset tag is applied to the times and dropped. Lines come out ordered by
+// time.
+func Parse(text string) (*model.Lyrics, error) {
+	const op = "internal.lib.lrc.Parse()"
+
+	lyrics := &model.Lyrics{Tags: make(map[string]string)}
+
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var times []int64
+		for {
+			match := timeTag.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			ms, ok := millis(match[1], match[2], match[3])
+			if !ok {
+				return nil, fmt.Errorf("%s:%w: bad timestamp on line %d", op, ErrInvalidLRC, n+1)
+			}
+			times = append(times, ms)
+			line = line[len(match[0]):]
+		}
+
+		if len(times) == 0 {
+			match := metaTag.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("%s:%w: line %d has no timestamp", op, ErrInvalidLRC, n+1)
+			}
+			lyrics.Tags[strings.ToLower(match[1])] = strings.TrimSpace(match[2])
+			continue
+		}
+
+		words, err := parseWords(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w: bad word timestamp on line %d", op, ErrInvalidLRC, n+1)
+		}
+		lineText := strings.Join(strings.Fields(wordTag.ReplaceAllString(line, "")), " ")
+		for _, ms := range times {
+			lyrics.Lines = append(lyrics.Lines, &model.LyricLine{
+				TimeMs: ms,
+				Text:   lineText,
+				Words:  copyWords(words),
+			})
+		}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return nil, fmt.Errorf("%s:%w: no timestamped lines", op, ErrInvalidLRC)
+	}
+
+	if offsetStr, ok := lyrics.Tags["offset"]; ok {
+		offset, err := strconv.ParseInt(strings.TrimPrefix(offsetStr, "+"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w: bad offset %q", op, ErrInvalidLRC, offsetStr)
+		}
+		// A positive offset makes the lyrics show up sooner.
+		for _, line := range lyrics.Lines {
+			line.TimeMs = max(line.TimeMs-offset, 0)
+			for _, word := range line.Words {
+				word.TimeMs = max(word.TimeMs-offset, 0)
+			}
+		}
+		delete(lyrics.Tags, "offset")
+	}
+
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].TimeMs < lyrics.Lines[j].TimeMs
+	})
+	Index(lyrics)
+
+	return lyrics, nil
+}
+
+// parseWords splits a line of enhanced LRC into its timed words. A trailing
+// timestamp without a word only marks the end of the line and is dropped.
+func parseWords(line string) ([]*model.LyricWord, error) {
+	matches := wordTag.FindAllStringSubmatchIndex(line, -1)
+
+	var words []*model.LyricWord
+	for i, match := range matches {
+		ms, ok := millis(line[match[2]:match[3]], line[match[4]:match[5]], submatch(line, match, 3))
+		if !ok {
+			return nil, ErrInvalidLRC
+		}
+
+		end := len(line)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		if word := strings.TrimSpace(line[match[1]:end]); word != "" {
+			words = append(words, &model.LyricWord{TimeMs: ms, Text: word})
+		}
+	}
+	return words, nil
+}
+
+func submatch(s string, match []int, n int) string {
+	if match[2*n] < 0 {
+		return ""
+	}
+	return s[match[2*n]:match[2*n+1]]
+}
+
+func copyWords(words []*model.LyricWord) []*model.LyricWord {
+	if words == nil {
+		return nil
+	}
+	copied := make([]*model.LyricWord, 0, len(words))
+	for _, word := range words {
+		w := *word
+		copied = append(copied, &w)
+	}
+	return copied
+}
+
+// Index numbers the lines in order and fills the written times.
+func Index(lyrics *model.Lyrics) {
+	for i, line := range lyrics.Lines {
+		line.Position = i + 1
+		line.Time = FormatTime(line.TimeMs)
+		for _, word := range line.Words {
+			word.Time = FormatTime(word.TimeMs)
+		}
+	}
+}
+
+// Format writes the lyrics back as an LRC file.
+func Format(lyrics *model.Lyrics) string {
+	var b strings.Builder
+
+	names := make([]string, 0, len(lyrics.Tags))
+	for name := range lyrics.Tags {
+		if !slices.Contains(tagOrder, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range append(slices.Clone(tagOrder), names...) {
+		if value, ok := lyrics.Tags[name]; ok {
+			fmt.Fprintf(&b, "[%s:%s]\n", name, value)
+		}
+	}
+
+	for _, line := range lyrics.Lines {
+		fmt.Fprintf(&b, "[%s]", FormatTime(line.TimeMs))
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		}
+		for i, word := range line.Words {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, "<%s>%s", FormatTime(word.TimeMs), word.Text)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// PlainText derives the lyrics text from the lines. Lines without text, the
+// instrumental breaks, separate couplets with a blank line.
+func PlainText(lyrics *model.Lyrics) string {
+	var lines []string
+	for _, line := range lyrics.Lines {
+		if line.Text == "" {
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+			continue
+		}
+		lines = append(lines, line.Text)
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LineAt returns the line being sung at the playback offset, or nil before
+// the first line.
+func LineAt(lyrics *model.Lyrics, ms int64) *model.LyricLine {
+	i := sort.Search(len(lyrics.Lines), func(i int) bool {
+		return lyrics.Lines[i].TimeMs > ms
+	})
+	if i == 0 {
+		return nil
+	}
+	return lyrics.Lines[i-1]
+}
@@ -0,0 +1,170 @@
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		err  bool
+	}{
+		{in: "01:02", want: 62000},
+		{in: "01:02.5", want: 62500},
+		{in: "01:02.50", want: 62500},
+		{in: "01:02.123", want: 62123},
+		{in: "125:00", want: 7500000},
+		{in: "01:60", err: true},
+		{in: "01:02:03", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTime(tt.in)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidTime) {
+					t.Fatalf("ParseTime(%q) error = %v, want %v", tt.in, err, ErrInvalidTime)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseTime(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := map[int64]string{
+		0:       "00:00.00",
+		62500:   "01:02.50",
+		62123:   "01:02.123",
+		7500000: "125:00.00",
+	}
+
+	for ms, want := range tests {
+		if got := FormatTime(ms); got != want {
+			t.Errorf("FormatTime(%d) = %q, want %q", ms, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		lines []string
+		words []string
+		tags  map[string]string
+		err   bool
+	}{
+		{
+			name:  "lines in time order",
+			text:  "[ti:Song1]\n[ar:Group1]\n\n[00:05.00]b\n[00:01.00]a",
+			lines: []string{"00:01.00 a", "00:05.00 b"},
+			tags:  map[string]string{"ti": "Song1", "ar": "Group1"},
+		},
+		{
+			name:  "repeated line",
+			text:  "[00:01.00][00:10.00]chorus\n[00:05.00]verse",
+			lines: []string{"00:01.00 chorus", "00:05.00 verse", "00:10.00 chorus"},
+		},
+		{
+			name:  "offset shifts the times",
+			text:  "[offset:+500]\n[00:00.20]a\n[00:02.00]b",
+			lines: []string{"00:00.00 a", "00:01.50 b"},
+		},
+		{
+			name:  "enhanced words",
+			text:  "[00:01.00]<00:01.00>one <00:01.50>two<00:02.00>",
+			lines: []string{"00:01.00 one two"},
+			words: []string{"00:01.00 one", "00:01.50 two"},
+		},
+		{name: "line without a timestamp", text: "[00:01.00]a\nb", err: true},
+		{name: "bad timestamp", text: "[00:61.00]a", err: true},
+		{name: "bad offset", text: "[offset:soon]\n[00:01.00]a", err: true},
+		{name: "tags only", text: "[ti:Song1]", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lyrics, err := Parse(tt.text)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidLRC) {
+					t.Fatalf("error = %v, want %v", err, ErrInvalidLRC)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var lines, words []string
+			for i, line := range lyrics.Lines {
+				if line.Position != i+1 {
+					t.Errorf("line %d has position %d", i+1, line.Position)
+				}
+				lines = append(lines, fmt.Sprintf("%s %s", line.Time, line.Text))
+				for _, word := range line.Words {
+					words = append(words, fmt.Sprintf("%s %s", word.Time, word.Text))
+				}
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Errorf("lines = %q, want %q", lines, tt.lines)
+			}
+			if !slices.Equal(words, tt.words) {
+				t.Errorf("words = %q, want %q", words, tt.words)
+			}
+			if tt.tags != nil && !maps.Equal(lyrics.Tags, tt.tags) {
+				t.Errorf("tags = %v, want %v", lyrics.Tags, tt.tags)
+			}
+		})
+	}
+}
+
+// TestFormat checks that formatted lyrics parse back to the same file.
+func TestFormat(t *testing.T) {
+	text := "[ti:Song1]\n[ar:Group1]\n[key:value]\n[00:01.00]a\n[00:02.50]<00:02.50>b <00:03.125>c\n[00:04.00]\n"
+
+	lyrics, err := Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(lyrics); got != text {
+		t.Errorf("Format() = %q, want %q", got, text)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	lyrics, err := Parse("[00:01.00]a\n[00:05.00]b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int64]string{0: "", 1000: "a", 4999: "a", 5000: "b", 60000: "b"}
+	for ms, want := range tests {
+		var got string
+		if line := LineAt(lyrics, ms); line != nil {
+			got = line.Text
+		}
+		if got != want {
+			t.Errorf("LineAt(%d) = %q, want %q", ms, got, want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	lyrics, err := Parse("[00:01.00]a\n[00:02.00]b\n[00:03.00]\n[00:04.00]\n[00:05.00]c\n[00:06.00]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := PlainText(lyrics), "a\nb\n\nc"; got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// m3uLine keeps a value on its line of the M3U file, so that a name or a link
+// can't add lines of its own.
+var m3uLine = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// WriteM3U writes the playlist as extended M3U. Entries without a link are
+// skipped because M3U has no way to list a track without its location.
+func WriteM3U(w io.Writer, playlist *model.Playlist) error {
+	var b strings.Builder
+
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", m3uLine.Replace(playlist.Name))
+	for _, entry := range playlist.Entries {
+		if entry.Link == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "#EXTINF:-1,%s - %s\n", m3uLine.Replace(entry.Song.GroupName), m3uLine.Replace(entry.Song.SongName))
+		b.WriteString(m3uLine.Replace(entry.Link) + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type xspfPlaylist struct {
+	XMLName   xml.Name    `xml:"playlist"`
+	Version   string      `xml:"version,attr"`
+	Namespace string      `xml:"xmlns,attr"`
+	Title     string      `xml:"title"`
+	TrackList []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location,omitempty"`
+	Title    string `xml:"title"`
+	Creator  string `xml:"creator"`
+	TrackNum int    `xml:"trackNum"`
+}
+
+// WriteXSPF writes the playlist as XSPF version 1.
+func WriteXSPF(w io.Writer, playlist *model.Playlist) error {
+	doc := xspfPlaylist{
+		Version:   "1",
+		Namespace: "http://xspf.org/ns/0/",
+		Title:     playlist.Name,
+		TrackList: make([]xspfTrack, 0, len(playlist.Entries)),
+	}
+	for _, entry := range playlist.Entries {
+		doc.TrackList = append(doc.TrackList, xspfTrack{
+			Location: entry.Link,
+			Title:    entry.Song.SongName,
+			Creator:  entry.Song.GroupName,
+			TrackNum: entry.Position,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,46 @@
+package playlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestWriteM3U(t *testing.T) {
+	tests := []struct {
+		name     string
+		playlist *model.Playlist
+		want     string
+	}{
+		{
+			name: "entries with links",
+			playlist: &model.Playlist{Name: "Mix", Entries: []*model.PlaylistEntry{
+				{Position: 1, Song: &model.Song{SongName: "Song1", GroupName: "Group1"}, Link: "https://example.com/1"},
+				{Position: 2, Song: &model.Song{SongName: "Song2", GroupName: "Group1"}},
+			}},
+			want: "#EXTM3U\n#PLAYLIST:Mix\n#EXTINF:-1,Group1 - Song1\nhttps://example.com/1\n",
+		},
+		{
+			name: "line breaks in names and links",
+			playlist: &model.Playlist{Name: "Mix\n#EXTINF:-1,x", Entries: []*model.PlaylistEntry{
+				{Position: 1, Song: &model.Song{SongName: "Song1\r\nfile:///etc/passwd", GroupName: "Group1\r"},
+					Link: "https://example.com/1\nfile:///etc/passwd"},
+			}},
+			want: "#EXTM3U\n#PLAYLIST:Mix #EXTINF:-1,x\n#EXTINF:-1,Group1  - Song1 file:///etc/passwd\n" +
+				"https://example.com/1 file:///etc/passwd\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteM3U(&b, tt.playlist); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteM3U() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
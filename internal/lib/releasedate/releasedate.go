@@ -0,0 +1,114 @@
+package releasedate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+var ErrInvalidDate = errors.New("invalid release date")
+
+const (
+	PrecisionYear  = "year"
+	PrecisionMonth = "month"
+	PrecisionDay   = "day"
+)
+
+var precisionLevel = map[string]int{
+	PrecisionYear:  1,
+	PrecisionMonth: 2,
+	PrecisionDay:   3,
+}
+
+type layout struct {
+	layout    string
+	precision string
+}
+
+// layouts are the input formats accepted for a release date, the ones sent by
+// the external API included.
+var layouts = []layout{
+	{"2006-01-02", PrecisionDay},
+	{time.RFC3339, PrecisionDay},
+	{"02.01.2006", PrecisionDay},
+	{"2.1.2006", PrecisionDay},
+	{"02/01/2006", PrecisionDay},
+	{"2006/01/02", PrecisionDay},
+	{"2 January 2006", PrecisionDay},
+	{"2 Jan 2006", PrecisionDay},
+	{"January 2, 2006", PrecisionDay},
+	{"Jan 2, 2006", PrecisionDay},
+	{"2006-01", PrecisionMonth},
+	{"01.2006", PrecisionMonth},
+	{"01/2006", PrecisionMonth},
+	{"January 2006", PrecisionMonth},
+	{"Jan 2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// Parse reads a release date in any of the accepted formats and reports how
+// precise it is. Dates of month or year precision fall on the first day of
+// their period.
+func Parse(s string) (time.Time, string, error) {
+	const op = "internal.lib.releasedate.Parse()"
+
+	s = strings.TrimSpace(s)
+	for _, l := range layouts {
+		t, err := time.Parse(l.layout, s)
+		if err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), l.precision, nil
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("%s:%w: %q", op, ErrInvalidDate, s)
+}
+
+// Format writes the date as ISO-8601 reduced to the given precision:
+// 2006, 2006-07 or 2006-07-16.
+func Format(t time.Time, precision string) string {
+	switch precision {
+	case PrecisionYear:
+		return t.Format("2006")
+	case PrecisionMonth:
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+// End returns the last day of the period the date stands for, so that a
+// releasedTo of 2006 includes the whole year.
+func End(t time.Time, precision string) time.Time {
+	switch precision {
+	case PrecisionYear:
+		return t.AddDate(1, 0, -1)
+	case PrecisionMonth:
+		return t.AddDate(0, 1, -1)
+	}
+	return t
+}
+
+// Normalize rewrites the release date of the detail in ISO-8601 and fills its
+// precision. A precision given by the client may only make the date coarser.
+func Normalize(songDetail *model.SongDetail) error {
+	const op = "internal.lib.releasedate.Normalize()"
+
+	t, precision, err := Parse(songDetail.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	if songDetail.ReleaseDatePrecision != "" {
+		level, ok := precisionLevel[songDetail.ReleaseDatePrecision]
+		if !ok || level > precisionLevel[precision] {
+			return fmt.Errorf("%s:%w: precision %q", op, ErrInvalidDate, songDetail.ReleaseDatePrecision)
+		}
+		precision = songDetail.ReleaseDatePrecision
+	}
+
+	songDetail.ReleaseDate = Format(t, precision)
+	songDetail.ReleaseDatePrecision = precision
+	return nil
+}
@@ -0,0 +1,114 @@
+package releasedate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      string
+		precision string
+		err       bool
+	}{
+		{in: "2006-07-16", want: "2006-07-16", precision: PrecisionDay},
+		{in: " 2006-07-16 ", want: "2006-07-16", precision: PrecisionDay},
+		{in: "2006-07-16T23:30:00+03:00", want: "2006-07-16", precision: PrecisionDay},
+		{in: "16.07.2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "6.7.2006", want: "2006-07-06", precision: PrecisionDay},
+		{in: "16/07/2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "2006/07/16", want: "2006-07-16", precision: PrecisionDay},
+		{in: "16 July 2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "16 Jul 2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "July 16, 2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "Jul 16, 2006", want: "2006-07-16", precision: PrecisionDay},
+		{in: "2006-07", want: "2006-07-01", precision: PrecisionMonth},
+		{in: "07.2006", want: "2006-07-01", precision: PrecisionMonth},
+		{in: "07/2006", want: "2006-07-01", precision: PrecisionMonth},
+		{in: "July 2006", want: "2006-07-01", precision: PrecisionMonth},
+		{in: "Jul 2006", want: "2006-07-01", precision: PrecisionMonth},
+		{in: "2006", want: "2006-01-01", precision: PrecisionYear},
+		{in: "31.02.2006", err: true},
+		{in: "2006-13", err: true},
+		{in: "someday", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, precision, err := Parse(tt.in)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.in, err, ErrInvalidDate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if got.Format("2006-01-02") != tt.want || precision != tt.precision {
+				t.Errorf("Parse(%q) = %s %s, want %s %s", tt.in, got.Format("2006-01-02"), precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      string
+		precision string
+		want      string
+		err       bool
+	}{
+		{name: "parsed precision", date: "16.07.2006", want: "2006-07-16"},
+		{name: "coarser precision", date: "16.07.2006", precision: PrecisionMonth, want: "2006-07"},
+		{name: "same precision", date: "2006", precision: PrecisionYear, want: "2006"},
+		{name: "finer precision", date: "2006", precision: PrecisionDay, err: true},
+		{name: "unknown precision", date: "2006", precision: "decade", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songDetail := &model.SongDetail{ReleaseDate: tt.date, ReleaseDatePrecision: tt.precision}
+			err := Normalize(songDetail)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Fatalf("error = %v, want %v", err, ErrInvalidDate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if songDetail.ReleaseDate != tt.want {
+				t.Errorf("ReleaseDate = %q, want %q", songDetail.ReleaseDate, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		start     string
+		precision string
+		want      string
+	}{
+		{start: "2024", precision: PrecisionYear, want: "2024-12-31"},
+		{start: "2024-02", precision: PrecisionMonth, want: "2024-02-29"},
+		{start: "2024-02-10", precision: PrecisionDay, want: "2024-02-10"},
+	}
+
+	for _, tt := range tests {
+		start, _, err := Parse(tt.start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := End(start, tt.precision).Format("2006-01-02"); got != tt.want {
+			t.Errorf("End(%s) = %s, want %s", tt.start, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package sections
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// Types are the known section types.
+var Types = []string{
+	model.SectionVerse, model.SectionChorus, model.SectionPreChorus, model.SectionBridge,
+	model.SectionIntro, model.SectionOutro, model.SectionHook, model.SectionInstrumental, model.SectionOther,
+}
+
+// typeWords maps the first word of a section marker to the section type.
+var typeWords = map[string]string{
+	"verse":        model.SectionVerse,
+	"couplet":      model.SectionVerse,
+	"chorus":       model.SectionChorus,
+	"refrain":      model.SectionChorus,
+	"pre-chorus":   model.SectionPreChorus,
+	"prechorus":    model.SectionPreChorus,
+	"bridge":       model.SectionBridge,
+	"intro":        model.SectionIntro,
+	"outro":        model.SectionOutro,
+	"coda":         model.SectionOutro,
+	"hook":         model.SectionHook,
+	"interlude":    model.SectionInstrumental,
+	"instrumental": model.SectionInstrumental,
+	"solo":         model.SectionInstrumental,
+	"part":         model.SectionOther,
+}
+
+var (
+	bracketMarker = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+	colonMarker   = regexp.MustCompile(`^([^:]{1,30}):$`)
+	typeWord      = regexp.MustCompile(`^(?i)(pre[- ]?chorus|[a-z]+)`)
+)
+
+// Parse splits lyrics into sections. A section starts at a marker line such
+// as "[Chorus]", "[Verse 2: Artist]" or "Verse 1:", or after a blank line.
+// Unmarked sections are verses. A marker without lines repeats the earlier
+// section with the same label, or the type; so does a section with the same
+// lines as an earlier one of its type.
+func Parse(text string) []*model.LyricsSection {
+	return parse(text, false)
+}
+
+// parse is Parse; with anyBracket every line in brackets is a marker, the
+// ones naming no known type start a section of type other.
+func parse(text string, anyBracket bool) []*model.LyricsSection {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var sections []*model.LyricsSection
+	var current *model.LyricsSection
+	closeSection := func() {
+		if current != nil {
+			sections = append(sections, current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if sectionType, label, ok := marker(line, anyBracket); ok {
+			closeSection()
+			current = &model.LyricsSection{Type: sectionType, Label: label, Lines: []string{}}
+			continue
+		}
+
+		if line == "" {
+			if current != nil && len(current.Lines) > 0 {
+				closeSection()
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &model.LyricsSection{Type: model.SectionVerse, Lines: []string{}}
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	closeSection()
+
+	return Resolve(sections)
+}
+
+// marker recognizes a section marker line by the type word it starts with.
+func marker(line string, anyBracket bool) (string, string, bool) {
+	match := bracketMarker.FindStringSubmatch(line)
+	bracketed := match != nil
+	if match == nil {
+		match = colonMarker.FindStringSubmatch(line)
+	}
+	if match == nil {
+		return "", "", false
+	}
+
+	label := strings.TrimSpace(match[1])
+	sectionType := TypeOf(label)
+	if sectionType == "" {
+		if !bracketed || !anyBracket {
+			return "", "", false
+		}
+		sectionType = model.SectionOther
+	}
+
+	// Render writes a label naming another type after the type, e.g.
+	// "[Bridge: Spoken]"; the label is what follows it.
+	if typeName, rest, ok := strings.Cut(label, ": "); ok && typeName == title(sectionType) {
+		if rest = strings.TrimSpace(rest); rest != "" && TypeOf(rest) != sectionType {
+			label = rest
+		}
+	}
+	return sectionType, label, true
+}
+
+// TypeOf finds the section type a label starts with, e.g. chorus for
+// "Chorus 2"; it is empty when the label names no known type.
+func TypeOf(label string) string {
+	word := strings.ToLower(typeWord.FindString(strings.TrimSpace(label)))
+	word = strings.ReplaceAll(word, " ", "-")
+	return typeWords[word]
+}
+
+// Resolve numbers the sections and links the repeated ones to their first
+// occurrence. A section without lines takes the lines it repeats.
+func Resolve(sections []*model.LyricsSection) []*model.LyricsSection {
+	for i, section := range sections {
+		section.Position = i + 1
+		section.RepeatOf = 0
+		if section.Lines == nil {
+			section.Lines = []string{}
+		}
+
+		for _, earlier := range sections[:i] {
+			if earlier.RepeatOf != 0 || len(earlier.Lines) == 0 {
+				continue
+			}
+			if len(section.Lines) == 0 {
+				if repeats(section, earlier) {
+					section.RepeatOf = earlier.Position
+					section.Lines = slices.Clone(earlier.Lines)
+					break
+				}
+				continue
+			}
+			if slices.Equal(earlier.Lines, section.Lines) && (earlier.Type == section.Type || unmarked(section)) {
+				// An unmarked block is a repeat of whatever it repeats.
+				section.Type, section.Label = earlier.Type, earlier.Label
+				section.RepeatOf = earlier.Position
+				break
+			}
+		}
+	}
+
+	// A bare marker with nothing to repeat has only its label.
+	return sections
+}
+
+func unmarked(section *model.LyricsSection) bool {
+	return section.Type == model.SectionVerse && section.Label == ""
+}
+
+// repeats tells whether a bare marker refers to the earlier section: by the
+// same label, or by the type when the marker is just the type name.
+func repeats(marker *model.LyricsSection, earlier *model.LyricsSection) bool {
+	if marker.Label != "" && strings.EqualFold(marker.Label, earlier.Label) {
+		return true
+	}
+	return marker.Type == earlier.Type && (marker.Label == "" || typeWords[strings.ToLower(marker.Label)] != "")
+}
+
+// Render writes the sections as text with a marker before each of them but
+// the unlabeled verses, so that Parse reads them back. Repeats are written in
+// full, since a bare marker might be read as a repeat of another section.
+func Render(sections []*model.LyricsSection) string {
+	blocks := make([]string, 0, len(sections))
+	for _, section := range sections {
+		if unmarked(section) && len(section.Lines) > 0 {
+			blocks = append(blocks, strings.Join(section.Lines, "\n"))
+			continue
+		}
+
+		header := section.Label
+		if header == "" {
+			header = title(section.Type)
+		} else if TypeOf(header) != section.Type {
+			header = title(section.Type) + ": " + header
+		}
+
+		block := "[" + header + "]"
+		if len(section.Lines) > 0 {
+			block += "\n" + strings.Join(section.Lines, "\n")
+		}
+		blocks = append(blocks, block)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func title(sectionType string) string {
+	if sectionType == model.SectionOther {
+		return "Part"
+	}
+	return strings.ToUpper(sectionType[:1]) + sectionType[1:]
+}
+
+// Normalize writes the text of the detail from the sections supplied by the
+// client; without them the detail is left as is. The sections are brought to
+// the form Parse reads back from the text: lines are trimmed and blank ones
+// dropped, and a section written with a marker of its type alone gets that
+// marker as its label.
+func Normalize(songDetail *model.SongDetail) {
+	if len(songDetail.Sections) == 0 {
+		return
+	}
+
+	for _, section := range songDetail.Sections {
+		section.Label = strings.TrimSpace(section.Label)
+		lines := make([]string, 0, len(section.Lines))
+		for _, line := range section.Lines {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		section.Lines = lines
+	}
+
+	sections := Resolve(songDetail.Sections)
+	for i, section := range sections {
+		// An unlabeled verse goes without a marker unless it follows a marker
+		// without lines, which would take its lines.
+		bare := section.Type == model.SectionVerse && len(section.Lines) > 0 &&
+			(i == 0 || len(sections[i-1].Lines) > 0)
+		if section.Label == "" && !bare {
+			section.Label = title(section.Type)
+		}
+	}
+
+	songDetail.Sections = sections
+	songDetail.Text = Render(sections)
+}
+
+// FromDetail returns the sections supplied with the detail, or parses them
+// from its text.
+func FromDetail(songDetail *model.SongDetail) []*model.LyricsSection {
+	if len(songDetail.Sections) > 0 {
+		return songDetail.Sections
+	}
+	return Parse(songDetail.Text)
+}
+
+// Collapse leaves the lines out of the repeated sections, which then only
+// point at their first occurrence.
+func Collapse(sections []*model.LyricsSection) []*model.LyricsSection {
+	collapsed := make([]*model.LyricsSection, 0, len(sections))
+	for _, section := range sections {
+		if section.RepeatOf != 0 {
+			s := *section
+			s.Lines = []string{}
+			section = &s
+		}
+		collapsed = append(collapsed, section)
+	}
+	return collapsed
+}
@@ -0,0 +1,175 @@
+package sections
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func section(sectionType string, label string, lines ...string) *model.LyricsSection {
+	return &model.LyricsSection{Type: sectionType, Label: label, Lines: lines}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []*model.LyricsSection
+	}{
+		{
+			name: "unmarked blocks are verses",
+			text: "a\nb\n\nc",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionVerse, Lines: []string{"a", "b"}},
+				{Position: 2, Type: model.SectionVerse, Lines: []string{"c"}},
+			},
+		},
+		{
+			name: "bracket and colon markers",
+			text: "[Verse 2: Artist]\na\n\nChorus:\nb",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionVerse, Label: "Verse 2: Artist", Lines: []string{"a"}},
+				{Position: 2, Type: model.SectionChorus, Label: "Chorus", Lines: []string{"b"}},
+			},
+		},
+		{
+			name: "bare marker repeats the section of its type",
+			text: "[Chorus]\na\n\n[Verse]\nb\n\n[Chorus]",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}},
+				{Position: 2, Type: model.SectionVerse, Label: "Verse", Lines: []string{"b"}},
+				{Position: 3, Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}, RepeatOf: 1},
+			},
+		},
+		{
+			name: "unmarked block repeating a chorus is the chorus",
+			text: "[Chorus]\na\n\nb\n\na",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}},
+				{Position: 2, Type: model.SectionVerse, Lines: []string{"b"}},
+				{Position: 3, Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}, RepeatOf: 1},
+			},
+		},
+		{
+			name: "part marker with a label",
+			text: "[Part: Spoken]\na",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionOther, Label: "Spoken", Lines: []string{"a"}},
+			},
+		},
+		{
+			name: "unknown bracket line is a lyric",
+			text: "[Laughs]\na",
+			want: []*model.LyricsSection{
+				{Position: 1, Type: model.SectionVerse, Lines: []string{"[Laughs]", "a"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSections(t, Parse(tt.text), tt.want)
+		})
+	}
+}
+
+// TestNormalizeRoundTrip checks that the text written from the sections of a
+// client is read back as the same sections, since revisions keep the text
+// only.
+func TestNormalizeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []*model.LyricsSection
+	}{
+		{
+			name: "other sections",
+			sections: []*model.LyricsSection{
+				section(model.SectionOther, "Spoken", "a"),
+				section(model.SectionOther, "", "b"),
+			},
+		},
+		{
+			name: "verses and a repeated chorus",
+			sections: []*model.LyricsSection{
+				section(model.SectionVerse, "", "a", "b"),
+				section(model.SectionChorus, "", "c"),
+				section(model.SectionVerse, "", "d"),
+				section(model.SectionChorus, "", "c"),
+			},
+		},
+		{
+			name: "repeat of the second of two verses",
+			sections: []*model.LyricsSection{
+				section(model.SectionVerse, "", "a"),
+				section(model.SectionVerse, "", "b"),
+				section(model.SectionVerse, "", "b"),
+			},
+		},
+		{
+			name: "marker without lines before a verse",
+			sections: []*model.LyricsSection{
+				section(model.SectionBridge, ""),
+				section(model.SectionVerse, "", "a"),
+			},
+		},
+		{
+			name: "labels naming the type or another one",
+			sections: []*model.LyricsSection{
+				section(model.SectionChorus, "Chorus 2", "a"),
+				section(model.SectionBridge, "Chorus", "b"),
+				section(model.SectionVerse, "Artist", "c"),
+				section(model.SectionPreChorus, "", "d"),
+				section(model.SectionInstrumental, ""),
+			},
+		},
+		{
+			name: "blank and padded lines",
+			sections: []*model.LyricsSection{
+				section(model.SectionIntro, " Intro ", "  a  ", "", "b"),
+				section(model.SectionOutro, "", "c"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songDetail := &model.SongDetail{Sections: tt.sections}
+			Normalize(songDetail)
+
+			assertSections(t, Parse(songDetail.Text), songDetail.Sections)
+		})
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := map[string]string{
+		"Chorus 2":   model.SectionChorus,
+		"pre chorus": model.SectionPreChorus,
+		"Pre-Chorus": model.SectionPreChorus,
+		"Part":       model.SectionOther,
+		"Solo":       model.SectionInstrumental,
+		"Spoken":     "",
+	}
+
+	for label, want := range tests {
+		if got := TypeOf(label); got != want {
+			t.Errorf("TypeOf(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func assertSections(t *testing.T, got []*model.LyricsSection, want []*model.LyricsSection) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d sections, want %d: %s", len(got), len(want), Render(got))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Position != w.Position || g.Type != w.Type || g.Label != w.Label || g.RepeatOf != w.RepeatOf ||
+			!slices.Equal(g.Lines, w.Lines) {
+			t.Errorf("section %d = %+v, want %+v", i+1, *g, *w)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package sections
+
+import (
+	"slices"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// ParseTranslation splits translated lyrics into sections like Parse, but
+// takes every line in brackets for a marker, as the markers of a
+// translation are usually translated too: "[Припев]".
+func ParseTranslation(text string) []*model.LyricsSection {
+	return parse(text, true)
+}
+
+// Align gives the translated sections the position, type and repeats of the
+// original sections they translate, section by section, and the label too
+// when the translation has none of its own. A repeat left
+// empty in the translation takes the lines of the translation it repeats.
+// It reports whether the translation has as many sections as the original;
+// the sections past the shorter of them are left as they are.
+func Align(translated []*model.LyricsSection, original []*model.LyricsSection) bool {
+	for i, section := range translated {
+		if i >= len(original) {
+			break
+		}
+
+		section.Position = original[i].Position
+		section.Type = original[i].Type
+		if section.Label == "" {
+			section.Label = original[i].Label
+		}
+		section.RepeatOf = original[i].RepeatOf
+
+		repeated := section.RepeatOf - 1
+		if len(section.Lines) == 0 && repeated >= 0 && repeated < i {
+			section.Lines = slices.Clone(translated[repeated].Lines)
+		}
+	}
+
+	return len(translated) == len(original)
+}
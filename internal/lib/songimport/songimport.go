@@ -0,0 +1,205 @@
+package songimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+var ErrBadInput = errors.New("bad import input")
+
+// Row is one input row; Err is set when the row itself can't be read, in
+// which case the rest of the input is still read. Line is the 1-based line of
+// the input the row starts on, so that it can be found in the source.
+type Row struct {
+	Data *model.ImportRow
+	Line int
+	Err  error
+}
+
+type fields struct {
+	SongName             string `json:"song"`
+	GroupName            string `json:"group"`
+	ReleaseDate          string `json:"releaseDate"`
+	ReleaseDatePrecision string `json:"releaseDatePrecision"`
+	Link                 string `json:"link"`
+	Text                 string `json:"text"`
+}
+
+// FormatOf picks the input format by the Content-Type of the request.
+func FormatOf(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FormatNDJSON
+	case "application/json":
+		return FormatJSON
+	}
+	return ""
+}
+
+// Parse reads songs in the given format. CSV input starts with a header
+// naming the columns: song, group, releaseDate, releaseDatePrecision, link
+// and text, of which song and group are required.
+func Parse(format string, r io.Reader) ([]*Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return parseJSON(r)
+	case FormatNDJSON:
+		return parseNDJSON(r)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", ErrBadInput, format)
+}
+
+func parseCSV(r io.Reader) ([]*Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't read csv header: %w", ErrBadInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"song", "group"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: csv header has no %q column", ErrBadInput, name)
+		}
+	}
+
+	var rows []*Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, &Row{Line: parseErr.StartLine, Err: err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadInput, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		column := func(name string) string {
+			if i, ok := columns[strings.ToLower(name)]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, &Row{Line: line, Data: toImportRow(&fields{
+			SongName:             column("song"),
+			GroupName:            column("group"),
+			ReleaseDate:          column("releaseDate"),
+			ReleaseDatePrecision: column("releaseDatePrecision"),
+			Link:                 column("link"),
+			Text:                 column("text"),
+		})})
+	}
+
+	return rows, nil
+}
+
+func parseJSON(r io.Reader) ([]*Row, error) {
+	// The input read so far is kept to tell the lines the elements start on.
+	var input bytes.Buffer
+	decoder := json.NewDecoder(io.TeeReader(r, &input))
+
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: json input must be an array", ErrBadInput)
+	}
+
+	var rows []*Row
+	line, counted := 1, 0
+	for decoder.More() {
+		// The decoder stops before the comma that separates the elements.
+		start := int(decoder.InputOffset())
+		for start < input.Len() && strings.ContainsRune(", \t\r\n", rune(input.Bytes()[start])) {
+			start++
+		}
+		line += bytes.Count(input.Bytes()[counted:start], []byte("\n"))
+		counted = start
+
+		var f fields
+		err := decoder.Decode(&f)
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			rows = append(rows, &Row{Line: line, Err: err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadInput, err)
+		}
+		rows = append(rows, &Row{Line: line, Data: toImportRow(&f)})
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadInput, err)
+	}
+
+	return rows, nil
+}
+
+func parseNDJSON(r io.Reader) ([]*Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []*Row
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var f fields
+		if err := json.Unmarshal([]byte(line), &f); err != nil {
+			rows = append(rows, &Row{Line: number, Err: err})
+			continue
+		}
+		rows = append(rows, &Row{Line: number, Data: toImportRow(&f)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadInput, err)
+	}
+
+	return rows, nil
+}
+
+// toImportRow keeps the details only when any of them is given.
+func toImportRow(f *fields) *model.ImportRow {
+	row := &model.ImportRow{
+		Song: &model.Song{SongName: f.SongName, GroupName: f.GroupName},
+	}
+	if f.ReleaseDate != "" || f.Link != "" || f.Text != "" {
+		row.Detail = &model.SongDetail{
+			ReleaseDate:          f.ReleaseDate,
+			ReleaseDatePrecision: f.ReleaseDatePrecision,
+			Link:                 f.Link,
+			Text:                 f.Text,
+		}
+	}
+	return row
+}
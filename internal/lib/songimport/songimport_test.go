@@ -0,0 +1,116 @@
+package songimport
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []string
+		err    bool
+	}{
+		{
+			name:   "csv with details",
+			format: FormatCSV,
+			input:  "song,group,releaseDate,link\nSong1,Group1,2006,https://example.com\nSong2,Group1,,",
+			want:   []string{"2 Song1:Group1 2006", "3 Song2:Group1"},
+		},
+		{
+			name:   "csv columns in any order and case",
+			format: FormatCSV,
+			input:  "Group, Song\nGroup1,Song1",
+			want:   []string{"2 Song1:Group1"},
+		},
+		{
+			name:   "csv quoted field over two lines",
+			format: FormatCSV,
+			input:  "song,group,text\nSong1,Group1,\"a\nb\"\nSong2,Group1,",
+			want:   []string{"2 Song1:Group1 a\nb", "4 Song2:Group1"},
+		},
+		{
+			name:   "csv bad row",
+			format: FormatCSV,
+			input:  "song,group\n\"Song1\"x,Group1\nSong2,Group1",
+			want:   []string{"2 error", "3 Song2:Group1"},
+		},
+		{name: "csv without a song column", format: FormatCSV, input: "name,group\nSong1,Group1", err: true},
+		{name: "csv without a header", format: FormatCSV, input: "", err: true},
+		{
+			name:   "json array",
+			format: FormatJSON,
+			input:  "[\n  {\"song\": \"Song1\", \"group\": \"Group1\", \"text\": \"a\"},\n\n  {\"song\": 1},\n  {\"song\": \"Song2\",\n   \"group\": \"Group1\"}\n]",
+			want:   []string{"2 Song1:Group1 a", "4 error", "5 Song2:Group1"},
+		},
+		{
+			name:   "json on one line",
+			format: FormatJSON,
+			input:  `[{"song": "Song1", "group": "Group1"}, {"song": "Song2", "group": "Group1"}]`,
+			want:   []string{"1 Song1:Group1", "1 Song2:Group1"},
+		},
+		{name: "json object", format: FormatJSON, input: `{"song": "Song1"}`, err: true},
+		{name: "json cut off", format: FormatJSON, input: `[{"song": "Song1"`, err: true},
+		{
+			name:   "ndjson with blank lines",
+			format: FormatNDJSON,
+			input:  "{\"song\": \"Song1\", \"group\": \"Group1\"}\n\n\nsong\n{\"song\": \"Song2\", \"group\": \"Group1\", \"text\": \"x\"}\n",
+			want:   []string{"1 Song1:Group1", "4 error", "5 Song2:Group1 x"},
+		},
+		{name: "unknown format", format: "xml", input: "<songs/>", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Parse(tt.format, strings.NewReader(tt.input))
+			if tt.err {
+				if !errors.Is(err, ErrBadInput) {
+					t.Fatalf("error = %v, want %v", err, ErrBadInput)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, row := range rows {
+				if row.Err != nil {
+					got = append(got, fmt.Sprintf("%d error", row.Line))
+					continue
+				}
+				s := fmt.Sprintf("%d %s:%s", row.Line, row.Data.Song.SongName, row.Data.Song.GroupName)
+				if row.Data.Detail != nil {
+					s += " " + row.Data.Detail.ReleaseDate + row.Data.Detail.Text
+				}
+				got = append(got, s)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]string{
+		"text/csv":                          FormatCSV,
+		"text/csv; charset=utf-8":           FormatCSV,
+		"application/JSON":                  FormatJSON,
+		"application/x-ndjson":              FormatNDJSON,
+		"application/jsonl":                 FormatNDJSON,
+		"application/x-www-form-urlencoded": "",
+		"":                                  "",
+	}
+
+	for contentType, want := range tests {
+		if got := FormatOf(contentType); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
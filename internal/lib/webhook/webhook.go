@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// GenerateSecret returns a new random secret to sign deliveries with.
+func GenerateSecret() (string, error) {
+	const op = "internal.lib.webhook.GenerateSecret()"
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("%s:%w", op, err)
+	}
+
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Sign returns the signature of the body sent at timestamp, in Unix seconds:
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the
+// secret. Signing the timestamp lets receivers refuse replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
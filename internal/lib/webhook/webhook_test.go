@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"song.created"}`)
+	const want = "sha256=57b6fb42a63a8f4f404187020922267767497e71466618e54577136c85223be6"
+
+	if got := Sign("whsec_test", 1700000000, body); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign("whsec_test", 1700000001, body) == want {
+		t.Error("signature doesn't depend on the timestamp")
+	}
+	if Sign("whsec_other", 1700000000, body) == want {
+		t.Error("signature doesn't depend on the secret")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(first, "whsec_") || len(first) != len("whsec_")+43 {
+		t.Errorf("secret %q, want whsec_ and 32 bytes in base64", first)
+	}
+	if first == second {
+		t.Error("the same secret generated twice")
+	}
+}
@@ -1,12 +1,422 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Song struct {
-	SongName  string `json:"song" validate:"required" db:"song_name"`
-	GroupName string `json:"group" validate:"required" db:"group_name"`
+	ID          int64      `json:"id,omitempty" db:"id"`
+	SongName    string     `json:"song" validate:"required" db:"song_name"`
+	GroupName   string     `json:"group" validate:"required" db:"group_name"`
+	ReleaseDate string     `json:"releaseDate,omitempty" db:"release_date"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
+}
+
+const (
+	MatchExact    = "exact"
+	MatchPrefix   = "prefix"
+	MatchContains = "contains"
+	MatchFuzzy    = "fuzzy"
+)
+
+type LibraryFilter struct {
+	SongName  string
+	GroupName string
+	Match     string
+	Threshold float64
+	Sort      []SortKey
+
+	ReleasedFrom *time.Time
+	ReleasedTo   *time.Time
 }
 
+const (
+	SortSong        = "song"
+	SortGroup       = "group"
+	SortReleaseDate = "releaseDate"
+	SortScore       = "score"
+)
+
+// SortKey orders the library by Field; songs.id always follows as the last key.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// LibraryCursor is the keyset position of a library row: the values of its
+// sort keys and its id. Sort records the ordering the values belong to.
+type LibraryCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	ID     int64         `json:"id"`
+}
+
+// LibraryPage asks for Limit rows after Cursor, or before it when Backward is set.
+type LibraryPage struct {
+	Limit    int64
+	Cursor   *LibraryCursor
+	Backward bool
+}
+
+// SongDetail.ReleaseDate is accepted in several formats and always written
+// back as ISO-8601 reduced to ReleaseDatePrecision (year, month or day).
+// Sections are parsed from Text on save; when a client supplies them, Text
+// is written from them instead. Sources names the metadata provider each
+// field was fetched from; a field edited by hand has none.
 type SongDetail struct {
-	ReleaseDate string `json:"releaseDate" validate:"required" db:"release_date"`
-	Link        string `json:"link" validate:"required" db:"link"`
-	Text        string `json:"text" validate:"required" db:"text"`
+	ReleaseDate          string            `json:"releaseDate" validate:"required" db:"release_date"`
+	ReleaseDatePrecision string            `json:"releaseDatePrecision,omitempty" validate:"omitempty,oneof=year month day" db:"release_date_precision"`
+	Link                 string            `json:"link" validate:"required" db:"link"`
+	Text                 string            `json:"text" validate:"required_without=Sections" db:"text"`
+	Sections             []*LyricsSection  `json:"sections,omitempty" validate:"omitempty,dive" db:"-"`
+	Sources              map[string]string `json:"sources,omitempty" db:"-"`
+}
+
+// The fields of SongDetail a metadata provider may supply.
+const (
+	FieldReleaseDate = "releaseDate"
+	FieldLink        = "link"
+	FieldText        = "text"
+)
+
+const (
+	SectionVerse        = "verse"
+	SectionChorus       = "chorus"
+	SectionPreChorus    = "pre-chorus"
+	SectionBridge       = "bridge"
+	SectionIntro        = "intro"
+	SectionOutro        = "outro"
+	SectionHook         = "hook"
+	SectionInstrumental = "instrumental"
+	SectionOther        = "other"
+)
+
+// LyricsSection is a part of the lyrics in song order. A repeated section,
+// e.g. a chorus sung again, points at its first occurrence with RepeatOf.
+type LyricsSection struct {
+	Position int      `json:"position"`
+	Type     string   `json:"type" validate:"required,oneof=verse chorus pre-chorus bridge intro outro hook instrumental other"`
+	Label    string   `json:"label,omitempty"`
+	Lines    []string `json:"lines"`
+	RepeatOf int      `json:"repeatOf,omitempty"`
+}
+
+type Group struct {
+	ID          int64  `json:"id" db:"id"`
+	Name        string `json:"name" validate:"required" db:"name"`
+	Country     string `json:"country" db:"country"`
+	FormedYear  *int   `json:"formedYear,omitempty" validate:"omitempty,gte=1000,lte=9999" db:"formed_year"`
+	Description string `json:"description" db:"description"`
+}
+
+type Album struct {
+	ID          int64  `json:"id" db:"id"`
+	Title       string `json:"title" validate:"required" db:"title"`
+	GroupName   string `json:"group" validate:"required" db:"group_name"`
+	ReleaseDate string `json:"releaseDate" validate:"required,datetime=2006-01-02" db:"release_date"`
+}
+
+type Playlist struct {
+	ID      int64            `json:"id" db:"id"`
+	Name    string           `json:"name" validate:"required" db:"name"`
+	Entries []*PlaylistEntry `json:"entries"`
+}
+
+type PlaylistEntry struct {
+	Position int    `json:"position" db:"position"`
+	Song     *Song  `json:"song"`
+	Link     string `json:"link,omitempty" db:"link"`
+}
+
+type APIKey struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" validate:"required" db:"name"`
+	Role      string     `json:"role" validate:"required,oneof=reader editor admin" db:"role"`
+	Hash      string     `json:"-" db:"key_hash"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+type SearchResult struct {
+	Song    *Song   `json:"song"`
+	Rank    float64 `json:"rank" db:"rank"`
+	Snippet string  `json:"snippet" db:"snippet"`
+}
+
+// DetailChange describes who made a change to the song detail and why.
+type DetailChange struct {
+	Author  string
+	Message string
+}
+
+// Revision is a numbered snapshot of the song detail, stored on every change.
+type Revision struct {
+	Revision  int64       `json:"revision" db:"revision"`
+	SongID    int64       `json:"songId" db:"song_id"`
+	Detail    *SongDetail `json:"detail"`
+	Author    string      `json:"author,omitempty" db:"author"`
+	Message   string      `json:"message,omitempty" db:"message"`
+	CreatedAt time.Time   `json:"createdAt" db:"created_at"`
+}
+
+type RevisionDiff struct {
+	From        int64        `json:"from"`
+	To          int64        `json:"to"`
+	ReleaseDate *ValueChange `json:"releaseDate,omitempty"`
+	Link        *ValueChange `json:"link,omitempty"`
+	Lines       []*DiffLine  `json:"lines"`
+}
+
+type ValueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DiffLine is one line of a text diff; Op is equal, insert or delete.
+type DiffLine struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+}
+
+const (
+	AuditAddSong         = "AddSong"
+	AuditAddSongDetail   = "AddSongDetail"
+	AuditPutSongDetail   = "PutSongDetail"
+	AuditDeleteSong      = "DeleteSong"
+	AuditRestoreSong     = "RestoreSong"
+	AuditRestoreRevision = "RestoreRevision"
+	AuditPutChordSheet   = "PutChordSheet"
+)
+
+// AuditRecord is a successful change of a song; Before and After hold the
+// state around the change as JSON and are null where there is none.
+type AuditRecord struct {
+	ID         int64           `json:"id" db:"id"`
+	Operation  string          `json:"operation" db:"operation"`
+	SongName   string          `json:"song" db:"song_name"`
+	GroupName  string          `json:"group" db:"group_name"`
+	RequestID  string          `json:"requestId,omitempty" db:"request_id"`
+	RemoteAddr string          `json:"remoteAddr,omitempty" db:"remote_addr"`
+	Actor      string          `json:"actor,omitempty" db:"actor"`
+	Before     json.RawMessage `json:"before" db:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" db:"after" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"createdAt" db:"created_at"`
+}
+
+type AuditFilter struct {
+	SongName  string
+	GroupName string
+	Operation string
+	From      *time.Time
+	To        *time.Time
+}
+
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+// ImportRow is a song with optional details as read by the bulk import.
+type ImportRow struct {
+	Song   *Song
+	Detail *SongDetail
+}
+
+type ImportReport struct {
+	DryRun     bool               `json:"dryRun"`
+	Created    int                `json:"created"`
+	Duplicates int                `json:"duplicates"`
+	Invalid    int                `json:"invalid"`
+	Rows       []*ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult reports what became of one input row; Row is the 1-based
+// line of the input the row starts on.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Song   string `json:"song,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportedSong is a song of the library export with its details and lyrics.
+type ExportedSong struct {
+	ID                   int64  `json:"id"`
+	SongName             string `json:"song"`
+	GroupName            string `json:"group"`
+	ReleaseDate          string `json:"releaseDate,omitempty"`
+	ReleaseDatePrecision string `json:"releaseDatePrecision,omitempty"`
+	Link                 string `json:"link,omitempty"`
+	Text                 string `json:"text,omitempty"`
+}
+
+// Lyrics are time-synced lyrics of a song as read from an LRC file. Tags
+// hold the LRC metadata such as ti, ar and al.
+type Lyrics struct {
+	SongID int64             `json:"songId"`
+	Tags   map[string]string `json:"tags,omitempty"`
+	Lines  []*LyricLine      `json:"lines"`
+}
+
+// LyricLine is a timestamped line; Words are set for enhanced LRC with
+// word-level timestamps.
+type LyricLine struct {
+	Position int          `json:"position"`
+	TimeMs   int64        `json:"timeMs"`
+	Time     string       `json:"time"`
+	Text     string       `json:"text"`
+	Words    []*LyricWord `json:"words,omitempty"`
+}
+
+type LyricWord struct {
+	TimeMs int64  `json:"timeMs"`
+	Time   string `json:"time"`
+	Text   string `json:"text"`
+}
+
+// ChordSheet is a ChordPro chord sheet of a song. Meta holds the metadata
+// directives other than title, subtitle, artist and key, e.g. capo or tempo.
+type ChordSheet struct {
+	SongID   int64             `json:"songId"`
+	Title    string            `json:"title,omitempty"`
+	Subtitle string            `json:"subtitle,omitempty"`
+	Artist   string            `json:"artist,omitempty"`
+	Key      string            `json:"key,omitempty"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	Sections []*ChordSection   `json:"sections"`
+}
+
+// ChordSection is a block of the sheet; Type is chorus, verse, bridge, tab or
+// grid for the ChordPro environments and empty for unmarked blocks.
+type ChordSection struct {
+	Type  string       `json:"type,omitempty"`
+	Label string       `json:"label,omitempty"`
+	Lines []*ChordLine `json:"lines"`
+}
+
+// ChordLine is a lyric line with the chords placed over it; a comment line
+// holds the comment as its text.
+type ChordLine struct {
+	Text    string           `json:"text"`
+	Chords  []*ChordPosition `json:"chords,omitempty"`
+	Comment bool             `json:"comment,omitempty"`
+}
+
+// ChordPosition places a chord before the character of the line at Position,
+// counted in characters.
+type ChordPosition struct {
+	Position int    `json:"position"`
+	Chord    string `json:"chord"`
+}
+
+// Translation is the lyrics of a song in the language of the BCP-47 tag
+// Language. Its sections are aligned to the sections of the original lyrics
+// one by one, so they share their positions and types.
+type Translation struct {
+	SongID    int64            `json:"songId"`
+	Language  string           `json:"language"`
+	Text      string           `json:"text"`
+	Sections  []*LyricsSection `json:"sections,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+const (
+	JobEnrichSong = "enrich_song"
+
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is a background task on a song. A queued job runs once RunAt has come;
+// a failed attempt is queued again until MaxAttempts are spent.
+type Job struct {
+	ID          int64      `json:"id"`
+	Kind        string     `json:"kind"`
+	SongID      int64      `json:"songId"`
+	Song        *Song      `json:"song,omitempty"`
+	State       string     `json:"state"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	LastError   string     `json:"lastError,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	RequestID   string     `json:"requestId,omitempty"`
+	RunAt       time.Time  `json:"runAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	URL         string     `json:"url,omitempty"`
+}
+
+const (
+	EventSongCreated      = "song.created"
+	EventSongDeleted      = "song.deleted"
+	EventDetailCreated    = "detail.created"
+	EventDetailUpdated    = "detail.updated"
+	EventEnrichmentFailed = "enrichment.failed"
+)
+
+// Event is a change of the library kept in the event log. Data holds what
+// changed: the queued job of a new song, the detail of a deleted or restored
+// song, the first or the new detail, the saved lyrics, chord sheet or
+// translation, or the failed job.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Song      *Song           `json:"song"`
+	Data      json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+	RequestID string          `json:"requestId,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// EventFilter selects the events of a group and of any of Types; empty
+// fields select all.
+type EventFilter struct {
+	GroupName string
+	Types     []string
+}
+
+// Webhook subscribes URL to the events of the listed types. Deliveries are
+// signed with Secret, which is returned only when the webhook is created.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url" validate:"required,http_url"`
+	Events    []string  `json:"events" validate:"required,min=1,dive,oneof=song.created song.deleted detail.created detail.updated enrichment.failed"`
+	Secret    string    `json:"secret,omitempty" validate:"omitempty,min=16"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookDelivery is an event sent to a webhook. Its states are those of a
+// job; every attempt is kept in Log.
+type WebhookDelivery struct {
+	ID         int64              `json:"id"`
+	WebhookID  int64              `json:"webhookId"`
+	URL        string             `json:"url"`
+	Secret     string             `json:"-"`
+	Event      *Event             `json:"event"`
+	State      string             `json:"state"`
+	Attempts   int                `json:"attempts"`
+	LastError  string             `json:"lastError,omitempty"`
+	ReplayOf   int64              `json:"replayOf,omitempty"`
+	RunAt      time.Time          `json:"runAt"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+	FinishedAt *time.Time         `json:"finishedAt,omitempty"`
+	Log        []*DeliveryAttempt `json:"log,omitempty"`
+}
+
+// DeliveryAttempt is one request of a delivery and its outcome; StatusCode
+// is 0 when no answer came.
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
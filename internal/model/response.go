@@ -1,10 +1,36 @@
 package model
 
 type Response struct {
-	Status       string           `json:"status"`
-	Error        string           `json:"error,omitempty"`
-	SongsLibrary *SongsConnection `json:"songLibrary,omitempty"`
-	SongText     *TextConnection  `json:"songText,omitempty"`
+	Status       string               `json:"status"`
+	Error        string               `json:"error,omitempty"`
+	SongsLibrary *SongsConnection     `json:"songLibrary,omitempty"`
+	SongText     *TextConnection      `json:"songText,omitempty"`
+	Group        *Group               `json:"group,omitempty"`
+	Groups       *GroupsConnection    `json:"groups,omitempty"`
+	Album        *Album               `json:"album,omitempty"`
+	AlbumTracks  *SongsConnection     `json:"albumTracks,omitempty"`
+	Playlist     *Playlist            `json:"playlist,omitempty"`
+	APIKey       *APIKey              `json:"apiKey,omitempty"`
+	APIKeys      []*APIKey            `json:"apiKeys,omitempty"`
+	Key          string               `json:"key,omitempty"`
+	SearchResult *SearchConnection    `json:"searchResult,omitempty"`
+	Revision     *Revision            `json:"revision,omitempty"`
+	Revisions    *RevisionsConnection `json:"revisions,omitempty"`
+	Diff         *RevisionDiff        `json:"diff,omitempty"`
+	Song         *Song                `json:"song,omitempty"`
+	Trash        *SongsConnection     `json:"trash,omitempty"`
+	Audit        *AuditConnection     `json:"audit,omitempty"`
+	Import       *ImportReport        `json:"import,omitempty"`
+	Lyrics       *Lyrics              `json:"lyrics,omitempty"`
+	LyricLine    *LyricLine           `json:"lyricLine,omitempty"`
+	ChordSheet   *ChordSheet          `json:"chordSheet,omitempty"`
+	Translation  *Translation         `json:"translation,omitempty"`
+	Translations []*Translation       `json:"translations,omitempty"`
+	Job          *Job                 `json:"job,omitempty"`
+	Webhook      *Webhook             `json:"webhook,omitempty"`
+	Webhooks     []*Webhook           `json:"webhooks,omitempty"`
+	Delivery     *WebhookDelivery     `json:"delivery,omitempty"`
+	Deliveries   []*WebhookDelivery   `json:"deliveries,omitempty"`
 }
 
 type SongsConnection struct {
@@ -13,13 +39,57 @@ type SongsConnection struct {
 }
 
 type LibraryPageInfo struct {
-	EndCursor   *int64 `json:"endCursor,omitempty"`
-	HasNextPage bool   `json:"hasNextPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	HasNextPage     bool    `json:"hasNextPage"`
+	TotalCount      int64   `json:"totalCount"`
 }
 
 type SongEdge struct {
-	Node   *Song `json:"node"`
-	Cursor int64 `json:"cursor"`
+	Node   *Song    `json:"node"`
+	Cursor string   `json:"cursor"`
+	Score  *float64 `json:"score,omitempty"`
+}
+
+type GroupsConnection struct {
+	Edges    []*GroupEdge     `json:"edges"`
+	PageInfo *LibraryPageInfo `json:"pageInfo"`
+}
+
+type GroupEdge struct {
+	Node   *Group `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+type SearchConnection struct {
+	Edges    []*SearchEdge    `json:"edges"`
+	PageInfo *LibraryPageInfo `json:"pageInfo"`
+}
+
+type SearchEdge struct {
+	Node   *SearchResult `json:"node"`
+	Cursor string        `json:"cursor"`
+}
+
+type RevisionsConnection struct {
+	Edges    []*RevisionEdge  `json:"edges"`
+	PageInfo *LibraryPageInfo `json:"pageInfo"`
+}
+
+type RevisionEdge struct {
+	Node   *Revision `json:"node"`
+	Cursor string    `json:"cursor"`
+}
+
+type AuditConnection struct {
+	Edges    []*AuditEdge     `json:"edges"`
+	PageInfo *LibraryPageInfo `json:"pageInfo"`
+}
+
+type AuditEdge struct {
+	Node   *AuditRecord `json:"node"`
+	Cursor string       `json:"cursor"`
 }
 
 type TextConnection struct {
@@ -33,8 +103,10 @@ type TextPageInfo struct {
 }
 
 type CoupletEdge struct {
-	Node   *string `json:"node"`
-	Cursor int     `json:"cursor"`
+	Node     *string        `json:"node"`
+	Cursor   int            `json:"cursor"`
+	Section  *LyricsSection `json:"section,omitempty"`
+	Language string         `json:"language,omitempty"`
 }
 
 func OK() Response {
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type albumRecord struct {
+	id          int64
+	title       string
+	groupId     int64
+	releaseDate string
+	tracks      []int64
+}
+
+func (s *Storage) AddAlbum(album *model.Album) error {
+	const op = "internal.storage.memory.AddAlbum()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := s.foundOrCreateGroup(album.GroupName)
+	for _, rec := range s.albums {
+		if rec.groupId == group.ID && rec.title == album.Title {
+			return fmt.Errorf("%s:%w", op, storage.ErrAlbumAlreadyExists)
+		}
+	}
+
+	s.lastAlbumId++
+	album.ID = s.lastAlbumId
+	s.albums = append(s.albums, &albumRecord{
+		id:          album.ID,
+		title:       album.Title,
+		groupId:     group.ID,
+		releaseDate: album.ReleaseDate,
+	})
+
+	return nil
+}
+
+func (s *Storage) GetAlbum(id int64) (*model.Album, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundAlbum(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toAlbum(rec), nil
+}
+
+func (s *Storage) AddAlbumTrack(albumId int64, song *model.Song, position int) error {
+	const op = "internal.storage.memory.AddAlbumTrack()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	album, err := s.foundAlbum(albumId)
+	if err != nil {
+		return err
+	}
+
+	songRec, err := s.foundSong(song)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range album.tracks {
+		if id == songRec.id {
+			return fmt.Errorf("%s:%w", op, storage.ErrTrackAlreadyExists)
+		}
+	}
+
+	album.tracks = storage.InsertAt(album.tracks, songRec.id, position)
+	return nil
+}
+
+func (s *Storage) SetAlbumTracks(albumId int64, songs []*model.Song) error {
+	const op = "internal.storage.memory.SetAlbumTracks()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	album, err := s.foundAlbum(albumId)
+	if err != nil {
+		return err
+	}
+
+	tracks := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		songRec, err := s.foundSong(song)
+		if err != nil {
+			return err
+		}
+		for _, id := range tracks {
+			if id == songRec.id {
+				return fmt.Errorf("%s:%w", op, storage.ErrTrackAlreadyExists)
+			}
+		}
+		tracks = append(tracks, songRec.id)
+	}
+
+	album.tracks = tracks
+	return nil
+}
+
+func (s *Storage) GetAlbumTracks(albumId int64, limit int64, offset int64) ([]*model.Song, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	album, err := s.foundAlbum(albumId)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []*model.Song
+	for _, songId := range album.tracks {
+		if rec, err := s.foundSongById(songId); err == nil {
+			tracks = append(tracks, s.toSong(rec))
+		}
+	}
+	tracks = tracks[min(max(offset, 0), int64(len(tracks))):]
+
+	return tracks[:min(max(limit, 0), int64(len(tracks)))], nil
+}
+
+func (s *Storage) CountAlbumTracks(albumId int64) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	album, err := s.foundAlbum(albumId)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, songId := range album.tracks {
+		if _, err := s.foundSongById(songId); err == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// foundAlbum must be called with s.mu held.
+func (s *Storage) foundAlbum(id int64) (*albumRecord, error) {
+	const op = "internal.storage.memory.foundAlbum()"
+
+	for _, rec := range s.albums {
+		if rec.id == id {
+			return rec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrAlbumNotFound)
+}
+
+// toAlbum must be called with s.mu held.
+func (s *Storage) toAlbum(rec *albumRecord) *model.Album {
+	album := &model.Album{
+		ID:          rec.id,
+		Title:       rec.title,
+		ReleaseDate: rec.releaseDate,
+	}
+	if group, err := s.foundGroup(rec.groupId); err == nil {
+		album.GroupName = group.Name
+	}
+	return album
+}
+
+// removeTrack must be called with s.mu held for writing.
+func (s *Storage) removeTrack(songId int64) {
+	for _, album := range s.albums {
+		for i, id := range album.tracks {
+			if id == songId {
+				album.tracks = append(album.tracks[:i], album.tracks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// removeGroupAlbums must be called with s.mu held for writing.
+func (s *Storage) removeGroupAlbums(groupId int64) {
+	albums := s.albums[:0]
+	for _, album := range s.albums {
+		if album.groupId != groupId {
+			albums = append(albums, album)
+		}
+	}
+	s.albums = albums
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (s *Storage) AddAPIKey(key *model.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAPIKeyId++
+	key.ID = s.lastAPIKeyId
+	key.CreatedAt = time.Now()
+	stored := *key
+	s.apiKeys = append(s.apiKeys, &stored)
+
+	return nil
+}
+
+func (s *Storage) GetAPIKeys() ([]*model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*model.APIKey, 0, len(s.apiKeys))
+	for _, stored := range s.apiKeys {
+		key := *stored
+		keys = append(keys, &key)
+	}
+
+	return keys, nil
+}
+
+func (s *Storage) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	const op = "internal.storage.memory.GetAPIKeyByHash()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, stored := range s.apiKeys {
+		if stored.Hash == hash && stored.RevokedAt == nil {
+			key := *stored
+			return &key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrAPIKeyNotFound)
+}
+
+func (s *Storage) RotateAPIKey(id int64, hash string) (*model.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.foundActiveAPIKey(id)
+	if err != nil {
+		return nil, err
+	}
+	stored.Hash = hash
+
+	key := *stored
+	return &key, nil
+}
+
+func (s *Storage) RevokeAPIKey(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.foundActiveAPIKey(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	stored.RevokedAt = &now
+
+	return nil
+}
+
+// foundActiveAPIKey must be called with s.mu held.
+func (s *Storage) foundActiveAPIKey(id int64) (*model.APIKey, error) {
+	const op = "internal.storage.memory.foundActiveAPIKey()"
+
+	for _, stored := range s.apiKeys {
+		if stored.ID == id && stored.RevokedAt == nil {
+			return stored, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrAPIKeyNotFound)
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func (s *Storage) AddAuditRecord(record *model.AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAuditId++
+	record.ID = s.lastAuditId
+	record.CreatedAt = time.Now().UTC()
+
+	rec := *record
+	s.audit = append(s.audit, &rec)
+	return nil
+}
+
+// GetAuditRecords lists the audit records matching the filter, newest first.
+func (s *Storage) GetAuditRecords(filter *model.AuditFilter, limit int64, offset int64) ([]*model.AuditRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*model.AuditRecord
+	skipped := int64(0)
+	for i := len(s.audit) - 1; i >= 0 && int64(len(records)) < limit; i-- {
+		if !matchAudit(s.audit[i], filter) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		rec := *s.audit[i]
+		records = append(records, &rec)
+	}
+
+	return records, nil
+}
+
+func (s *Storage) CountAuditRecords(filter *model.AuditFilter) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, rec := range s.audit {
+		if matchAudit(rec, filter) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func matchAudit(rec *model.AuditRecord, filter *model.AuditFilter) bool {
+	switch {
+	case filter.SongName != "" && rec.SongName != filter.SongName:
+		return false
+	case filter.GroupName != "" && rec.GroupName != filter.GroupName:
+		return false
+	case filter.Operation != "" && rec.Operation != filter.Operation:
+		return false
+	case filter.From != nil && rec.CreatedAt.Before(*filter.From):
+		return false
+	case filter.To != nil && rec.CreatedAt.After(*filter.To):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/nabishec/restapi/internal/lib/chordpro"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// PutChordSheet replaces the chord sheet of the song. When the song has a
+// detail, its text becomes the chord-free lyrics of the sheet as a new
+// revision.
+func (s *Storage) PutChordSheet(songId int64, sheet *model.ChordSheet, change *model.DetailChange) error {
+	const op = "internal.storage.memory.PutChordSheet()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	copied, err := copyChordSheet(sheet)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if text := chordpro.PlainText(sheet); rec.detail != nil && text != rec.detail.Text {
+		detail := *rec.detail
+		detail.Text = text
+		// The text comes from the sheet now, the other fields keep their source.
+		detail.Sources = maps.Clone(detail.Sources)
+		delete(detail.Sources, model.FieldText)
+		if _, err := saveSongDetail(rec, &detail, change); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	copied.SongID = songId
+	rec.chordSheet = copied
+	return nil
+}
+
+func (s *Storage) GetChordSheet(songId int64) (*model.ChordSheet, error) {
+	const op = "internal.storage.memory.GetChordSheet()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundChordSheet(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	sheet, err := copyChordSheet(rec.chordSheet)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return sheet, nil
+}
+
+func (s *Storage) DeleteChordSheet(songId int64) error {
+	const op = "internal.storage.memory.DeleteChordSheet()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundChordSheet(songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rec.chordSheet = nil
+	return nil
+}
+
+// foundChordSheet must be called with s.mu held.
+func (s *Storage) foundChordSheet(songId int64) (*songRecord, error) {
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, err
+	}
+	if rec.chordSheet == nil {
+		return nil, storage.ErrChordSheetNotFound
+	}
+	return rec, nil
+}
+
+// copyChordSheet makes a deep copy the way the sheet is kept by PostgreSQL.
+func copyChordSheet(sheet *model.ChordSheet) (*model.ChordSheet, error) {
+	data, err := json.Marshal(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied model.ChordSheet
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// AddEvent appends the event to the event log and queues its delivery to
+// every webhook subscribed to its type.
+func (s *Storage) AddEvent(event *model.Event) error {
+	s.mu.Lock()
+
+	now := time.Now().UTC()
+	s.lastEventId++
+	event.ID = s.lastEventId
+	event.CreatedAt = now
+
+	s.events = append(s.events, copyEvent(event))
+
+	for _, hook := range s.webhooks {
+		if slices.Contains(hook.Events, event.Type) {
+			s.queueDelivery(hook.ID, event.ID, 0, now)
+		}
+	}
+
+	listeners := make([]func(), 0, len(s.listeners))
+	for _, notify := range s.listeners {
+		listeners = append(listeners, notify)
+	}
+	s.mu.Unlock()
+
+	for _, notify := range listeners {
+		notify()
+	}
+	return nil
+}
+
+// GetEvents returns up to limit events of the log selected by the filter
+// that follow the event with the id after, oldest first.
+func (s *Storage) GetEvents(filter *model.EventFilter, after int64, limit int) ([]*model.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []*model.Event{}
+	for _, event := range s.events {
+		if len(events) == limit {
+			break
+		}
+		if event.ID <= after ||
+			filter.GroupName != "" && event.Song.GroupName != filter.GroupName ||
+			len(filter.Types) > 0 && !slices.Contains(filter.Types, event.Type) {
+			continue
+		}
+		events = append(events, copyEvent(event))
+	}
+	return events, nil
+}
+
+// LastEventID returns the id of the last event of the log, 0 when it's empty.
+func (s *Storage) LastEventID() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastEventId, nil
+}
+
+// ListenEvents calls notify whenever an event is added to the log, until
+// ctx is done; notify is called once as soon as it listens.
+func (s *Storage) ListenEvents(ctx context.Context, notify func()) error {
+	s.mu.Lock()
+	if s.listeners == nil {
+		s.listeners = make(map[int64]func())
+	}
+	s.lastListenerId++
+	id := s.lastListenerId
+	s.listeners[id] = notify
+	s.mu.Unlock()
+
+	notify()
+	<-ctx.Done()
+
+	s.mu.Lock()
+	delete(s.listeners, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// foundEvent must be called with s.mu held.
+func (s *Storage) foundEvent(id int64) *model.Event {
+	for _, event := range s.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+func copyEvent(event *model.Event) *model.Event {
+	copied := *event
+	song := *event.Song
+	copied.Song = &song
+	copied.Data = slices.Clone(event.Data)
+	return &copied
+}
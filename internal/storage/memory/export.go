@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// ExportSongs passes the songs of the library matching the filter, with
+// their details, to write one by one. The songs are copied first, so write
+// runs without the lock held.
+func (s *Storage) ExportSongs(ctx context.Context, filter *model.LibraryFilter, write func(song *model.ExportedSong) error) error {
+	s.mu.RLock()
+	rows := s.filterLibrary(filter)
+	songs := make([]*model.ExportedSong, 0, len(rows))
+	for _, row := range rows {
+		song := &model.ExportedSong{
+			ID:        row.edge.Node.ID,
+			SongName:  row.edge.Node.SongName,
+			GroupName: row.edge.Node.GroupName,
+		}
+		if rec, err := s.foundSongById(song.ID); err == nil && rec.detail != nil {
+			song.ReleaseDate = rec.detail.ReleaseDate
+			song.ReleaseDatePrecision = rec.detail.ReleaseDatePrecision
+			song.Link = rec.detail.Link
+			song.Text = rec.detail.Text
+		}
+		songs = append(songs, song)
+	}
+	s.mu.RUnlock()
+
+	for _, song := range songs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := write(song); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
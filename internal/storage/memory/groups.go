@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (s *Storage) AddGroup(group *model.Group) error {
+	const op = "internal.storage.memory.AddGroup()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.foundGroupByName(group.Name); err == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupAlreadyExists)
+	}
+
+	s.lastGroupId++
+	group.ID = s.lastGroupId
+	stored := *group
+	s.groups = append(s.groups, &stored)
+
+	return nil
+}
+
+func (s *Storage) GetGroup(id int64) (*model.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	group, err := s.foundGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *group
+	return &result, nil
+}
+
+func (s *Storage) GetGroups(limit int64, offset int64) ([]*model.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var groups []*model.Group
+	for i := max(offset, 0); i < int64(len(s.groups)) && int64(len(groups)) < limit; i++ {
+		group := *s.groups[i]
+		groups = append(groups, &group)
+	}
+
+	return groups, nil
+}
+
+func (s *Storage) CountGroups() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.groups)), nil
+}
+
+func (s *Storage) PutGroup(group *model.Group) error {
+	const op = "internal.storage.memory.PutGroup()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if found, err := s.foundGroupByName(group.Name); err == nil && found.ID != group.ID {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupAlreadyExists)
+	}
+
+	stored, err := s.foundGroup(group.ID)
+	if err != nil {
+		return err
+	}
+	*stored = *group
+
+	return nil
+}
+
+func (s *Storage) DeleteGroup(id int64) error {
+	const op = "internal.storage.memory.DeleteGroup()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range s.songs {
+		if rec.groupId == id {
+			return fmt.Errorf("%s:%w", op, storage.ErrGroupHasSongs)
+		}
+	}
+	// The songs in the trash keep their group until they are purged.
+	for _, rec := range s.trash {
+		if rec.groupId == id {
+			return fmt.Errorf("%s:%w", op, storage.ErrGroupHasTrashedSongs)
+		}
+	}
+
+	for i, group := range s.groups {
+		if group.ID == id {
+			s.groups = append(s.groups[:i], s.groups[i+1:]...)
+			s.removeGroupAlbums(id)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+}
+
+// foundGroup must be called with s.mu held.
+func (s *Storage) foundGroup(id int64) (*model.Group, error) {
+	const op = "internal.storage.memory.foundGroup()"
+
+	for _, group := range s.groups {
+		if group.ID == id {
+			return group, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+}
+
+// foundGroupByName must be called with s.mu held.
+func (s *Storage) foundGroupByName(name string) (*model.Group, error) {
+	const op = "internal.storage.memory.foundGroupByName()"
+
+	for _, group := range s.groups {
+		if group.Name == name {
+			return group, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+}
+
+// foundOrCreateGroup must be called with s.mu held for writing.
+func (s *Storage) foundOrCreateGroup(name string) *model.Group {
+	if group, err := s.foundGroupByName(name); err == nil {
+		return group
+	}
+
+	s.lastGroupId++
+	group := &model.Group{ID: s.lastGroupId, Name: name}
+	s.groups = append(s.groups, group)
+
+	return group
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// ImportSongs adds the songs with their details and tells for every row
+// whether it was created or is a duplicate of an existing song or of an
+// earlier row. Nothing is changed on a dry run or when any detail is invalid.
+func (s *Storage) ImportSongs(rows []*model.ImportRow, dryRun bool, change *model.DetailChange) ([]string, error) {
+	const op = "internal.storage.memory.ImportSongs()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]string, len(rows))
+	seen := make(map[[2]string]bool, len(rows))
+	for i, row := range rows {
+		key := [2]string{row.Song.SongName, row.Song.GroupName}
+		if _, err := s.foundSong(row.Song); err == nil || seen[key] {
+			statuses[i] = model.ImportDuplicate
+			continue
+		}
+		seen[key] = true
+		statuses[i] = model.ImportCreated
+
+		if row.Detail != nil {
+			detail := *row.Detail
+			if err := releasedate.Normalize(&detail); err != nil {
+				return nil, fmt.Errorf("%s:%w", op, err)
+			}
+		}
+	}
+
+	if dryRun {
+		return statuses, nil
+	}
+
+	for i, row := range rows {
+		if statuses[i] != model.ImportCreated {
+			continue
+		}
+
+		s.lastId++
+		rec := &songRecord{
+			id:       s.lastId,
+			songName: row.Song.SongName,
+			groupId:  s.foundOrCreateGroup(row.Song.GroupName).ID,
+		}
+		if row.Detail != nil {
+			if _, err := saveSongDetail(rec, row.Detail, change); err != nil {
+				return nil, fmt.Errorf("%s:%w", op, err)
+			}
+		}
+		s.songs = append(s.songs, rec)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type jobRecord struct {
+	job         model.Job
+	lockedUntil time.Time
+}
+
+// AddSongAndEnqueue adds the song and queues the job on it at once, so that
+// no song is left without its job.
+func (s *Storage) AddSongAndEnqueue(song *model.Song, job *model.Job) error {
+	const op = "internal.storage.memory.AddSongAndEnqueue()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.foundSong(song); err == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
+	}
+
+	s.lastId++
+	s.songs = append(s.songs, &songRecord{
+		id:       s.lastId,
+		songName: song.SongName,
+		groupId:  s.foundOrCreateGroup(song.GroupName).ID,
+	})
+	song.ID = s.lastId
+
+	now := time.Now().UTC()
+	s.lastJobId++
+	job.ID = s.lastJobId
+	job.SongID = song.ID
+	job.State = model.JobQueued
+	job.RunAt, job.CreatedAt, job.UpdatedAt = now, now, now
+	s.jobs = append(s.jobs, &jobRecord{job: *job})
+
+	return nil
+}
+
+// ClaimJob takes the next due job of the kind, or a running one whose lease
+// has passed, and holds it for lease.
+func (s *Storage) ClaimJob(kind string, lease time.Duration) (*model.Job, error) {
+	const op = "internal.storage.memory.ClaimJob()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var next *jobRecord
+	for _, rec := range s.jobs {
+		due := rec.job.State == model.JobQueued && !rec.job.RunAt.After(now) ||
+			rec.job.State == model.JobRunning && rec.lockedUntil.Before(now)
+		if rec.job.Kind != kind || !due || s.foundJobSong(rec.job.SongID) == nil {
+			continue
+		}
+		if next == nil || rec.job.RunAt.Before(next.job.RunAt) {
+			next = rec
+		}
+	}
+	if next == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrJobNotFound)
+	}
+
+	next.job.State = model.JobRunning
+	next.job.Attempts++
+	next.job.UpdatedAt = now
+	next.lockedUntil = now.Add(lease)
+
+	return s.copyJob(next), nil
+}
+
+// CompleteJob marks the claimed job succeeded.
+func (s *Storage) CompleteJob(job *model.Job) error {
+	const op = "internal.storage.memory.CompleteJob()"
+
+	err := s.finishJob(job, func(rec *jobRecord, now time.Time) {
+		rec.job.State = model.JobSucceeded
+		rec.job.LastError = ""
+		rec.job.FinishedAt = &now
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// RetryJob queues the claimed job again to run at runAt.
+func (s *Storage) RetryJob(job *model.Job, lastError string, runAt time.Time) error {
+	const op = "internal.storage.memory.RetryJob()"
+
+	err := s.finishJob(job, func(rec *jobRecord, now time.Time) {
+		rec.job.State = model.JobQueued
+		rec.job.LastError = lastError
+		rec.job.RunAt = runAt.UTC()
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// FailJob marks the claimed job failed for good.
+func (s *Storage) FailJob(job *model.Job, lastError string) error {
+	const op = "internal.storage.memory.FailJob()"
+
+	err := s.finishJob(job, func(rec *jobRecord, now time.Time) {
+		rec.job.State = model.JobFailed
+		rec.job.LastError = lastError
+		rec.job.FinishedAt = &now
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// finishJob ends the attempt of the job. A job claimed again after its lease
+// has passed belongs to the new attempt, and the old one changes nothing.
+func (s *Storage) finishJob(job *model.Job, finish func(rec *jobRecord, now time.Time)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundJob(job.ID)
+	if err != nil {
+		return err
+	}
+	if rec.job.State != model.JobRunning || rec.job.Attempts != job.Attempts {
+		return storage.ErrJobNotFound
+	}
+
+	now := time.Now().UTC()
+	finish(rec, now)
+	rec.job.UpdatedAt = now
+	rec.lockedUntil = time.Time{}
+	return nil
+}
+
+func (s *Storage) GetJob(id int64) (*model.Job, error) {
+	const op = "internal.storage.memory.GetJob()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundJob(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return s.copyJob(rec), nil
+}
+
+// foundJob must be called with s.mu held. The jobs of purged songs are gone
+// with them.
+func (s *Storage) foundJob(id int64) (*jobRecord, error) {
+	for _, rec := range s.jobs {
+		if rec.job.ID == id && s.foundJobSong(rec.job.SongID) != nil {
+			return rec, nil
+		}
+	}
+	return nil, storage.ErrJobNotFound
+}
+
+// copyJob must be called with s.mu held.
+func (s *Storage) copyJob(rec *jobRecord) *model.Job {
+	job := rec.job
+	if job.FinishedAt != nil {
+		finishedAt := *job.FinishedAt
+		job.FinishedAt = &finishedAt
+	}
+	if song := s.foundJobSong(job.SongID); song != nil {
+		job.Song = s.toSong(song)
+	}
+	return &job
+}
+
+// foundJobSong must be called with s.mu held. The song of a job may be in
+// the trash; it is nil once purged.
+func (s *Storage) foundJobSong(songId int64) *songRecord {
+	for _, songs := range [][]*songRecord{s.songs, s.trash} {
+		for _, song := range songs {
+			if song.id == songId {
+				return song
+			}
+		}
+	}
+	return nil
+}
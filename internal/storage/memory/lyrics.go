@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// PutLyrics replaces the time-synced lyrics of the song.
+func (s *Storage) PutLyrics(songId int64, lyrics *model.Lyrics) error {
+	const op = "internal.storage.memory.PutLyrics()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rec.lyrics = copyLyrics(lyrics)
+	rec.lyrics.SongID = songId
+	return nil
+}
+
+func (s *Storage) GetLyrics(songId int64) (*model.Lyrics, error) {
+	const op = "internal.storage.memory.GetLyrics()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundLyrics(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return copyLyrics(rec.lyrics), nil
+}
+
+// GetLyricLineAt returns the line being sung at the playback offset in milliseconds.
+func (s *Storage) GetLyricLineAt(songId int64, at int64) (*model.LyricLine, error) {
+	const op = "internal.storage.memory.GetLyricLineAt()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundLyrics(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	line := lrc.LineAt(rec.lyrics, at)
+	if line == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrLyricLineNotFound)
+	}
+	return copyLyricLine(line), nil
+}
+
+func (s *Storage) DeleteLyrics(songId int64) error {
+	const op = "internal.storage.memory.DeleteLyrics()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundLyrics(songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rec.lyrics = nil
+	return nil
+}
+
+// foundLyrics must be called with s.mu held.
+func (s *Storage) foundLyrics(songId int64) (*songRecord, error) {
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, err
+	}
+	if rec.lyrics == nil {
+		return nil, storage.ErrLyricsNotFound
+	}
+	return rec, nil
+}
+
+func copyLyrics(lyrics *model.Lyrics) *model.Lyrics {
+	copied := &model.Lyrics{
+		SongID: lyrics.SongID,
+		Tags:   maps.Clone(lyrics.Tags),
+		Lines:  make([]*model.LyricLine, 0, len(lyrics.Lines)),
+	}
+	for _, line := range lyrics.Lines {
+		copied.Lines = append(copied.Lines, copyLyricLine(line))
+	}
+	return copied
+}
+
+func copyLyricLine(line *model.LyricLine) *model.LyricLine {
+	copied := *line
+	copied.Words = nil
+	for _, word := range line.Words {
+		w := *word
+		copied.Words = append(copied.Words, &w)
+	}
+	return &copied
+}
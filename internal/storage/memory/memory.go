@@ -0,0 +1,500 @@
+package memory
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/cursor"
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/lib/trigram"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type Storage struct {
+	mu             sync.RWMutex
+	lastId         int64
+	lastGroupId    int64
+	lastAlbumId    int64
+	lastPlaylistId int64
+	lastAPIKeyId   int64
+	lastAuditId    int64
+	lastJobId      int64
+	lastEventId    int64
+	lastWebhookId  int64
+	lastDeliveryId int64
+	lastListenerId int64
+	songs          []*songRecord
+	trash          []*songRecord
+	groups         []*model.Group
+	albums         []*albumRecord
+	playlists      []*playlistRecord
+	apiKeys        []*model.APIKey
+	audit          []*model.AuditRecord
+	jobs           []*jobRecord
+	events         []*model.Event
+	webhooks       []*model.Webhook
+	deliveries     []*deliveryRecord
+	listeners      map[int64]func()
+}
+
+type songRecord struct {
+	id         int64
+	songName   string
+	groupId    int64
+	detail     *model.SongDetail
+	revisions  []*model.Revision
+	lyrics     *model.Lyrics
+	chordSheet *model.ChordSheet
+	// translations are keyed by language and hold no sections, they are
+	// aligned to the current lyrics when read.
+	translations map[string]*model.Translation
+	deletedAt    time.Time
+}
+
+func NewStorage() *Storage {
+	return &Storage{}
+}
+
+func (s *Storage) AddSong(song *model.Song) error {
+	const op = "internal.storage.memory.AddSong()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.foundSong(song); err == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
+	}
+
+	s.lastId++
+	s.songs = append(s.songs, &songRecord{
+		id:       s.lastId,
+		songName: song.SongName,
+		groupId:  s.foundOrCreateGroup(song.GroupName).ID,
+	})
+
+	return nil
+}
+
+func (s *Storage) DeleteSong(song *model.Song, log *slog.Logger) error {
+	const op = "internal.storage.memory.DeleteSong()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+	}
+
+	for i := range s.songs {
+		if s.songs[i] == rec {
+			s.songs = append(s.songs[:i], s.songs[i+1:]...)
+			break
+		}
+	}
+	rec.deletedAt = time.Now().UTC()
+	s.trash = append(s.trash, rec)
+
+	return nil
+}
+
+func (s *Storage) PutSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
+	const op = "internal.storage.memory.PutSongDetail()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return err
+	}
+	if rec.detail == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongDetailNotFound)
+	}
+
+	if _, err := saveSongDetail(rec, songDetail, change); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) AddSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
+	const op = "internal.storage.memory.AddSongDetail()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return err
+	}
+
+	if _, err := saveSongDetail(rec, songDetail, change); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// EnrichSongDetail adds the detail fetched for a new song unless the song
+// has one already, which is never overwritten.
+func (s *Storage) EnrichSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
+	const op = "internal.storage.memory.EnrichSongDetail()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return err
+	}
+	if rec.detail != nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongDetailExists)
+	}
+
+	if _, err := saveSongDetail(rec, songDetail, change); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) GetSongLibrary(filter *model.LibraryFilter, page *model.LibraryPage, log *slog.Logger) ([]*model.SongEdge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.filterLibrary(filter)
+	sortString := cursor.SortString(filter.Sort)
+
+	if page.Cursor != nil {
+		position := sort.Search(len(rows), func(i int) bool {
+			return compareKeyset(rows[i].values, rows[i].edge.Node.ID,
+				page.Cursor.Values, page.Cursor.ID, filter.Sort) > 0
+		})
+		if page.Backward {
+			rows = rows[:position]
+			for len(rows) > 0 && rows[len(rows)-1].edge.Node.ID == page.Cursor.ID {
+				rows = rows[:len(rows)-1]
+			}
+		} else {
+			rows = rows[position:]
+		}
+	}
+
+	library := make([]*model.SongEdge, 0, len(rows))
+	for _, row := range rows {
+		row.edge.Cursor = cursor.Encode(&model.LibraryCursor{
+			Sort:   sortString,
+			Values: row.values,
+			ID:     row.edge.Node.ID,
+		})
+		library = append(library, row.edge)
+	}
+
+	if int64(len(library)) > page.Limit {
+		if page.Backward {
+			library = library[int64(len(library))-page.Limit:]
+		} else {
+			library = library[:page.Limit]
+		}
+	}
+
+	return library, nil
+}
+
+// GetSong returns the names of the song by its id, for the handlers that
+// only get the id.
+func (s *Storage) GetSong(id int64) (*model.Song, error) {
+	const op = "internal.storage.memory.GetSong()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSongById(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return s.toSong(rec), nil
+}
+
+func (s *Storage) GetSongDetail(song *model.Song) (*model.SongDetail, error) {
+	const op = "internal.storage.memory.GetSongDetail()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return nil, err
+	}
+	if rec.detail == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrSongDetailNotFound)
+	}
+
+	detail := *rec.detail
+	detail.Sources = maps.Clone(detail.Sources)
+	return &detail, nil
+}
+
+// GetSongDetails returns the details of the songs with the ids, keyed by
+// song id. Songs without details are left out.
+func (s *Storage) GetSongDetails(songIds []int64) (map[int64]*model.SongDetail, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	details := make(map[int64]*model.SongDetail, len(songIds))
+	for _, songId := range songIds {
+		rec, err := s.foundSongById(songId)
+		if err != nil || rec.detail == nil {
+			continue
+		}
+
+		detail := *rec.detail
+		detail.Sources = maps.Clone(detail.Sources)
+		details[songId] = &detail
+	}
+
+	return details, nil
+}
+
+// GetSongSections returns the sections of the song lyrics. Time-synced
+// lyrics take the place of the detail text.
+func (s *Storage) GetSongSections(song *model.Song) ([]*model.LyricsSection, error) {
+	const op = "internal.storage.memory.GetSongSections()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return nil, err
+	}
+
+	lyricsSections, err := recordSections(rec)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return lyricsSections, nil
+}
+
+// recordSections must be called with s.mu held.
+func recordSections(rec *songRecord) ([]*model.LyricsSection, error) {
+	if rec.lyrics != nil {
+		return sections.Parse(lrc.PlainText(rec.lyrics)), nil
+	}
+	if rec.detail == nil {
+		return nil, storage.ErrSongDetailNotFound
+	}
+	return rec.detail.Sections, nil
+}
+
+func (s *Storage) CountNumberOfSong(filter *model.LibraryFilter) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.filterLibrary(filter))), nil
+}
+
+// libraryRow is a library edge together with the values of its sort keys.
+type libraryRow struct {
+	edge   *model.SongEdge
+	values []interface{}
+}
+
+// filterLibrary must be called with s.mu held. The result is ordered by
+// filter.Sort and then by id.
+func (s *Storage) filterLibrary(filter *model.LibraryFilter) []*libraryRow {
+	var rows []*libraryRow
+	for _, rec := range s.songs {
+		song := s.toSong(rec)
+		if rec.detail != nil {
+			song.ReleaseDate = rec.detail.ReleaseDate
+		}
+		if !matchReleased(rec, filter) {
+			continue
+		}
+		if ok, score := matchFilter(song, filter); ok {
+			edge := &model.SongEdge{Node: song, Score: score}
+			rows = append(rows, &libraryRow{
+				edge:   edge,
+				values: sortValues(rec, edge, filter.Sort),
+			})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return compareKeyset(rows[i].values, rows[i].edge.Node.ID,
+			rows[j].values, rows[j].edge.Node.ID, filter.Sort) < 0
+	})
+
+	return rows
+}
+
+func sortValues(rec *songRecord, edge *model.SongEdge, keys []model.SortKey) []interface{} {
+	values := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		switch key.Field {
+		case model.SortSong:
+			values = append(values, edge.Node.SongName)
+		case model.SortGroup:
+			values = append(values, edge.Node.GroupName)
+		case model.SortReleaseDate:
+			var releaseDate time.Time
+			if rec.detail != nil {
+				releaseDate, _, _ = releasedate.Parse(rec.detail.ReleaseDate)
+			}
+			values = append(values, releaseDate.Format("2006-01-02"))
+		case model.SortScore:
+			var score float64
+			if edge.Score != nil {
+				score = *edge.Score
+			}
+			values = append(values, score)
+		}
+	}
+	return values
+}
+
+// matchReleased checks the release date range of the filter; songs without
+// a release date never match a range.
+func matchReleased(rec *songRecord, filter *model.LibraryFilter) bool {
+	if filter.ReleasedFrom == nil && filter.ReleasedTo == nil {
+		return true
+	}
+	if rec.detail == nil {
+		return false
+	}
+
+	releaseDate, _, err := releasedate.Parse(rec.detail.ReleaseDate)
+	if err != nil {
+		return false
+	}
+	if filter.ReleasedFrom != nil && releaseDate.Before(*filter.ReleasedFrom) {
+		return false
+	}
+	if filter.ReleasedTo != nil && releaseDate.After(*filter.ReleasedTo) {
+		return false
+	}
+	return true
+}
+
+// compareKeyset orders two rows by their sort key values and then by id.
+func compareKeyset(a []interface{}, aId int64, b []interface{}, bId int64, keys []model.SortKey) int {
+	for i, key := range keys {
+		c := compareValues(a[i], b[i])
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aId < bId:
+		return -1
+	case aId > bId:
+		return 1
+	}
+	return 0
+}
+
+func compareValues(a interface{}, b interface{}) int {
+	switch a := a.(type) {
+	case float64:
+		b, _ := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(a, b)
+	}
+	return 0
+}
+
+// foundSong must be called with s.mu held.
+func (s *Storage) foundSong(song *model.Song) (*songRecord, error) {
+	const op = "internal.storage.memory.foundSong()"
+
+	for _, rec := range s.songs {
+		if found := s.toSong(rec); found.SongName == song.SongName && found.GroupName == song.GroupName {
+			return rec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+}
+
+// foundSongById must be called with s.mu held.
+func (s *Storage) foundSongById(id int64) (*songRecord, error) {
+	const op = "internal.storage.memory.foundSongById()"
+
+	for _, rec := range s.songs {
+		if rec.id == id {
+			return rec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+}
+
+// toSong must be called with s.mu held.
+func (s *Storage) toSong(rec *songRecord) *model.Song {
+	song := &model.Song{ID: rec.id, SongName: rec.songName}
+	if group, err := s.foundGroup(rec.groupId); err == nil {
+		song.GroupName = group.Name
+	}
+	return song
+}
+
+func matchFilter(song *model.Song, filter *model.LibraryFilter) (bool, *float64) {
+	if filter.Match != model.MatchFuzzy {
+		return matchValue(song.SongName, filter.SongName, filter.Match) &&
+			matchValue(song.GroupName, filter.GroupName, filter.Match), nil
+	}
+
+	var score float64
+	var scored int
+	for _, pair := range [][2]string{{song.SongName, filter.SongName}, {song.GroupName, filter.GroupName}} {
+		if pair[1] == "" {
+			continue
+		}
+		similarity := trigram.Similarity(pair[0], pair[1])
+		if similarity < filter.Threshold {
+			return false, nil
+		}
+		score += similarity
+		scored++
+	}
+	if scored == 0 {
+		return true, nil
+	}
+
+	score /= float64(scored)
+	return true, &score
+}
+
+func matchValue(value string, pattern string, match string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	switch match {
+	case model.MatchPrefix:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(pattern))
+	case model.MatchContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(pattern))
+	}
+	return value == pattern
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type playlistRecord struct {
+	id      int64
+	name    string
+	entries []int64
+}
+
+func (s *Storage) AddPlaylist(playlist *model.Playlist) error {
+	const op = "internal.storage.memory.AddPlaylist()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range s.playlists {
+		if rec.name == playlist.Name {
+			return fmt.Errorf("%s:%w", op, storage.ErrPlaylistAlreadyExists)
+		}
+	}
+
+	s.lastPlaylistId++
+	playlist.ID = s.lastPlaylistId
+	playlist.Entries = []*model.PlaylistEntry{}
+	s.playlists = append(s.playlists, &playlistRecord{
+		id:   playlist.ID,
+		name: playlist.Name,
+	})
+
+	return nil
+}
+
+func (s *Storage) GetPlaylist(id int64) (*model.Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundPlaylist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist := &model.Playlist{
+		ID:      rec.id,
+		Name:    rec.name,
+		Entries: []*model.PlaylistEntry{},
+	}
+	for i, songId := range rec.entries {
+		songRec, err := s.foundSongById(songId)
+		if err != nil {
+			continue
+		}
+		entry := &model.PlaylistEntry{
+			Position: i + 1,
+			Song:     s.toSong(songRec),
+		}
+		if songRec.detail != nil {
+			entry.Link = songRec.detail.Link
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+
+	return playlist, nil
+}
+
+func (s *Storage) DeletePlaylist(id int64) error {
+	const op = "internal.storage.memory.DeletePlaylist()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rec := range s.playlists {
+		if rec.id == id {
+			s.playlists = append(s.playlists[:i], s.playlists[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s:%w", op, storage.ErrPlaylistNotFound)
+}
+
+func (s *Storage) AddPlaylistEntry(playlistId int64, song *model.Song, position int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundPlaylist(playlistId)
+	if err != nil {
+		return err
+	}
+
+	var songRec *songRecord
+	if song.ID != 0 {
+		songRec, err = s.foundSongById(song.ID)
+	} else {
+		songRec, err = s.foundSong(song)
+	}
+	if err != nil {
+		return err
+	}
+
+	rec.entries = storage.InsertAt(rec.entries, songRec.id, position)
+	return nil
+}
+
+func (s *Storage) MovePlaylistEntry(playlistId int64, from int, to int) error {
+	const op = "internal.storage.memory.MovePlaylistEntry()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundPlaylist(playlistId)
+	if err != nil {
+		return err
+	}
+
+	entries, err := storage.Move(rec.entries, from, to)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	rec.entries = entries
+
+	return nil
+}
+
+func (s *Storage) DeletePlaylistEntry(playlistId int64, position int) error {
+	const op = "internal.storage.memory.DeletePlaylistEntry()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundPlaylist(playlistId)
+	if err != nil {
+		return err
+	}
+
+	entries, err := storage.RemoveAt(rec.entries, position)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	rec.entries = entries
+
+	return nil
+}
+
+// foundPlaylist must be called with s.mu held.
+func (s *Storage) foundPlaylist(id int64) (*playlistRecord, error) {
+	const op = "internal.storage.memory.foundPlaylist()"
+
+	for _, rec := range s.playlists {
+		if rec.id == id {
+			return rec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrPlaylistNotFound)
+}
+
+// removePlaylistEntries must be called with s.mu held for writing.
+func (s *Storage) removePlaylistEntries(songId int64) {
+	for _, rec := range s.playlists {
+		entries := rec.entries[:0]
+		for _, id := range rec.entries {
+			if id != songId {
+				entries = append(entries, id)
+			}
+		}
+		rec.entries = entries
+	}
+}
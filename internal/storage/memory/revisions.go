@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// GetRevisions lists the revisions of the song detail, newest first.
+func (s *Storage) GetRevisions(songId int64, limit int64, offset int64) ([]*model.Revision, error) {
+	const op = "internal.storage.memory.GetRevisions()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	var revisions []*model.Revision
+	for i := int64(len(rec.revisions)) - 1 - max(offset, 0); i >= 0 && int64(len(revisions)) < limit; i-- {
+		revisions = append(revisions, copyRevision(rec.revisions[i]))
+	}
+
+	return revisions, nil
+}
+
+func (s *Storage) CountRevisions(songId int64) (int64, error) {
+	const op = "internal.storage.memory.CountRevisions()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return int64(len(rec.revisions)), nil
+}
+
+func (s *Storage) GetRevision(songId int64, revision int64) (*model.Revision, error) {
+	const op = "internal.storage.memory.GetRevision()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rev, err := s.foundRevision(songId, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return copyRevision(rev), nil
+}
+
+// RestoreRevision writes the detail of an old revision back as a new revision.
+func (s *Storage) RestoreRevision(songId int64, revision int64, change *model.DetailChange) (*model.Revision, error) {
+	const op = "internal.storage.memory.RestoreRevision()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.foundRevision(songId, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	restored, err := saveSongDetail(rec, old.Detail, change)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return copyRevision(restored), nil
+}
+
+// saveSongDetail must be called with s.mu held. It replaces the detail of
+// the song and records it as the next revision.
+func saveSongDetail(rec *songRecord, songDetail *model.SongDetail, change *model.DetailChange) (*model.Revision, error) {
+	detail := *songDetail
+	// A revision may have lost its date to the migration and is restored without one.
+	if strings.TrimSpace(detail.ReleaseDate) != "" {
+		if err := releasedate.Normalize(&detail); err != nil {
+			return nil, err
+		}
+	}
+	detail.Sections = sections.FromDetail(&detail)
+	detail.Sources = maps.Clone(detail.Sources)
+	rec.detail = &detail
+
+	// Revisions keep the text only, the sections are parsed from it again.
+	revisionDetail := detail
+	revisionDetail.Sections = nil
+	revisionDetail.Sources = nil
+	revision := &model.Revision{
+		Revision:  int64(len(rec.revisions)) + 1,
+		SongID:    rec.id,
+		Detail:    &revisionDetail,
+		Author:    change.Author,
+		Message:   change.Message,
+		CreatedAt: time.Now().UTC(),
+	}
+	rec.revisions = append(rec.revisions, revision)
+
+	return revision, nil
+}
+
+// foundRevision must be called with s.mu held.
+func (s *Storage) foundRevision(songId int64, revision int64) (*model.Revision, error) {
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, err
+	}
+	if revision < 1 || revision > int64(len(rec.revisions)) {
+		return nil, storage.ErrRevisionNotFound
+	}
+
+	return rec.revisions[revision-1], nil
+}
+
+func copyRevision(revision *model.Revision) *model.Revision {
+	rev := *revision
+	detail := *revision.Detail
+	rev.Detail = &detail
+	return &rev
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// SearchSongs matches songs whose text contains every word of query, ignoring
+// case. language is accepted for parity with the Postgres storage; there is
+// no stemming here.
+func (s *Storage) SearchSongs(query string, language string, limit int64, offset int64) ([]*model.SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := s.search(query)
+	if offset >= int64(len(results)) {
+		return nil, nil
+	}
+	results = results[max(offset, 0):]
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (s *Storage) CountSearchSongs(query string, language string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.search(query))), nil
+}
+
+// search must be called with s.mu held.
+func (s *Storage) search(query string) []*model.SearchResult {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	var results []*model.SearchResult
+	for _, rec := range s.songs {
+		if rec.detail == nil {
+			continue
+		}
+		text := strings.ToLower(rec.detail.Text)
+
+		var hits int
+		for _, word := range words {
+			count := strings.Count(text, word)
+			if count == 0 {
+				hits = 0
+				break
+			}
+			hits += count
+		}
+		if hits == 0 {
+			continue
+		}
+
+		results = append(results, &model.SearchResult{
+			Song:    s.toSong(rec),
+			Rank:    float64(hits) / float64(len(strings.Fields(text))),
+			Snippet: snippet(rec.detail.Text, words),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	return results
+}
+
+// snippet returns the first line holding one of words with the matches wrapped in <b></b>.
+func snippet(text string, words []string) string {
+	for _, line := range strings.Split(text, "\n") {
+		lower := strings.ToLower(line)
+		for _, word := range words {
+			if strings.Contains(lower, word) {
+				return highlight(line, words)
+			}
+		}
+	}
+	return ""
+}
+
+func highlight(line string, words []string) string {
+	var b strings.Builder
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		return line
+	}
+
+	for i := 0; i < len(line); {
+		matched := ""
+		for _, word := range words {
+			if strings.HasPrefix(lower[i:], word) && len(word) > len(matched) {
+				matched = word
+			}
+		}
+		if matched == "" {
+			b.WriteByte(line[i])
+			i++
+			continue
+		}
+		b.WriteString("<b>" + line[i:i+len(matched)] + "</b>")
+		i += len(matched)
+	}
+
+	return b.String()
+}
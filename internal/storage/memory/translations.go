@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// PutTranslation replaces the translation of the song to the language of
+// the translation. It must have as many sections as the song lyrics.
+func (s *Storage) PutTranslation(translation *model.Translation) error {
+	const op = "internal.storage.memory.PutTranslation()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSongById(translation.SongID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	original, err := recordSections(rec)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	translated := sections.ParseTranslation(translation.Text)
+	if !sections.Align(translated, original) {
+		return fmt.Errorf("%s:%w: %d sections for %d", op, storage.ErrTranslationNotAligned,
+			len(translated), len(original))
+	}
+
+	if rec.translations == nil {
+		rec.translations = make(map[string]*model.Translation)
+	}
+	translation.UpdatedAt = time.Now().UTC()
+	rec.translations[translation.Language] = &model.Translation{
+		SongID:    translation.SongID,
+		Language:  translation.Language,
+		Text:      translation.Text,
+		UpdatedAt: translation.UpdatedAt,
+	}
+
+	translation.Sections = translated
+	return nil
+}
+
+func (s *Storage) GetTranslation(songId int64, language string) (*model.Translation, error) {
+	const op = "internal.storage.memory.GetTranslation()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	translation, ok := rec.translations[language]
+	if !ok {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrTranslationNotFound)
+	}
+
+	return alignTranslation(rec, translation), nil
+}
+
+// GetTranslations lists the translations of the song by language.
+func (s *Storage) GetTranslations(songId int64) ([]*model.Translation, error) {
+	const op = "internal.storage.memory.GetTranslations()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return recordTranslations(rec), nil
+}
+
+// GetSongTranslations is GetTranslations for the song given by its name and group.
+func (s *Storage) GetSongTranslations(song *model.Song) ([]*model.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, err := s.foundSong(song)
+	if err != nil {
+		return nil, err
+	}
+
+	return recordTranslations(rec), nil
+}
+
+func (s *Storage) DeleteTranslation(songId int64, language string) error {
+	const op = "internal.storage.memory.DeleteTranslation()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundSongById(songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if _, ok := rec.translations[language]; !ok {
+		return fmt.Errorf("%s:%w", op, storage.ErrTranslationNotFound)
+	}
+
+	delete(rec.translations, language)
+	return nil
+}
+
+// recordTranslations must be called with s.mu held.
+func recordTranslations(rec *songRecord) []*model.Translation {
+	translations := make([]*model.Translation, 0, len(rec.translations))
+	for _, translation := range rec.translations {
+		translations = append(translations, alignTranslation(rec, translation))
+	}
+
+	sort.Slice(translations, func(i, j int) bool {
+		return translations[i].Language < translations[j].Language
+	})
+	return translations
+}
+
+// alignTranslation must be called with s.mu held. It copies the translation
+// split into sections aligned to the current sections of the song lyrics, as
+// far as they still match them.
+func alignTranslation(rec *songRecord, translation *model.Translation) *model.Translation {
+	aligned := *translation
+	aligned.Sections = sections.ParseTranslation(translation.Text)
+
+	// A song without text has no sections to align to.
+	original, _ := recordSections(rec)
+	sections.Align(aligned.Sections, original)
+	return &aligned
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// GetTrash lists the deleted songs, most recently deleted first.
+func (s *Storage) GetTrash(limit int64, offset int64) ([]*model.Song, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trash := make([]*songRecord, len(s.trash))
+	copy(trash, s.trash)
+	sort.SliceStable(trash, func(i, j int) bool {
+		return trash[i].deletedAt.After(trash[j].deletedAt)
+	})
+
+	var songs []*model.Song
+	for i := max(offset, 0); i < int64(len(trash)) && int64(len(songs)) < limit; i++ {
+		song := s.toSong(trash[i])
+		deletedAt := trash[i].deletedAt
+		song.DeletedAt = &deletedAt
+		songs = append(songs, song)
+	}
+
+	return songs, nil
+}
+
+func (s *Storage) CountTrash() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.trash)), nil
+}
+
+// RestoreSong takes a song out of the trash unless another song with the
+// same name was added to its group in the meantime.
+func (s *Storage) RestoreSong(id int64) (*model.Song, error) {
+	const op = "internal.storage.memory.RestoreSong()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rec := range s.trash {
+		if rec.id != id {
+			continue
+		}
+
+		song := s.toSong(rec)
+		if _, err := s.foundSong(song); err == nil {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
+		}
+
+		s.trash = append(s.trash[:i], s.trash[i+1:]...)
+		rec.deletedAt = time.Time{}
+		s.songs = append(s.songs, rec)
+		return song, nil
+	}
+
+	return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+}
+
+// PurgeSongs removes for good the songs deleted before the given time, with
+// their album tracks and playlist entries.
+func (s *Storage) PurgeSongs(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	trash := s.trash[:0]
+	for _, rec := range s.trash {
+		if rec.deletedAt.Before(before) {
+			s.removeTrack(rec.id)
+			s.removePlaylistEntries(rec.id)
+			purged++
+			continue
+		}
+		trash = append(trash, rec)
+	}
+	s.trash = trash
+
+	return purged, nil
+}
@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// deliveryRecord holds the delivery without its webhook and event, which
+// are looked up when it's read.
+type deliveryRecord struct {
+	delivery    model.WebhookDelivery
+	eventId     int64
+	lockedUntil time.Time
+}
+
+func (s *Storage) AddWebhook(hook *model.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastWebhookId++
+	hook.ID = s.lastWebhookId
+	hook.CreatedAt = time.Now().UTC()
+
+	stored := *hook
+	stored.Events = slices.Clone(hook.Events)
+	s.webhooks = append(s.webhooks, &stored)
+	return nil
+}
+
+func (s *Storage) GetWebhooks() ([]*model.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hooks := make([]*model.Webhook, 0, len(s.webhooks))
+	for _, hook := range s.webhooks {
+		copied := *hook
+		copied.Events = slices.Clone(hook.Events)
+		hooks = append(hooks, &copied)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes the webhook together with its deliveries.
+func (s *Storage) DeleteWebhook(id int64) error {
+	const op = "internal.storage.memory.DeleteWebhook()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.webhooks, func(hook *model.Webhook) bool { return hook.ID == id })
+	if i < 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrWebhookNotFound)
+	}
+	s.webhooks = slices.Delete(s.webhooks, i, i+1)
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(rec *deliveryRecord) bool {
+		return rec.delivery.WebhookID == id
+	})
+	return nil
+}
+
+// ClaimDelivery takes the next due delivery, or a running one whose lease
+// has passed, and holds it for lease.
+func (s *Storage) ClaimDelivery(lease time.Duration) (*model.WebhookDelivery, error) {
+	const op = "internal.storage.memory.ClaimDelivery()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var next *deliveryRecord
+	for _, rec := range s.deliveries {
+		due := rec.delivery.State == model.JobQueued && !rec.delivery.RunAt.After(now) ||
+			rec.delivery.State == model.JobRunning && rec.lockedUntil.Before(now)
+		if !due {
+			continue
+		}
+		if next == nil || rec.delivery.RunAt.Before(next.delivery.RunAt) {
+			next = rec
+		}
+	}
+	if next == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrDeliveryNotFound)
+	}
+
+	next.delivery.State = model.JobRunning
+	next.delivery.Attempts++
+	next.delivery.UpdatedAt = now
+	next.lockedUntil = now.Add(lease)
+
+	return s.copyDelivery(next), nil
+}
+
+// CompleteDelivery marks the claimed delivery succeeded and logs its attempt.
+func (s *Storage) CompleteDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error {
+	const op = "internal.storage.memory.CompleteDelivery()"
+
+	err := s.finishDelivery(delivery, attempt, func(rec *deliveryRecord, now time.Time) {
+		rec.delivery.State = model.JobSucceeded
+		rec.delivery.LastError = ""
+		rec.delivery.FinishedAt = &now
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// RetryDelivery logs the failed attempt and queues the delivery again to
+// run at runAt.
+func (s *Storage) RetryDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt, runAt time.Time) error {
+	const op = "internal.storage.memory.RetryDelivery()"
+
+	err := s.finishDelivery(delivery, attempt, func(rec *deliveryRecord, now time.Time) {
+		rec.delivery.State = model.JobQueued
+		rec.delivery.LastError = attempt.Error
+		rec.delivery.RunAt = runAt.UTC()
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// FailDelivery logs the failed attempt and marks the delivery failed for good.
+func (s *Storage) FailDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error {
+	const op = "internal.storage.memory.FailDelivery()"
+
+	err := s.finishDelivery(delivery, attempt, func(rec *deliveryRecord, now time.Time) {
+		rec.delivery.State = model.JobFailed
+		rec.delivery.LastError = attempt.Error
+		rec.delivery.FinishedAt = &now
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// finishDelivery ends the attempt of the delivery and logs it, unless the
+// delivery has been claimed again since.
+func (s *Storage) finishDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt, finish func(rec *deliveryRecord, now time.Time)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundDelivery(delivery.ID)
+	if err != nil {
+		return err
+	}
+	if rec.delivery.State != model.JobRunning || rec.delivery.Attempts != delivery.Attempts {
+		return storage.ErrDeliveryNotFound
+	}
+
+	now := time.Now().UTC()
+	finish(rec, now)
+	rec.delivery.UpdatedAt = now
+	rec.lockedUntil = time.Time{}
+
+	logged := *attempt
+	logged.CreatedAt = now
+	rec.delivery.Log = append(rec.delivery.Log, &logged)
+	attempt.CreatedAt = now
+	return nil
+}
+
+// GetWebhookDeliveries returns the last deliveries to the webhook, newest
+// first, in the state if one is given, with the log of their attempts.
+func (s *Storage) GetWebhookDeliveries(webhookId int64, state string, first int) ([]*model.WebhookDelivery, error) {
+	const op = "internal.storage.memory.GetWebhookDeliveries()"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.foundWebhook(webhookId) == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrWebhookNotFound)
+	}
+
+	deliveries := []*model.WebhookDelivery{}
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < first; i-- {
+		rec := s.deliveries[i]
+		if rec.delivery.WebhookID != webhookId || state != "" && rec.delivery.State != state {
+			continue
+		}
+		deliveries = append(deliveries, s.copyDelivery(rec))
+	}
+	return deliveries, nil
+}
+
+// ReplayDelivery queues the event of the delivery to its webhook once more,
+// as a new delivery.
+func (s *Storage) ReplayDelivery(id int64) (*model.WebhookDelivery, error) {
+	const op = "internal.storage.memory.ReplayDelivery()"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.foundDelivery(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	replayed := s.queueDelivery(rec.delivery.WebhookID, rec.eventId, id, time.Now().UTC())
+	return s.copyDelivery(replayed), nil
+}
+
+// queueDelivery must be called with s.mu held.
+func (s *Storage) queueDelivery(webhookId int64, eventId int64, replayOf int64, now time.Time) *deliveryRecord {
+	s.lastDeliveryId++
+	rec := &deliveryRecord{
+		delivery: model.WebhookDelivery{
+			ID:        s.lastDeliveryId,
+			WebhookID: webhookId,
+			State:     model.JobQueued,
+			ReplayOf:  replayOf,
+			RunAt:     now,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		eventId: eventId,
+	}
+	s.deliveries = append(s.deliveries, rec)
+	return rec
+}
+
+// foundDelivery must be called with s.mu held.
+func (s *Storage) foundDelivery(id int64) (*deliveryRecord, error) {
+	for _, rec := range s.deliveries {
+		if rec.delivery.ID == id {
+			return rec, nil
+		}
+	}
+	return nil, storage.ErrDeliveryNotFound
+}
+
+// foundWebhook must be called with s.mu held.
+func (s *Storage) foundWebhook(id int64) *model.Webhook {
+	for _, hook := range s.webhooks {
+		if hook.ID == id {
+			return hook
+		}
+	}
+	return nil
+}
+
+// copyDelivery must be called with s.mu held.
+func (s *Storage) copyDelivery(rec *deliveryRecord) *model.WebhookDelivery {
+	delivery := rec.delivery
+	if delivery.FinishedAt != nil {
+		finishedAt := *delivery.FinishedAt
+		delivery.FinishedAt = &finishedAt
+	}
+	delivery.Log = make([]*model.DeliveryAttempt, 0, len(rec.delivery.Log))
+	for _, attempt := range rec.delivery.Log {
+		copied := *attempt
+		delivery.Log = append(delivery.Log, &copied)
+	}
+	if hook := s.foundWebhook(delivery.WebhookID); hook != nil {
+		delivery.URL = hook.URL
+		delivery.Secret = hook.Secret
+	}
+	if event := s.foundEvent(rec.eventId); event != nil {
+		delivery.Event = copyEvent(event)
+	}
+	return &delivery
+}
@@ -0,0 +1,33 @@
+package storage
+
+// InsertAt puts id at the 1-based position, appending it when position is 0
+// or past the end.
+func InsertAt(ids []int64, id int64, position int) []int64 {
+	if position <= 0 || position > len(ids) {
+		return append(ids, id)
+	}
+
+	ids = append(ids[:position], ids[position-1:]...)
+	ids[position-1] = id
+	return ids
+}
+
+// RemoveAt drops the entry at the 1-based position.
+func RemoveAt(ids []int64, position int) ([]int64, error) {
+	if position <= 0 || position > len(ids) {
+		return nil, ErrEntryNotFound
+	}
+
+	return append(ids[:position-1], ids[position:]...), nil
+}
+
+// Move relocates the entry at the 1-based position from to the 1-based position to.
+func Move(ids []int64, from int, to int) ([]int64, error) {
+	if from <= 0 || from > len(ids) || to <= 0 || to > len(ids) {
+		return nil, ErrEntryNotFound
+	}
+
+	id := ids[from-1]
+	ids, _ = RemoveAt(ids, from)
+	return InsertAt(ids, id, to), nil
+}
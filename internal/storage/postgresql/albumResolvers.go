@@ -0,0 +1,199 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (r *Database) AddAlbum(album *model.Album) error {
+	const op = "internal.storage.postgresql.AddAlbum()"
+
+	groupId, err := r.foundOrCreateGroupId(album.GroupName)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	err = r.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM albums WHERE group_id = $1 AND title = $2)",
+		groupId, album.Title).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if exists {
+		return fmt.Errorf("%s:%w", op, storage.ErrAlbumAlreadyExists)
+	}
+
+	err = r.DB.QueryRow("INSERT INTO albums (title, group_id, release_date) VALUES ($1, $2, $3) RETURNING id",
+		album.Title, groupId, album.ReleaseDate).Scan(&album.ID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) GetAlbum(id int64) (*model.Album, error) {
+	const op = "internal.storage.postgresql.GetAlbum()"
+
+	var album model.Album
+	err := r.DB.Get(&album, `SELECT albums.id, albums.title, groups.name AS group_name,
+		to_char(albums.release_date, 'YYYY-MM-DD') AS release_date
+		FROM albums JOIN groups ON groups.id = albums.group_id WHERE albums.id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrAlbumNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &album, nil
+}
+
+func (r *Database) AddAlbumTrack(albumId int64, song *model.Song, position int) error {
+	const op = "internal.storage.postgresql.AddAlbumTrack()"
+
+	if _, err := r.GetAlbum(albumId); err != nil {
+		return err
+	}
+
+	songId, err := r.foundSongId(song)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM album_tracks WHERE album_id = $1 AND song_id = $2)",
+		albumId, songId).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if exists {
+		return fmt.Errorf("%s:%w", op, storage.ErrTrackAlreadyExists)
+	}
+
+	var lastPosition int
+	err = tx.QueryRow("SELECT COALESCE(MAX(position), 0) FROM album_tracks WHERE album_id = $1",
+		albumId).Scan(&lastPosition)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if position <= 0 || position > lastPosition {
+		position = lastPosition + 1
+	} else {
+		_, err = tx.Exec("UPDATE album_tracks SET position = position + 1 WHERE album_id = $1 AND position >= $2",
+			albumId, position)
+		if err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO album_tracks (album_id, song_id, position) VALUES ($1, $2, $3)",
+		albumId, songId, position)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) SetAlbumTracks(albumId int64, songs []*model.Song) error {
+	const op = "internal.storage.postgresql.SetAlbumTracks()"
+
+	if _, err := r.GetAlbum(albumId); err != nil {
+		return err
+	}
+
+	songIds := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		songId, err := r.foundSongId(song)
+		if err != nil {
+			return err
+		}
+		for _, id := range songIds {
+			if id == songId {
+				return fmt.Errorf("%s:%w", op, storage.ErrTrackAlreadyExists)
+			}
+		}
+		songIds = append(songIds, songId)
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = insertAlbumTracks(tx, albumId, songIds); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) GetAlbumTracks(albumId int64, limit int64, offset int64) ([]*model.Song, error) {
+	const op = "internal.storage.postgresql.GetAlbumTracks()"
+
+	var tracks []*model.Song
+	err := r.DB.Select(&tracks, `SELECT songs.id, songs.song_name, groups.name AS group_name
+		FROM album_tracks
+		JOIN songs ON songs.id = album_tracks.song_id
+		JOIN groups ON groups.id = songs.group_id
+		WHERE album_tracks.album_id = $1 AND songs.deleted_at IS NULL
+		ORDER BY album_tracks.position LIMIT $2 OFFSET $3`, albumId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return tracks, nil
+}
+
+func (r *Database) CountAlbumTracks(albumId int64) (int64, error) {
+	const op = "internal.storage.postgresql.CountAlbumTracks()"
+
+	var count int64
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM album_tracks
+		JOIN songs ON songs.id = album_tracks.song_id
+		WHERE album_tracks.album_id = $1 AND songs.deleted_at IS NULL`, albumId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return count, nil
+}
+
+func insertAlbumTracks(tx *sqlx.Tx, albumId int64, songIds []int64) error {
+	_, err := tx.Exec("DELETE FROM album_tracks WHERE album_id = $1", albumId)
+	if err != nil {
+		return err
+	}
+
+	for i, songId := range songIds {
+		_, err = tx.Exec("INSERT INTO album_tracks (album_id, song_id, position) VALUES ($1, $2, $3)",
+			albumId, songId, i+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (r *Database) AddAPIKey(key *model.APIKey) error {
+	const op = "internal.storage.postgresql.AddAPIKey()"
+
+	err := r.DB.QueryRow("INSERT INTO api_keys (name, key_hash, role) VALUES ($1, $2, $3) RETURNING id, created_at",
+		key.Name, key.Hash, key.Role).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) GetAPIKeys() ([]*model.APIKey, error) {
+	const op = "internal.storage.postgresql.GetAPIKeys()"
+
+	var keys []*model.APIKey
+	err := r.DB.Select(&keys, "SELECT id, name, key_hash, role, created_at, revoked_at FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (r *Database) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	const op = "internal.storage.postgresql.GetAPIKeyByHash()"
+
+	var key model.APIKey
+	err := r.DB.Get(&key, `SELECT id, name, key_hash, role, created_at, revoked_at FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL`, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrAPIKeyNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &key, nil
+}
+
+func (r *Database) RotateAPIKey(id int64, hash string) (*model.APIKey, error) {
+	const op = "internal.storage.postgresql.RotateAPIKey()"
+
+	var key model.APIKey
+	err := r.DB.Get(&key, `UPDATE api_keys SET key_hash = $1 WHERE id = $2 AND revoked_at IS NULL
+		RETURNING id, name, key_hash, role, created_at, revoked_at`, hash, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrAPIKeyNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &key, nil
+}
+
+func (r *Database) RevokeAPIKey(id int64) error {
+	const op = "internal.storage.postgresql.RevokeAPIKey()"
+
+	res, err := r.DB.Exec("UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package postgresql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+func (r *Database) AddAuditRecord(record *model.AuditRecord) error {
+	const op = "internal.storage.postgresql.AddAuditRecord()"
+
+	err := r.DB.QueryRow(`INSERT INTO audit_log
+		(operation, song_name, group_name, request_id, remote_addr, actor, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8::jsonb)
+		RETURNING id, created_at`,
+		record.Operation, record.SongName, record.GroupName, record.RequestID, record.RemoteAddr, record.Actor,
+		jsonArg(record.Before), jsonArg(record.After)).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+// GetAuditRecords lists the audit records matching the filter, newest first.
+func (r *Database) GetAuditRecords(filter *model.AuditFilter, limit int64, offset int64) ([]*model.AuditRecord, error) {
+	const op = "internal.storage.postgresql.GetAuditRecords()"
+
+	conditions, args := auditConditions(filter)
+	args = append(args, limit, offset)
+
+	var records []*model.AuditRecord
+	err := r.DB.Select(&records, `SELECT id, operation, song_name, group_name, request_id, remote_addr, actor,
+			COALESCE(before, 'null'::jsonb) AS before, COALESCE(after, 'null'::jsonb) AS after, created_at
+		FROM audit_log WHERE TRUE`+conditions+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $`+strconv.Itoa(len(args)-1)+` OFFSET $`+strconv.Itoa(len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return records, nil
+}
+
+func (r *Database) CountAuditRecords(filter *model.AuditFilter) (int64, error) {
+	const op = "internal.storage.postgresql.CountAuditRecords()"
+
+	conditions, args := auditConditions(filter)
+
+	var count int64
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM audit_log WHERE TRUE"+conditions, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return count, nil
+}
+
+func auditConditions(filter *model.AuditFilter) (string, []interface{}) {
+	var conditions string
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions += " AND " + condition + " $" + strconv.Itoa(len(args))
+	}
+	if filter.SongName != "" {
+		addCondition("song_name =", filter.SongName)
+	}
+	if filter.GroupName != "" {
+		addCondition("group_name =", filter.GroupName)
+	}
+	if filter.Operation != "" {
+		addCondition("operation =", filter.Operation)
+	}
+	if filter.From != nil {
+		addCondition("created_at >=", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at <=", *filter.To)
+	}
+
+	return conditions, args
+}
+
+func jsonArg(value []byte) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
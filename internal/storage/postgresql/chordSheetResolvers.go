@@ -0,0 +1,116 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/lib/chordpro"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// PutChordSheet replaces the chord sheet of the song. When the song has a
+// detail, its text becomes the chord-free lyrics of the sheet as a new
+// revision.
+func (r *Database) PutChordSheet(songId int64, sheet *model.ChordSheet, change *model.DetailChange) error {
+	const op = "internal.storage.postgresql.PutChordSheet()"
+
+	data, err := json.Marshal(sheet)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow("SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", songId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+		}
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO songs_chord_sheets (song_id, sheet) VALUES ($1, $2::jsonb)
+		ON CONFLICT (song_id) DO UPDATE SET sheet = EXCLUDED.sheet, updated_at = now()`,
+		songId, string(data))
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	var songDetail model.SongDetail
+	var releaseDate sql.NullString
+	var sourcesData []byte
+	err = tx.QueryRow("SELECT "+releaseDateISO("songs_detail")+`, release_date_precision, link, text, sources
+		FROM songs_detail WHERE song_id = $1`,
+		songId).Scan(&releaseDate, &songDetail.ReleaseDatePrecision, &songDetail.Link, &songDetail.Text, &sourcesData)
+	hasDetail := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	songDetail.ReleaseDate = releaseDate.String
+
+	if text := chordpro.PlainText(sheet); hasDetail && text != songDetail.Text {
+		songDetail.Text = text
+		// The text comes from the sheet now, the other fields keep their source.
+		songDetail.Sources, err = detailSources(sourcesData)
+		if err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+		delete(songDetail.Sources, model.FieldText)
+		if _, err := saveSongDetailTx(tx, songId, &songDetail, change, detailUpdate); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+func (r *Database) GetChordSheet(songId int64) (*model.ChordSheet, error) {
+	const op = "internal.storage.postgresql.GetChordSheet()"
+
+	var data []byte
+	err := r.DB.QueryRow(`SELECT songs_chord_sheets.sheet FROM songs
+		LEFT JOIN songs_chord_sheets ON songs_chord_sheets.song_id = songs.id
+		WHERE songs.id = $1 AND songs.deleted_at IS NULL`, songId).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrChordSheetNotFound)
+	}
+
+	var sheet model.ChordSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	sheet.SongID = songId
+
+	return &sheet, nil
+}
+
+func (r *Database) DeleteChordSheet(songId int64) error {
+	const op = "internal.storage.postgresql.DeleteChordSheet()"
+
+	if _, err := r.GetChordSheet(songId); err != nil {
+		return err
+	}
+
+	_, err := r.DB.Exec("DELETE FROM songs_chord_sheets WHERE song_id = $1", songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
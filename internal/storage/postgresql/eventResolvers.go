@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// eventsChannel is notified of the id of every event added to the log.
+const eventsChannel = "library_events"
+
+// eventsLock is the advisory lock serializing the events added to the log.
+const eventsLock = 7120240601
+
+// AddEvent appends the event to the event log and queues its delivery to
+// every webhook subscribed to its type, in one transaction. Readers resume
+// after the last id they saw, so ids must become visible in order: the lock
+// held to the commit keeps a later id from committing before an earlier one.
+func (r *Database) AddEvent(event *model.Event) error {
+	const op = "internal.storage.postgresql.AddEvent()"
+
+	var songId *int64
+	if event.Song.ID != 0 {
+		songId = &event.Song.ID
+	}
+	var data *string
+	if event.Data != nil {
+		data = new(string)
+		*data = string(event.Data)
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", eventsLock); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	err = tx.QueryRow(`INSERT INTO events (type, song_id, song_name, group_name, data, request_id)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6)
+		RETURNING id, created_at`,
+		event.Type, songId, event.Song.SongName, event.Song.GroupName, data, event.RequestID).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_id)
+		SELECT id, $1 FROM webhooks WHERE events @> jsonb_build_array($2::text)`,
+		event.ID, event.Type)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// GetEvents returns up to limit events of the log selected by the filter
+// that follow the event with the id after, oldest first.
+func (r *Database) GetEvents(filter *model.EventFilter, after int64, limit int) ([]*model.Event, error) {
+	const op = "internal.storage.postgresql.GetEvents()"
+
+	// An empty array rather than null, which isn't an array at all.
+	types, err := json.Marshal(append([]string{}, filter.Types...))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	rows, err := r.DB.Query(`SELECT id, type, song_id, song_name, group_name, data, request_id, created_at
+		FROM events
+		WHERE id > $1 AND ($2 = '' OR group_name = $2)
+			AND (jsonb_array_length($3::jsonb) = 0
+				OR type IN (SELECT jsonb_array_elements_text($3::jsonb)))
+		ORDER BY id
+		LIMIT $4`,
+		after, filter.GroupName, string(types), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	var events []*model.Event
+	for rows.Next() {
+		event := &model.Event{Song: &model.Song{}}
+		var songId sql.NullInt64
+		var data []byte
+		err := rows.Scan(&event.ID, &event.Type, &songId, &event.Song.SongName, &event.Song.GroupName,
+			&data, &event.RequestID, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		event.Song.ID = songId.Int64
+		event.Data = data
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return events, nil
+}
+
+// LastEventID returns the id of the last event of the log, 0 when it's empty.
+func (r *Database) LastEventID() (int64, error) {
+	const op = "internal.storage.postgresql.LastEventID()"
+
+	var id int64
+	if err := r.DB.QueryRow("SELECT COALESCE(MAX(id), 0) FROM events").Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+	return id, nil
+}
+
+// ListenEvents calls notify whenever an event is added to the log by any
+// instance, until ctx is done or the connection fails. It holds a connection
+// of its own, since a pooled one may be taken away between notifications;
+// notify is called once as soon as it listens, for the events added before.
+func (r *Database) ListenEvents(ctx context.Context, notify func()) error {
+	const op = "internal.storage.postgresql.ListenEvents()"
+
+	conn, err := pgx.Connect(ctx, r.dataSourceName)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventsChannel); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	notify()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%s:%w", op, err)
+		}
+		notify()
+	}
+}
@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// exportFetchSize is the number of rows fetched from the export cursor at once.
+const exportFetchSize = 500
+
+// ExportSongs passes the songs of the library matching the filter, with
+// their details, to write one by one. The rows are read through a server-side
+// cursor, so only one batch is held in memory.
+func (r *Database) ExportSongs(ctx context.Context, filter *model.LibraryFilter, write func(song *model.ExportedSong) error) error {
+	const op = "internal.storage.postgresql.ExportSongs()"
+
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	conditions, score, args := libraryConditions(filter, nil)
+	_, err = tx.ExecContext(ctx, "DECLARE library_export NO SCROLL CURSOR FOR SELECT songs.id, songs.song_name, groups.name, "+
+		releaseDateISO("songs_detail")+", COALESCE(songs_detail.release_date_precision, ''), COALESCE(songs_detail.link, ''), COALESCE(songs_detail.text, '')"+
+		libraryFrom+" WHERE songs.deleted_at IS NULL"+conditions+
+		" ORDER BY "+keysetOrder(filter.Sort, librarySortExpressions(score), false), args...)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	for {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH FORWARD %d FROM library_export", exportFetchSize))
+		if err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+
+		var fetched int
+		for rows.Next() {
+			fetched++
+
+			var song model.ExportedSong
+			var releaseDate sql.NullString
+			err = rows.Scan(&song.ID, &song.SongName, &song.GroupName, &releaseDate,
+				&song.ReleaseDatePrecision, &song.Link, &song.Text)
+			if err == nil {
+				song.ReleaseDate = releaseDate.String
+				err = write(&song)
+			}
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("%s:%w", op, err)
+			}
+		}
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+		rows.Close()
+
+		if fetched < exportFetchSize {
+			break
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (r *Database) AddGroup(group *model.Group) error {
+	const op = "internal.storage.postgresql.AddGroup()"
+
+	if _, err := r.foundGroupId(group.Name); err == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupAlreadyExists)
+	}
+
+	err := r.DB.QueryRow(`INSERT INTO groups (name, country, formed_year, description)
+		VALUES ($1, $2, $3, $4) RETURNING id`,
+		group.Name, group.Country, group.FormedYear, group.Description).Scan(&group.ID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) GetGroup(id int64) (*model.Group, error) {
+	const op = "internal.storage.postgresql.GetGroup()"
+
+	var group model.Group
+	err := r.DB.Get(&group, "SELECT id, name, country, formed_year, description FROM groups WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &group, nil
+}
+
+func (r *Database) GetGroups(limit int64, offset int64) ([]*model.Group, error) {
+	const op = "internal.storage.postgresql.GetGroups()"
+
+	var groups []*model.Group
+	err := r.DB.Select(&groups, `SELECT id, name, country, formed_year, description FROM groups
+		ORDER BY id LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (r *Database) CountGroups() (int64, error) {
+	const op = "internal.storage.postgresql.CountGroups()"
+
+	var count int64
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM groups").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Database) PutGroup(group *model.Group) error {
+	const op = "internal.storage.postgresql.PutGroup()"
+
+	if id, err := r.foundGroupId(group.Name); err == nil && id != group.ID {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupAlreadyExists)
+	}
+
+	res, err := r.DB.Exec(`UPDATE groups SET name = $1, country = $2, formed_year = $3, description = $4
+		WHERE id = $5`,
+		group.Name, group.Country, group.FormedYear, group.Description, group.ID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+func (r *Database) DeleteGroup(id int64) error {
+	const op = "internal.storage.postgresql.DeleteGroup()"
+
+	// The songs in the trash keep their group until they are purged.
+	var songsNumber, trashedNumber int64
+	err := r.DB.QueryRow(`SELECT COUNT(*) FILTER (WHERE deleted_at IS NULL),
+		COUNT(*) FILTER (WHERE deleted_at IS NOT NULL)
+		FROM songs WHERE group_id = $1`, id).Scan(&songsNumber, &trashedNumber)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if songsNumber > 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupHasSongs)
+	}
+	if trashedNumber > 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupHasTrashedSongs)
+	}
+
+	res, err := r.DB.Exec("DELETE FROM groups WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+func (r *Database) foundGroupId(name string) (int64, error) {
+	op := "internal.storage.postgresql.foundGroupId()"
+	var groupId int64
+
+	err := r.DB.QueryRow("SELECT id FROM groups WHERE name = $1", name).Scan(&groupId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s:%w", op, storage.ErrGroupNotFound)
+		}
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return groupId, nil
+}
+
+func (r *Database) foundOrCreateGroupId(name string) (int64, error) {
+	op := "internal.storage.postgresql.foundOrCreateGroupId()"
+	var groupId int64
+
+	err := r.DB.QueryRow(`INSERT INTO groups (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id`, name).Scan(&groupId)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return groupId, nil
+}
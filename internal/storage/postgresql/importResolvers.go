@@ -0,0 +1,218 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+)
+
+// importBatchSize bounds the rows of one multi-row statement of the import.
+const importBatchSize = 500
+
+// ImportSongs adds the songs with their details in one transaction and tells
+// for every row whether it was created or is a duplicate of an existing song
+// or of an earlier row. A dry run reports the same and rolls back.
+func (r *Database) ImportSongs(rows []*model.ImportRow, dryRun bool, change *model.DetailChange) ([]string, error) {
+	const op = "internal.storage.postgresql.ImportSongs()"
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	statuses := make([]string, len(rows))
+	seen := make(map[[2]string]bool, len(rows))
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+		if err := importBatch(tx, rows[start:end], statuses[start:end], seen, change); err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	if dryRun {
+		return statuses, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return statuses, nil
+}
+
+func importBatch(tx *sqlx.Tx, rows []*model.ImportRow, statuses []string, seen map[[2]string]bool, change *model.DetailChange) error {
+	groupIds, err := importGroups(tx, rows)
+	if err != nil {
+		return err
+	}
+
+	existing, err := existingSongs(tx, rows, groupIds)
+	if err != nil {
+		return err
+	}
+
+	var created []*model.ImportRow
+	for i, row := range rows {
+		key := [2]string{row.Song.SongName, row.Song.GroupName}
+		if seen[key] || existing[key] {
+			statuses[i] = model.ImportDuplicate
+			continue
+		}
+		seen[key] = true
+		statuses[i] = model.ImportCreated
+		created = append(created, row)
+	}
+	if len(created) == 0 {
+		return nil
+	}
+
+	songIds, err := insertSongs(tx, created, groupIds)
+	if err != nil {
+		return err
+	}
+
+	return insertDetails(tx, created, songIds, change)
+}
+
+// importGroups finds or creates the groups of the rows and maps their names to ids.
+func importGroups(tx *sqlx.Tx, rows []*model.ImportRow) (map[string]int64, error) {
+	var placeholders []string
+	var args []interface{}
+	added := make(map[string]bool)
+	for _, row := range rows {
+		if added[row.Song.GroupName] {
+			continue
+		}
+		added[row.Song.GroupName] = true
+		args = append(args, row.Song.GroupName)
+		placeholders = append(placeholders, "($"+strconv.Itoa(len(args))+")")
+	}
+
+	result, err := tx.Query(`INSERT INTO groups (name) VALUES `+strings.Join(placeholders, ", ")+`
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id, name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	groupIds := make(map[string]int64, len(args))
+	for result.Next() {
+		var id int64
+		var name string
+		if err := result.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		groupIds[name] = id
+	}
+	return groupIds, result.Err()
+}
+
+// existingSongs returns which of the rows are already in the library.
+func existingSongs(tx *sqlx.Tx, rows []*model.ImportRow, groupIds map[string]int64) (map[[2]string]bool, error) {
+	var placeholders []string
+	var args []interface{}
+	for _, row := range rows {
+		args = append(args, row.Song.SongName, groupIds[row.Song.GroupName])
+		placeholders = append(placeholders, "($"+strconv.Itoa(len(args)-1)+", $"+strconv.Itoa(len(args))+"::int)")
+	}
+
+	result, err := tx.Query(`SELECT songs.song_name, groups.name FROM songs
+		JOIN groups ON groups.id = songs.group_id
+		WHERE songs.deleted_at IS NULL AND (songs.song_name, songs.group_id) IN (VALUES `+
+		strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	existing := make(map[[2]string]bool)
+	for result.Next() {
+		var key [2]string
+		if err := result.Scan(&key[0], &key[1]); err != nil {
+			return nil, err
+		}
+		existing[key] = true
+	}
+	return existing, result.Err()
+}
+
+// insertSongs adds the songs and returns their ids in the order of the rows.
+func insertSongs(tx *sqlx.Tx, rows []*model.ImportRow, groupIds map[string]int64) ([]int64, error) {
+	var placeholders []string
+	var args []interface{}
+	for _, row := range rows {
+		args = append(args, row.Song.SongName, groupIds[row.Song.GroupName])
+		placeholders = append(placeholders, "($"+strconv.Itoa(len(args)-1)+", $"+strconv.Itoa(len(args))+")")
+	}
+
+	result, err := tx.Query("INSERT INTO songs (song_name, group_id) VALUES "+
+		strings.Join(placeholders, ", ")+" RETURNING id, song_name, group_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	// The rows are unique by song and group, so the returned ids are matched by them.
+	ids := make(map[string]int64, len(rows))
+	for result.Next() {
+		var id, groupId int64
+		var songName string
+		if err := result.Scan(&id, &songName, &groupId); err != nil {
+			return nil, err
+		}
+		ids[strconv.FormatInt(groupId, 10)+":"+songName] = id
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	songIds := make([]int64, len(rows))
+	for i, row := range rows {
+		songIds[i] = ids[strconv.FormatInt(groupIds[row.Song.GroupName], 10)+":"+row.Song.SongName]
+	}
+	return songIds, nil
+}
+
+// insertDetails adds the details of the rows that have them as their first revision.
+func insertDetails(tx *sqlx.Tx, rows []*model.ImportRow, songIds []int64, change *model.DetailChange) error {
+	var placeholders []string
+	var args []interface{}
+	for i, row := range rows {
+		if row.Detail == nil {
+			continue
+		}
+		releaseDate, precision, err := parseReleaseDate(row.Detail)
+		if err != nil {
+			return err
+		}
+
+		sectionsData, err := json.Marshal(sections.FromDetail(row.Detail))
+		if err != nil {
+			return err
+		}
+
+		args = append(args, songIds[i], releaseDate, precision, row.Detail.Link, row.Detail.Text, string(sectionsData))
+		n := len(args)
+		placeholders = append(placeholders, "($"+strconv.Itoa(n-5)+"::int, $"+strconv.Itoa(n-4)+"::date, $"+
+			strconv.Itoa(n-3)+", $"+strconv.Itoa(n-2)+", $"+strconv.Itoa(n-1)+", $"+strconv.Itoa(n)+"::jsonb)")
+	}
+	if len(placeholders) == 0 {
+		return nil
+	}
+
+	args = append(args, change.Author, change.Message)
+	_, err := tx.Exec(`WITH details AS (
+			INSERT INTO songs_detail (song_id, release_date, release_date_precision, link, text, sections)
+			VALUES `+strings.Join(placeholders, ", ")+`
+			RETURNING song_id, release_date, release_date_precision, link, text
+		)
+		INSERT INTO songs_detail_revisions
+			(song_id, revision, release_date, release_date_precision, link, text, author, message)
+		SELECT song_id, 1, release_date, release_date_precision, link, text, $`+
+		strconv.Itoa(len(args)-1)+`, $`+strconv.Itoa(len(args))+` FROM details`, args...)
+	return err
+}
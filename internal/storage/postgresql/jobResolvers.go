@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+const jobColumns = `jobs.id, jobs.kind, jobs.song_id, jobs.state, jobs.attempts, jobs.max_attempts,
+	jobs.last_error, jobs.author, jobs.request_id, jobs.run_at, jobs.created_at, jobs.updated_at,
+	jobs.finished_at, songs.song_name, groups.name`
+
+// AddSongAndEnqueue adds the song and queues the job on it in one
+// transaction, so that no song is left without its job.
+func (r *Database) AddSongAndEnqueue(song *model.Song, job *model.Job) error {
+	const op = "internal.storage.postgresql.AddSongAndEnqueue()"
+
+	if _, err := r.foundSongId(song); err == nil {
+		return fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
+	}
+
+	groupId, err := r.foundOrCreateGroupId(song.GroupName)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("INSERT INTO songs (song_name, group_id) VALUES ($1, $2) RETURNING id",
+		song.SongName, groupId).Scan(&song.ID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	job.SongID = song.ID
+	job.State = model.JobQueued
+	err = tx.QueryRow(`INSERT INTO jobs (kind, song_id, max_attempts, author, request_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, run_at, created_at, updated_at`,
+		job.Kind, job.SongID, job.MaxAttempts, job.Author, job.RequestID).
+		Scan(&job.ID, &job.RunAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// ClaimJob takes the next due job of the kind, or a running one whose lease
+// has passed, and holds it for lease. Concurrent workers skip the rows locked
+// by each other, so every job goes to a single worker.
+func (r *Database) ClaimJob(kind string, lease time.Duration) (*model.Job, error) {
+	const op = "internal.storage.postgresql.ClaimJob()"
+
+	row := r.DB.QueryRow(`WITH claimed AS (
+			UPDATE jobs SET state = 'running', attempts = attempts + 1,
+				locked_until = now() + make_interval(secs => $2), updated_at = now()
+			WHERE id = (
+				SELECT id FROM jobs
+				WHERE kind = $1 AND (state = 'queued' AND run_at <= now()
+					OR state = 'running' AND locked_until < now())
+				ORDER BY run_at, id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT `+jobColumns+` FROM claimed AS jobs
+		JOIN songs ON songs.id = jobs.song_id
+		JOIN groups ON groups.id = songs.group_id`,
+		kind, lease.Seconds())
+
+	job, err := scanJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrJobNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return job, nil
+}
+
+// CompleteJob marks the claimed job succeeded.
+func (r *Database) CompleteJob(job *model.Job) error {
+	const op = "internal.storage.postgresql.CompleteJob()"
+
+	err := r.finishJob(job, `state = 'succeeded', last_error = '', finished_at = now()`)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// RetryJob queues the claimed job again to run at runAt.
+func (r *Database) RetryJob(job *model.Job, lastError string, runAt time.Time) error {
+	const op = "internal.storage.postgresql.RetryJob()"
+
+	err := r.finishJob(job, `state = 'queued', last_error = $3, run_at = $4`, lastError, runAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// FailJob marks the claimed job failed for good.
+func (r *Database) FailJob(job *model.Job, lastError string) error {
+	const op = "internal.storage.postgresql.FailJob()"
+
+	err := r.finishJob(job, `state = 'failed', last_error = $3, finished_at = now()`, lastError)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// finishJob ends the attempt of the job. A job claimed again after its lease
+// has passed belongs to the new attempt, and the old one changes nothing.
+func (r *Database) finishJob(job *model.Job, set string, args ...interface{}) error {
+	res, err := r.DB.Exec(`UPDATE jobs SET `+set+`, locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`,
+		append([]interface{}{job.ID, job.Attempts}, args...)...)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return storage.ErrJobNotFound
+	}
+	return nil
+}
+
+func (r *Database) GetJob(id int64) (*model.Job, error) {
+	const op = "internal.storage.postgresql.GetJob()"
+
+	job, err := scanJob(r.DB.QueryRow(`SELECT `+jobColumns+` FROM jobs
+		JOIN songs ON songs.id = jobs.song_id
+		JOIN groups ON groups.id = songs.group_id
+		WHERE jobs.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrJobNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return job, nil
+}
+
+func scanJob(row rowScanner) (*model.Job, error) {
+	job := &model.Job{Song: &model.Song{}}
+	var finishedAt sql.NullTime
+
+	err := row.Scan(&job.ID, &job.Kind, &job.SongID, &job.State, &job.Attempts, &job.MaxAttempts,
+		&job.LastError, &job.Author, &job.RequestID, &job.RunAt, &job.CreatedAt, &job.UpdatedAt,
+		&finishedAt, &job.Song.SongName, &job.Song.GroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Song.ID = job.SongID
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
@@ -0,0 +1,197 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// lyricsBatchSize bounds the lines of one multi-row insert.
+const lyricsBatchSize = 500
+
+// PutLyrics replaces the time-synced lyrics of the song.
+func (r *Database) PutLyrics(songId int64, lyrics *model.Lyrics) error {
+	const op = "internal.storage.postgresql.PutLyrics()"
+
+	tags, err := json.Marshal(lyrics.Tags)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow("SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", songId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+		}
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO songs_lyrics (song_id, tags) VALUES ($1, $2::jsonb)
+		ON CONFLICT (song_id) DO UPDATE SET tags = EXCLUDED.tags, updated_at = now()`,
+		songId, string(tags))
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	_, err = tx.Exec("DELETE FROM songs_lyrics_lines WHERE song_id = $1", songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	for start := 0; start < len(lyrics.Lines); start += lyricsBatchSize {
+		end := min(start+lyricsBatchSize, len(lyrics.Lines))
+		if err := insertLyricLines(tx, songId, lyrics.Lines[start:end]); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+func insertLyricLines(tx *sqlx.Tx, songId int64, lines []*model.LyricLine) error {
+	placeholders := make([]string, 0, len(lines))
+	args := []interface{}{songId}
+	for _, line := range lines {
+		var words interface{}
+		if len(line.Words) > 0 {
+			data, err := json.Marshal(line.Words)
+			if err != nil {
+				return err
+			}
+			words = string(data)
+		}
+
+		args = append(args, line.Position, line.TimeMs, line.Text, words)
+		n := len(args)
+		placeholders = append(placeholders, "($1, $"+strconv.Itoa(n-3)+"::int, $"+strconv.Itoa(n-2)+
+			"::bigint, $"+strconv.Itoa(n-1)+", $"+strconv.Itoa(n)+"::jsonb)")
+	}
+
+	_, err := tx.Exec("INSERT INTO songs_lyrics_lines (song_id, position, time_ms, text, words) VALUES "+
+		strings.Join(placeholders, ", "), args...)
+	return err
+}
+
+func (r *Database) GetLyrics(songId int64) (*model.Lyrics, error) {
+	const op = "internal.storage.postgresql.GetLyrics()"
+
+	lyrics, err := r.lyricsTags(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	rows, err := r.DB.Query(`SELECT position, time_ms, text, words FROM songs_lyrics_lines
+		WHERE song_id = $1 ORDER BY position`, songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := scanLyricLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		lyrics.Lines = append(lyrics.Lines, line)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	lrc.Index(lyrics)
+	return lyrics, nil
+}
+
+// GetLyricLineAt returns the line being sung at the playback offset in milliseconds.
+func (r *Database) GetLyricLineAt(songId int64, at int64) (*model.LyricLine, error) {
+	const op = "internal.storage.postgresql.GetLyricLineAt()"
+
+	if _, err := r.lyricsTags(songId); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	line, err := scanLyricLine(r.DB.QueryRow(`SELECT position, time_ms, text, words FROM songs_lyrics_lines
+		WHERE song_id = $1 AND time_ms <= $2 ORDER BY time_ms DESC, position DESC LIMIT 1`, songId, at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrLyricLineNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	line.Time = lrc.FormatTime(line.TimeMs)
+	for _, word := range line.Words {
+		word.Time = lrc.FormatTime(word.TimeMs)
+	}
+	return line, nil
+}
+
+func (r *Database) DeleteLyrics(songId int64) error {
+	const op = "internal.storage.postgresql.DeleteLyrics()"
+
+	if _, err := r.lyricsTags(songId); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	_, err := r.DB.Exec("DELETE FROM songs_lyrics WHERE song_id = $1", songId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// lyricsTags reads the lyrics of an active song without their lines.
+func (r *Database) lyricsTags(songId int64) (*model.Lyrics, error) {
+	var tags []byte
+	err := r.DB.QueryRow(`SELECT songs_lyrics.tags FROM songs
+		LEFT JOIN songs_lyrics ON songs_lyrics.song_id = songs.id
+		WHERE songs.id = $1 AND songs.deleted_at IS NULL`, songId).Scan(&tags)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrSongNotFound
+		}
+		return nil, err
+	}
+	if tags == nil {
+		return nil, storage.ErrLyricsNotFound
+	}
+
+	lyrics := &model.Lyrics{SongID: songId}
+	if err := json.Unmarshal(tags, &lyrics.Tags); err != nil {
+		return nil, err
+	}
+	return lyrics, nil
+}
+
+func scanLyricLine(row rowScanner) (*model.LyricLine, error) {
+	line := &model.LyricLine{}
+	var words []byte
+
+	if err := row.Scan(&line.Position, &line.TimeMs, &line.Text, &words); err != nil {
+		return nil, err
+	}
+	if words != nil {
+		if err := json.Unmarshal(words, &line.Words); err != nil {
+			return nil, err
+		}
+	}
+	return line, nil
+}
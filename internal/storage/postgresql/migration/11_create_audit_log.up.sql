@@ -0,0 +1,15 @@
+CREATE TABLE audit_log (
+    id  BIGSERIAL PRIMARY KEY,
+    operation TEXT NOT NULL,
+    song_name TEXT NOT NULL,
+    group_name TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    remote_addr TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_song_idx ON audit_log (song_name, group_name);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
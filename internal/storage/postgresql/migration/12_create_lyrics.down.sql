@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS songs_lyrics_lines;
+DROP TABLE IF EXISTS songs_lyrics;
@@ -0,0 +1,16 @@
+CREATE TABLE songs_lyrics (
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    tags JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE songs_lyrics_lines (
+    song_id INT NOT NULL REFERENCES songs_lyrics(song_id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    time_ms BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    words JSONB,
+    PRIMARY KEY (song_id, position)
+);
+
+CREATE INDEX songs_lyrics_lines_time_idx ON songs_lyrics_lines (song_id, time_ms);
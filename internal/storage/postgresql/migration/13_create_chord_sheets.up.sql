@@ -0,0 +1,5 @@
+CREATE TABLE songs_chord_sheets (
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    sheet JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+-- Details saved before sections existed keep NULL and are parsed when read.
+ALTER TABLE songs_detail ADD COLUMN sections JSONB;
@@ -0,0 +1,7 @@
+CREATE TABLE song_translations (
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    text TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (song_id, language)
+);
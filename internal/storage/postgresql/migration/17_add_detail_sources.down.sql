@@ -0,0 +1 @@
+ALTER TABLE songs_detail DROP COLUMN IF EXISTS sources;
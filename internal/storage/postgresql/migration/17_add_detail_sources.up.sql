@@ -0,0 +1,2 @@
+-- The provider each field of the detail came from; NULL when entered by hand.
+ALTER TABLE songs_detail ADD COLUMN sources JSONB;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS events_notify ON events;
+DROP FUNCTION IF EXISTS notify_library_event();
+DROP INDEX IF EXISTS events_group_idx;
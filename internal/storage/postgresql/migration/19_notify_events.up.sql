@@ -0,0 +1,14 @@
+CREATE INDEX events_group_idx ON events (group_name, id);
+
+-- Every instance listens on library_events, so an event added by any of them
+-- wakes the subscribers of all. The payload is the id; the event itself is
+-- read from the log.
+CREATE FUNCTION notify_library_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('library_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER events_notify AFTER INSERT ON events
+    FOR EACH ROW EXECUTE FUNCTION notify_library_event();
@@ -0,0 +1,9 @@
+ALTER TABLE songs ADD COLUMN group_name TEXT;
+
+UPDATE songs SET group_name = groups.name
+FROM groups
+WHERE groups.id = songs.group_id;
+
+ALTER TABLE songs ALTER COLUMN group_name SET NOT NULL;
+ALTER TABLE songs DROP COLUMN group_id;
+DROP TABLE IF EXISTS groups;
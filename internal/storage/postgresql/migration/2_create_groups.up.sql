@@ -0,0 +1,19 @@
+CREATE TABLE groups (
+    id  SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    country TEXT NOT NULL DEFAULT '',
+    formed_year INT,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO groups (name)
+SELECT DISTINCT group_name FROM songs;
+
+ALTER TABLE songs ADD COLUMN group_id INT REFERENCES groups(id);
+
+UPDATE songs SET group_id = groups.id
+FROM groups
+WHERE groups.name = songs.group_name;
+
+ALTER TABLE songs ALTER COLUMN group_id SET NOT NULL;
+ALTER TABLE songs DROP COLUMN group_name;
@@ -0,0 +1,15 @@
+CREATE TABLE albums (
+    id  SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    group_id INT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    release_date DATE NOT NULL,
+    UNIQUE (group_id, title)
+);
+
+CREATE TABLE album_tracks (
+    album_id INT NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (album_id, song_id),
+    UNIQUE (album_id, position) DEFERRABLE INITIALLY DEFERRED
+);
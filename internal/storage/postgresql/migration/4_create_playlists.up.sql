@@ -0,0 +1,11 @@
+CREATE TABLE playlists (
+    id  SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE playlist_entries (
+    playlist_id INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (playlist_id, position)
+);
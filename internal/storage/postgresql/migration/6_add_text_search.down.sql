@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS songs_detail_text_search_idx;
+ALTER TABLE songs_detail DROP COLUMN IF EXISTS text_search;
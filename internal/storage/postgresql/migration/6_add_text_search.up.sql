@@ -0,0 +1,4 @@
+ALTER TABLE songs_detail ADD COLUMN text_search tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', text) || to_tsvector('russian', text)) STORED;
+
+CREATE INDEX songs_detail_text_search_idx ON songs_detail USING GIN (text_search);
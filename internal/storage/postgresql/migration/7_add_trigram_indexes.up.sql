@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX songs_song_name_trgm_idx ON songs USING GIN (song_name gin_trgm_ops);
+CREATE INDEX groups_name_trgm_idx ON groups USING GIN (name gin_trgm_ops);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS songs_detail_release_date_idx;
+
+ALTER TABLE songs_detail ALTER COLUMN release_date TYPE TEXT USING COALESCE(release_date_raw, CASE release_date_precision
+    WHEN 'year' THEN to_char(release_date, 'YYYY')
+    WHEN 'month' THEN to_char(release_date, 'YYYY-MM')
+    ELSE to_char(release_date, 'YYYY-MM-DD')
+END, '');
+ALTER TABLE songs_detail ALTER COLUMN release_date SET NOT NULL;
+
+ALTER TABLE songs_detail DROP COLUMN IF EXISTS release_date_raw;
+ALTER TABLE songs_detail DROP COLUMN IF EXISTS release_date_precision;
@@ -0,0 +1,86 @@
+ALTER TABLE songs_detail ADD COLUMN release_date_precision TEXT NOT NULL DEFAULT 'day'
+    CHECK (release_date_precision IN ('year', 'month', 'day'));
+
+-- Dates the migration can't read are kept here as they were written, the
+-- release_date of such rows is NULL until the detail is changed.
+ALTER TABLE songs_detail ADD COLUMN release_date_raw TEXT;
+
+-- release_month is the number of the month written in full or as its first
+-- three letters, NULL for anything else.
+CREATE FUNCTION pg_temp.release_month(month_name TEXT) RETURNS INT AS $$
+    SELECT number::int FROM unnest(ARRAY['january', 'february', 'march', 'april', 'may', 'june',
+        'july', 'august', 'september', 'october', 'november', 'december']) WITH ORDINALITY AS months (full_name, number)
+    WHERE lower(month_name) IN (full_name, left(full_name, 3))
+$$ LANGUAGE sql;
+
+-- parse_release_date reads a date in the formats of releasedate.Parse. It
+-- returns NULLs instead of failing for anything else, an impossible day such
+-- as 31.02.2006 included.
+CREATE FUNCTION pg_temp.parse_release_date(raw TEXT, OUT parsed_date DATE, OUT parsed_precision TEXT) AS $$
+DECLARE
+    s TEXT := trim(raw);
+    m TEXT[];
+BEGIN
+    IF s ~ '^\d{4}-\d{2}-\d{2}$' THEN
+        m := regexp_match(s, '^(\d{4})-(\d{2})-(\d{2})$');
+        parsed_date := make_date(m[1]::int, m[2]::int, m[3]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$' THEN
+        -- The day is the one written, whatever the offset, as in Go.
+        PERFORM s::timestamptz;
+        m := regexp_match(s, '^(\d{4})-(\d{2})-(\d{2})');
+        parsed_date := make_date(m[1]::int, m[2]::int, m[3]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN
+        m := regexp_match(s, '^(\d{1,2})\.(\d{1,2})\.(\d{4})$');
+        parsed_date := make_date(m[3]::int, m[2]::int, m[1]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{2}/\d{2}/\d{4}$' THEN
+        m := regexp_match(s, '^(\d{2})/(\d{2})/(\d{4})$');
+        parsed_date := make_date(m[3]::int, m[2]::int, m[1]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{4}/\d{2}/\d{2}$' THEN
+        m := regexp_match(s, '^(\d{4})/(\d{2})/(\d{2})$');
+        parsed_date := make_date(m[1]::int, m[2]::int, m[3]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{1,2} [A-Za-z]+ \d{4}$' THEN
+        m := regexp_match(s, '^(\d{1,2}) ([A-Za-z]+) (\d{4})$');
+        parsed_date := make_date(m[3]::int, pg_temp.release_month(m[2]), m[1]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^[A-Za-z]+ \d{1,2}, \d{4}$' THEN
+        m := regexp_match(s, '^([A-Za-z]+) (\d{1,2}), (\d{4})$');
+        parsed_date := make_date(m[3]::int, pg_temp.release_month(m[1]), m[2]::int);
+        parsed_precision := 'day';
+    ELSIF s ~ '^\d{4}-\d{2}$' THEN
+        m := regexp_match(s, '^(\d{4})-(\d{2})$');
+        parsed_date := make_date(m[1]::int, m[2]::int, 1);
+        parsed_precision := 'month';
+    ELSIF s ~ '^\d{2}[./]\d{4}$' THEN
+        m := regexp_match(s, '^(\d{2})[./](\d{4})$');
+        parsed_date := make_date(m[2]::int, m[1]::int, 1);
+        parsed_precision := 'month';
+    ELSIF s ~ '^[A-Za-z]+ \d{4}$' THEN
+        m := regexp_match(s, '^([A-Za-z]+) (\d{4})$');
+        parsed_date := make_date(m[2]::int, pg_temp.release_month(m[1]), 1);
+        parsed_precision := 'month';
+    ELSIF s ~ '^\d{4}$' THEN
+        parsed_date := make_date(s::int, 1, 1);
+        parsed_precision := 'year';
+    END IF;
+EXCEPTION WHEN others THEN
+    parsed_date := NULL;
+    parsed_precision := NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE songs_detail SET
+    release_date_precision = COALESCE((pg_temp.parse_release_date(release_date)).parsed_precision, 'day'),
+    release_date_raw = CASE
+        WHEN (pg_temp.parse_release_date(release_date)).parsed_date IS NULL AND trim(release_date) <> '' THEN release_date
+    END;
+
+ALTER TABLE songs_detail ALTER COLUMN release_date DROP NOT NULL;
+ALTER TABLE songs_detail ALTER COLUMN release_date TYPE DATE
+    USING (pg_temp.parse_release_date(release_date)).parsed_date;
+
+CREATE INDEX songs_detail_release_date_idx ON songs_detail (release_date);
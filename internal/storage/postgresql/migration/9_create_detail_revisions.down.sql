@@ -0,0 +1 @@
+DROP TABLE IF EXISTS songs_detail_revisions;
@@ -0,0 +1,17 @@
+CREATE TABLE songs_detail_revisions (
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    release_date DATE,
+    release_date_precision TEXT NOT NULL DEFAULT 'day',
+    link TEXT NOT NULL,
+    text TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (song_id, revision)
+);
+
+INSERT INTO songs_detail_revisions (song_id, revision, release_date, release_date_precision, link, text, message)
+SELECT song_id, 1, release_date, release_date_precision, link, text, 'initial revision'
+FROM songs_detail
+WHERE song_id IS NOT NULL;
@@ -0,0 +1,210 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func (r *Database) AddPlaylist(playlist *model.Playlist) error {
+	const op = "internal.storage.postgresql.AddPlaylist()"
+
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM playlists WHERE name = $1)", playlist.Name).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if exists {
+		return fmt.Errorf("%s:%w", op, storage.ErrPlaylistAlreadyExists)
+	}
+
+	err = r.DB.QueryRow("INSERT INTO playlists (name) VALUES ($1) RETURNING id", playlist.Name).Scan(&playlist.ID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	playlist.Entries = []*model.PlaylistEntry{}
+
+	return nil
+}
+
+func (r *Database) GetPlaylist(id int64) (*model.Playlist, error) {
+	const op = "internal.storage.postgresql.GetPlaylist()"
+
+	var playlist model.Playlist
+	err := r.DB.Get(&playlist, "SELECT id, name FROM playlists WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrPlaylistNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	rows, err := r.DB.Query(`SELECT playlist_entries.position, songs.id, songs.song_name, groups.name,
+			COALESCE(songs_detail.link, '')
+		FROM playlist_entries
+		JOIN songs ON songs.id = playlist_entries.song_id
+		JOIN groups ON groups.id = songs.group_id
+		LEFT JOIN songs_detail ON songs_detail.song_id = songs.id
+		WHERE playlist_entries.playlist_id = $1 AND songs.deleted_at IS NULL
+		ORDER BY playlist_entries.position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	playlist.Entries = []*model.PlaylistEntry{}
+	for rows.Next() {
+		entry := &model.PlaylistEntry{Song: &model.Song{}}
+		err = rows.Scan(&entry.Position, &entry.Song.ID, &entry.Song.SongName, &entry.Song.GroupName, &entry.Link)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &playlist, nil
+}
+
+func (r *Database) DeletePlaylist(id int64) error {
+	const op = "internal.storage.postgresql.DeletePlaylist()"
+
+	res, err := r.DB.Exec("DELETE FROM playlists WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrPlaylistNotFound)
+	}
+
+	return nil
+}
+
+func (r *Database) AddPlaylistEntry(playlistId int64, song *model.Song, position int) error {
+	const op = "internal.storage.postgresql.AddPlaylistEntry()"
+
+	songId, err := r.foundSongRef(song)
+	if err != nil {
+		return err
+	}
+
+	err = r.changePlaylistEntries(playlistId, func(songIds []int64) ([]int64, error) {
+		return storage.InsertAt(songIds, songId, position), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) MovePlaylistEntry(playlistId int64, from int, to int) error {
+	const op = "internal.storage.postgresql.MovePlaylistEntry()"
+
+	err := r.changePlaylistEntries(playlistId, func(songIds []int64) ([]int64, error) {
+		return storage.Move(songIds, from, to)
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Database) DeletePlaylistEntry(playlistId int64, position int) error {
+	const op = "internal.storage.postgresql.DeletePlaylistEntry()"
+
+	err := r.changePlaylistEntries(playlistId, func(songIds []int64) ([]int64, error) {
+		return storage.RemoveAt(songIds, position)
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	return nil
+}
+
+// changePlaylistEntries loads the ordered song ids of the playlist, applies change
+// and writes the result back with positions renumbered from 1.
+func (r *Database) changePlaylistEntries(playlistId int64, change func([]int64) ([]int64, error)) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow("SELECT id FROM playlists WHERE id = $1 FOR UPDATE", playlistId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrPlaylistNotFound
+		}
+		return err
+	}
+
+	var songIds []int64
+	err = tx.Select(&songIds, "SELECT song_id FROM playlist_entries WHERE playlist_id = $1 ORDER BY position",
+		playlistId)
+	if err != nil {
+		return err
+	}
+
+	songIds, err = change(songIds)
+	if err != nil {
+		return err
+	}
+
+	if err = insertPlaylistEntries(tx, playlistId, songIds); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertPlaylistEntries(tx *sqlx.Tx, playlistId int64, songIds []int64) error {
+	_, err := tx.Exec("DELETE FROM playlist_entries WHERE playlist_id = $1", playlistId)
+	if err != nil {
+		return err
+	}
+
+	for i, songId := range songIds {
+		_, err = tx.Exec("INSERT INTO playlist_entries (playlist_id, song_id, position) VALUES ($1, $2, $3)",
+			playlistId, songId, i+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// foundSongRef resolves a song referenced either by its ID or by its (song, group) identity.
+func (r *Database) foundSongRef(song *model.Song) (int64, error) {
+	op := "internal.storage.postgresql.foundSongRef()"
+
+	if song.ID == 0 {
+		return r.foundSongId(song)
+	}
+
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)", song.ID).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+	if !exists {
+		return 0, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+	}
+
+	return song.ID, nil
+}
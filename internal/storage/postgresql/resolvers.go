@@ -1,12 +1,20 @@
 package postgresql
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
 
-	"github.com/jackc/pgx/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/nabishec/restapi/internal/lib/cursor"
 	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/lib/releasedate"
+	"github.com/nabishec/restapi/internal/lib/sections"
 	"github.com/nabishec/restapi/internal/model"
 	"github.com/nabishec/restapi/internal/storage"
 )
@@ -18,8 +26,13 @@ func (r *Database) AddSong(song *model.Song) error {
 		return fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
 	}
 
-	_, err := r.DB.Exec("INSERT INTO songs (song_name, group_name) VALUES ($1, $2)",
-		song.SongName, song.GroupName)
+	groupId, err := r.foundOrCreateGroupId(song.GroupName)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.DB.Exec("INSERT INTO songs (song_name, group_id) VALUES ($1, $2)",
+		song.SongName, groupId)
 
 	if err != nil {
 		return fmt.Errorf("%s:%w", op, err)
@@ -31,7 +44,9 @@ func (r *Database) AddSong(song *model.Song) error {
 func (r *Database) DeleteSong(song *model.Song, log *slog.Logger) error {
 	const op = "internal.storage.postgresql.DeleteSong()"
 
-	res, err := r.DB.Exec("DELETE FROM songs WHERE song_name = $1 AND group_name = $2",
+	res, err := r.DB.Exec(`UPDATE songs SET deleted_at = now() FROM groups
+		WHERE songs.group_id = groups.id AND songs.song_name = $1 AND groups.name = $2
+			AND songs.deleted_at IS NULL`,
 		song.SongName, song.GroupName)
 
 	if err != nil {
@@ -50,77 +65,198 @@ func (r *Database) DeleteSong(song *model.Song, log *slog.Logger) error {
 	return nil
 }
 
-func (r *Database) PutSongDetail(song *model.Song, songDetail *model.SongDetail) error {
+func (r *Database) PutSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
 	const op = "internal.storage.postgresql.PutSongDetail()"
 
 	songId, err := r.foundSongId(song)
 	if err != nil {
 		return err
 	}
-	id, err := r.foundSongDetailId(songId)
-	if err != nil {
-		return fmt.Errorf("%s:%w", op, storage.ErrSongDetailNotFound)
-	}
-	_, err = r.DB.Exec("UPDATE songs_detail SET release_date = $1, link = $2, text = $3 WHERE id = $4",
-		songDetail.ReleaseDate, songDetail.Link, songDetail.Text, id)
+
+	_, err = r.saveSongDetail(songId, songDetail, change, detailUpdate)
 	if err != nil {
 		return fmt.Errorf("%s:%w", op, err)
 	}
 	return nil
 }
 
-func (r *Database) GetSongLibrary(songName string, groupName string, limit int64, offset int64, log *slog.Logger) ([]*model.Song, error) {
+func (r *Database) GetSongLibrary(filter *model.LibraryFilter, page *model.LibraryPage, log *slog.Logger) ([]*model.SongEdge, error) {
 	const op = "internal.storage.postgresql.GetMusicLibrary()"
 
-	var library []*model.Song
+	conditions, score, args := libraryConditions(filter, nil)
+	sortExpressions := librarySortExpressions(score)
 
-	query := "SELECT song_name,group_name FROM songs WHERE TRUE"
-	args := []interface{}{}
-
-	if songName != "" {
-		query += " AND song_name = $" + strconv.Itoa(len(args)+1)
-		args = append(args, songName)
-	}
-	if groupName != "" {
-		query += " AND group_name = $" + strconv.Itoa(len(args)+1)
-		args = append(args, groupName)
+	if page.Cursor != nil {
+		var keyset string
+		keyset, args = keysetCondition(filter.Sort, sortExpressions, page, args)
+		conditions += " AND " + keyset
 	}
+
+	query := "SELECT songs.id, songs.song_name, groups.name, " + score + ", " + releaseDateISO("songs_detail") +
+		", to_char(" + sortExpressions[model.SortReleaseDate] + ", 'YYYY-MM-DD')" + libraryFrom + " WHERE songs.deleted_at IS NULL" + conditions +
+		" ORDER BY " + keysetOrder(filter.Sort, sortExpressions, page.Backward)
 	query += " LIMIT $" + strconv.Itoa(len(args)+1)
-	args = append(args, limit)
-	query += " OFFSET $" + strconv.Itoa(len(args)+1)
-	args = append(args, offset)
+	args = append(args, page.Limit)
 
 	log.Debug("Executing query", slog.String("query", query), slog.Any("args", args))
 
-	err := r.DB.Select(&library, query, args...)
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s:%w", op, err)
 	}
+	defer rows.Close()
+
+	sortString := cursor.SortString(filter.Sort)
+
+	var library []*model.SongEdge
+	for rows.Next() {
+		edge := &model.SongEdge{Node: &model.Song{}}
+		var isoDate sql.NullString
+		var releaseDate string
+		err = rows.Scan(&edge.Node.ID, &edge.Node.SongName, &edge.Node.GroupName, &edge.Score,
+			&isoDate, &releaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		edge.Node.ReleaseDate = isoDate.String
+
+		values := make([]interface{}, 0, len(filter.Sort))
+		for _, key := range filter.Sort {
+			switch key.Field {
+			case model.SortSong:
+				values = append(values, edge.Node.SongName)
+			case model.SortGroup:
+				values = append(values, edge.Node.GroupName)
+			case model.SortReleaseDate:
+				values = append(values, releaseDate)
+			case model.SortScore:
+				values = append(values, *edge.Score)
+			}
+		}
+		edge.Cursor = cursor.Encode(&model.LibraryCursor{
+			Sort:   sortString,
+			Values: values,
+			ID:     edge.Node.ID,
+		})
+
+		library = append(library, edge)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	if page.Backward {
+		slices.Reverse(library)
+	}
+
 	return library, nil
 }
 
-func (r *Database) GetSongText(song *model.Song) (*string, error) {
-	const op = "internal.storage.postgresql.GetSongText()"
+// GetSong returns the names of the song by its id, for the handlers that
+// only get the id.
+func (r *Database) GetSong(id int64) (*model.Song, error) {
+	const op = "internal.storage.postgresql.GetSong()"
+
+	var song model.Song
+	err := r.DB.Get(&song, `SELECT songs.id, songs.song_name, groups.name AS group_name
+		FROM songs
+		JOIN groups ON groups.id = songs.group_id
+		WHERE songs.id = $1 AND songs.deleted_at IS NULL`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &song, nil
+}
+
+func (r *Database) GetSongDetail(song *model.Song) (*model.SongDetail, error) {
+	const op = "internal.storage.postgresql.GetSongDetail()"
 
 	songId, err := r.foundSongId(song)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := r.foundSongDetailId(songId)
+	var songDetail model.SongDetail
+	var releaseDate sql.NullString
+	var sectionsData, sourcesData []byte
+	err = r.DB.QueryRow("SELECT "+releaseDateISO("songs_detail")+`, release_date_precision, link, text, sections, sources
+		FROM songs_detail WHERE song_id = $1`,
+		songId).Scan(&releaseDate, &songDetail.ReleaseDatePrecision, &songDetail.Link, &songDetail.Text, &sectionsData, &sourcesData)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrSongDetailNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
 	}
+	songDetail.ReleaseDate = releaseDate.String
 
-	var text string
-	err = r.DB.QueryRow("SELECT text FROM songs_detail WHERE id = $1", id).Scan(&text)
+	songDetail.Sections, err = detailSections(songDetail.Text, sectionsData)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	songDetail.Sources, err = detailSources(sourcesData)
 	if err != nil {
 		return nil, fmt.Errorf("%s:%w", op, err)
 	}
-	return &text, nil
+
+	return &songDetail, nil
 }
 
-func (r *Database) AddSongDetail(song *model.Song, songDetail *model.SongDetail) error {
+// GetSongDetails returns the details of the songs with the ids in a single
+// query, keyed by song id. Songs without details are left out.
+func (r *Database) GetSongDetails(songIds []int64) (map[int64]*model.SongDetail, error) {
+	const op = "internal.storage.postgresql.GetSongDetails()"
+
+	ids, err := json.Marshal(append([]int64{}, songIds...))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	rows, err := r.DB.Query("SELECT song_id, "+releaseDateISO("songs_detail")+`, release_date_precision, link, text, sections, sources
+		FROM songs_detail
+		WHERE song_id IN (SELECT jsonb_array_elements_text($1::jsonb)::bigint)`,
+		string(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	details := make(map[int64]*model.SongDetail, len(songIds))
+	for rows.Next() {
+		var songId int64
+		var songDetail model.SongDetail
+		var releaseDate sql.NullString
+		var sectionsData, sourcesData []byte
+		err := rows.Scan(&songId, &releaseDate, &songDetail.ReleaseDatePrecision, &songDetail.Link, &songDetail.Text,
+			&sectionsData, &sourcesData)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		songDetail.ReleaseDate = releaseDate.String
+
+		songDetail.Sections, err = detailSections(songDetail.Text, sectionsData)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		songDetail.Sources, err = detailSources(sourcesData)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+
+		details[songId] = &songDetail
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return details, nil
+}
+
+func (r *Database) AddSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
 	const op = "internal.storage.postgresql.AddSongDetail()"
 
 	songId, err := r.foundSongId(song)
@@ -128,18 +264,27 @@ func (r *Database) AddSongDetail(song *model.Song, songDetail *model.SongDetail)
 		return err
 	}
 
-	if _, err := r.foundSongDetailId(songId); err == nil {
-		err = r.PutSongDetail(song, songDetail)
-		return err
+	_, err = r.saveSongDetail(songId, songDetail, change, detailUpsert)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
 	}
+	return nil
+}
 
-	_, err = r.DB.Exec("INSERT INTO songs_detail (release_date, link, text, song_id) VALUES ($1, $2, $3, $4)",
-		songDetail.ReleaseDate, songDetail.Link, songDetail.Text, songId)
+// EnrichSongDetail adds the detail fetched for a new song unless the song
+// has one already, which is never overwritten.
+func (r *Database) EnrichSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error {
+	const op = "internal.storage.postgresql.EnrichSongDetail()"
 
+	songId, err := r.foundSongId(song)
 	if err != nil {
-		return fmt.Errorf("%s:%w", op, err)
+		return err
 	}
 
+	_, err = r.saveSongDetail(songId, songDetail, change, detailInsert)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
 	return nil
 }
 
@@ -147,11 +292,12 @@ func (r *Database) foundSongId(song *model.Song) (int64, error) {
 	op := "internal.storage.postgresql.foundSongId()"
 	var songId int64
 
-	err := r.DB.QueryRow("SELECT id FROM songs WHERE song_name = $1 AND group_name = $2",
+	err := r.DB.QueryRow(`SELECT songs.id FROM songs JOIN groups ON groups.id = songs.group_id
+		WHERE songs.song_name = $1 AND groups.name = $2 AND songs.deleted_at IS NULL`,
 		song.SongName, song.GroupName).Scan(&songId)
 
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return 0, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
 		}
 		return 0, fmt.Errorf("%s:%w", op, err)
@@ -160,6 +306,120 @@ func (r *Database) foundSongId(song *model.Song) (int64, error) {
 	return songId, nil
 }
 
+// detailSources reads the sources column, NULL for details entered by hand.
+func detailSources(data []byte) (map[string]string, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var sources map[string]string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// saveMode tells saveSongDetail what to do with the detail the song has.
+type saveMode int
+
+const (
+	detailUpsert saveMode = iota // replace it, or add one
+	detailUpdate                 // replace it; there must be one
+	detailInsert                 // add one; there must be none
+)
+
+// saveSongDetail writes the detail of the song and records it as the next
+// revision. The song row is locked so concurrent changes get distinct numbers.
+func (r *Database) saveSongDetail(songId int64, songDetail *model.SongDetail, change *model.DetailChange, mode saveMode) (int64, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	revision, err := saveSongDetailTx(tx, songId, songDetail, change, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	return revision, tx.Commit()
+}
+
+// saveSongDetailTx is saveSongDetail within the transaction tx.
+func saveSongDetailTx(tx *sqlx.Tx, songId int64, songDetail *model.SongDetail, change *model.DetailChange, mode saveMode) (int64, error) {
+	releaseDate, precision, err := parseReleaseDate(songDetail)
+	if err != nil {
+		return 0, err
+	}
+	sectionsData, err := json.Marshal(sections.FromDetail(songDetail))
+	if err != nil {
+		return 0, err
+	}
+	var sourcesData *string
+	if len(songDetail.Sources) > 0 {
+		data, err := json.Marshal(songDetail.Sources)
+		if err != nil {
+			return 0, err
+		}
+		sourcesData = new(string)
+		*sourcesData = string(data)
+	}
+
+	var id int64
+	err = tx.QueryRow("SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", songId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrSongNotFound
+		}
+		return 0, err
+	}
+
+	var updated int64
+	if mode == detailInsert {
+		var exists bool
+		err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM songs_detail WHERE song_id = $1)", songId).Scan(&exists)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			return 0, storage.ErrSongDetailExists
+		}
+	} else {
+		res, err := tx.Exec(`UPDATE songs_detail SET release_date = $1, release_date_precision = $2, release_date_raw = NULL,
+			link = $3, text = $4, sections = $6::jsonb, sources = $7::jsonb
+			WHERE song_id = $5`,
+			releaseDate, precision, songDetail.Link, songDetail.Text, songId, string(sectionsData), sourcesData)
+		if err != nil {
+			return 0, err
+		}
+		updated, _ = res.RowsAffected()
+	}
+	if updated == 0 {
+		if mode == detailUpdate {
+			return 0, storage.ErrSongDetailNotFound
+		}
+		_, err = tx.Exec(`INSERT INTO songs_detail (release_date, release_date_precision, link, text, song_id, sections, sources)
+			VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb)`,
+			releaseDate, precision, songDetail.Link, songDetail.Text, songId, string(sectionsData), sourcesData)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var revision int64
+	err = tx.QueryRow(`INSERT INTO songs_detail_revisions
+		(song_id, revision, release_date, release_date_precision, link, text, author, message)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6, $7
+		FROM songs_detail_revisions WHERE song_id = $1
+		RETURNING revision`,
+		songId, releaseDate, precision, songDetail.Link, songDetail.Text, change.Author, change.Message).Scan(&revision)
+	if err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
 func (r *Database) foundSongDetailId(songId int64) (int64, error) {
 	op := "internal.storage.postgresql.foundSongDetailId()"
 	var SongDetailId int64
@@ -168,7 +428,7 @@ func (r *Database) foundSongDetailId(songId int64) (int64, error) {
 		songId).Scan(&SongDetailId)
 
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return 0, fmt.Errorf("%s:%w", op, storage.ErrSongDetailNotFound)
 		}
 		return 0, fmt.Errorf("%s:%w", op, err)
@@ -177,12 +437,14 @@ func (r *Database) foundSongDetailId(songId int64) (int64, error) {
 	return SongDetailId, nil
 }
 
-func (r *Database) CountNumberOfSong(song string, group string) (int64, error) {
+func (r *Database) CountNumberOfSong(filter *model.LibraryFilter) (int64, error) {
 	op := "internal.storage.postgresql.CountNumberOfSong()"
 	var count int64
 
-	err := r.DB.QueryRow("SELECT COUNT(*) FROM songs WHERE ($1 IS NULL OR song_name = $1) AND ($2 IS NULL OR group_name = $2)",
-		song, group).Scan(&count)
+	conditions, _, args := libraryConditions(filter, nil)
+
+	err := r.DB.QueryRow("SELECT COUNT(*)"+libraryFrom+" WHERE songs.deleted_at IS NULL"+conditions,
+		args...).Scan(&count)
 
 	if err != nil {
 		return 0, fmt.Errorf("%s:%w", op, err)
@@ -190,3 +452,143 @@ func (r *Database) CountNumberOfSong(song string, group string) (int64, error) {
 
 	return count, nil
 }
+
+const libraryFrom = " FROM songs JOIN groups ON groups.id = songs.group_id" +
+	" LEFT JOIN songs_detail ON songs_detail.song_id = songs.id"
+
+// librarySortExpressions maps the sort keys to the columns of libraryFrom.
+func librarySortExpressions(score string) map[string]string {
+	return map[string]string{
+		model.SortSong:        "songs.song_name",
+		model.SortGroup:       "groups.name",
+		model.SortReleaseDate: "COALESCE(songs_detail.release_date, DATE '0001-01-01')",
+		model.SortScore:       score,
+	}
+}
+
+// releaseDateISO writes the release_date of table as ISO-8601 reduced to its precision.
+func releaseDateISO(table string) string {
+	return "to_char(" + table + ".release_date, CASE " + table + ".release_date_precision" +
+		" WHEN 'year' THEN 'YYYY' WHEN 'month' THEN 'YYYY-MM' ELSE 'YYYY-MM-DD' END)"
+}
+
+// parseReleaseDate turns the release date of the detail into a DATE value and
+// its precision; an explicit precision of the detail wins over the parsed one.
+// An empty date is NULL, as left by the migration for dates it couldn't read,
+// so that such revisions can still be restored.
+func parseReleaseDate(songDetail *model.SongDetail) (sql.NullTime, string, error) {
+	if strings.TrimSpace(songDetail.ReleaseDate) == "" {
+		return sql.NullTime{}, releasedate.PrecisionDay, nil
+	}
+
+	releaseDate, precision, err := releasedate.Parse(songDetail.ReleaseDate)
+	if err != nil {
+		return sql.NullTime{}, "", err
+	}
+	if songDetail.ReleaseDatePrecision != "" {
+		precision = songDetail.ReleaseDatePrecision
+	}
+	return sql.NullTime{Time: releaseDate, Valid: true}, precision, nil
+}
+
+// libraryConditions builds the WHERE conditions for the song and group filters
+// according to the match mode, together with the similarity score expression
+// that is NULL unless the match is fuzzy.
+func libraryConditions(filter *model.LibraryFilter, args []interface{}) (string, string, []interface{}) {
+	var conditions string
+	var scores []string
+
+	addCondition := func(column string, value string) {
+		if value == "" {
+			return
+		}
+
+		switch filter.Match {
+		case model.MatchPrefix:
+			args = append(args, escapeLike(value)+"%")
+			conditions += " AND " + column + " ILIKE $" + strconv.Itoa(len(args))
+		case model.MatchContains:
+			args = append(args, "%"+escapeLike(value)+"%")
+			conditions += " AND " + column + " ILIKE $" + strconv.Itoa(len(args))
+		case model.MatchFuzzy:
+			args = append(args, value)
+			similarity := "similarity(" + column + ", $" + strconv.Itoa(len(args)) + ")"
+			args = append(args, filter.Threshold)
+			conditions += " AND " + similarity + " >= $" + strconv.Itoa(len(args))
+			scores = append(scores, similarity)
+		default:
+			args = append(args, value)
+			conditions += " AND " + column + " = $" + strconv.Itoa(len(args))
+		}
+	}
+	addCondition("songs.song_name", filter.SongName)
+	addCondition("groups.name", filter.GroupName)
+
+	if filter.ReleasedFrom != nil {
+		args = append(args, *filter.ReleasedFrom)
+		conditions += " AND songs_detail.release_date >= $" + strconv.Itoa(len(args))
+	}
+	if filter.ReleasedTo != nil {
+		args = append(args, *filter.ReleasedTo)
+		conditions += " AND songs_detail.release_date <= $" + strconv.Itoa(len(args))
+	}
+
+	score := "NULL::float8"
+	if len(scores) > 0 {
+		score = "((" + strings.Join(scores, " + ") + ") / " + strconv.Itoa(len(scores)) + ")::float8"
+	}
+
+	return conditions, score, args
+}
+
+// keysetOrder builds the ORDER BY list for the sort keys followed by songs.id,
+// with every direction flipped when paging backward.
+func keysetOrder(keys []model.SortKey, expressions map[string]string, backward bool) string {
+	order := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		if key.Desc != backward {
+			order = append(order, expressions[key.Field]+" DESC")
+		} else {
+			order = append(order, expressions[key.Field]+" ASC")
+		}
+	}
+	if backward {
+		order = append(order, "songs.id DESC")
+	} else {
+		order = append(order, "songs.id ASC")
+	}
+	return strings.Join(order, ", ")
+}
+
+// keysetCondition selects the rows that come after page.Cursor in the
+// keysetOrder ordering: (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... down to songs.id.
+func keysetCondition(keys []model.SortKey, expressions map[string]string, page *model.LibraryPage, args []interface{}) (string, []interface{}) {
+	var alternatives []string
+	var equal string
+
+	for i, key := range keys {
+		args = append(args, page.Cursor.Values[i])
+		column := expressions[key.Field]
+		param := "$" + strconv.Itoa(len(args))
+
+		operator := ">"
+		if key.Desc != page.Backward {
+			operator = "<"
+		}
+		alternatives = append(alternatives, "("+equal+column+" "+operator+" "+param+")")
+		equal += column + " = " + param + " AND "
+	}
+
+	args = append(args, page.Cursor.ID)
+	operator := ">"
+	if page.Backward {
+		operator = "<"
+	}
+	alternatives = append(alternatives, "("+equal+"songs.id "+operator+" $"+strconv.Itoa(len(args))+")")
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
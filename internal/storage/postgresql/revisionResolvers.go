@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+func revisionColumns() string {
+	return "revision, song_id, " + releaseDateISO("songs_detail_revisions") +
+		", release_date_precision, link, text, author, message, created_at"
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRevision(row rowScanner) (*model.Revision, error) {
+	revision := &model.Revision{Detail: &model.SongDetail{}}
+	var releaseDate sql.NullString
+
+	err := row.Scan(&revision.Revision, &revision.SongID, &releaseDate, &revision.Detail.ReleaseDatePrecision,
+		&revision.Detail.Link, &revision.Detail.Text, &revision.Author, &revision.Message, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.Detail.ReleaseDate = releaseDate.String
+
+	return revision, nil
+}
+
+// GetRevisions lists the revisions of the song detail, newest first.
+func (r *Database) GetRevisions(songId int64, limit int64, offset int64) ([]*model.Revision, error) {
+	const op = "internal.storage.postgresql.GetRevisions()"
+
+	rows, err := r.DB.Query("SELECT "+revisionColumns()+` FROM songs_detail_revisions
+		WHERE song_id = $1 ORDER BY revision DESC LIMIT $2 OFFSET $3`,
+		songId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	var revisions []*model.Revision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return revisions, nil
+}
+
+func (r *Database) CountRevisions(songId int64) (int64, error) {
+	const op = "internal.storage.postgresql.CountRevisions()"
+
+	var exists bool
+	var count int64
+	err := r.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL),
+		(SELECT COUNT(*) FROM songs_detail_revisions WHERE song_id = $1)`,
+		songId).Scan(&exists, &count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+	if !exists {
+		return 0, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+	}
+
+	return count, nil
+}
+
+func (r *Database) GetRevision(songId int64, revision int64) (*model.Revision, error) {
+	const op = "internal.storage.postgresql.GetRevision()"
+
+	rev, err := scanRevision(r.DB.QueryRow("SELECT "+revisionColumns()+` FROM songs_detail_revisions
+		WHERE song_id = $1 AND revision = $2`,
+		songId, revision))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrRevisionNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return rev, nil
+}
+
+// RestoreRevision writes the detail of an old revision back as a new revision.
+func (r *Database) RestoreRevision(songId int64, revision int64, change *model.DetailChange) (*model.Revision, error) {
+	const op = "internal.storage.postgresql.RestoreRevision()"
+
+	old, err := r.GetRevision(songId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := r.saveSongDetail(songId, old.Detail, change, detailUpsert)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return r.GetRevision(songId, restored)
+}
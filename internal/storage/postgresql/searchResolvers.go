@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/model"
+)
+
+const searchHeadlineOptions = `StartSel=<b>, StopSel=</b>, MaxFragments=2, FragmentDelimiter=" ... "`
+
+func (r *Database) SearchSongs(query string, language string, limit int64, offset int64) ([]*model.SearchResult, error) {
+	const op = "internal.storage.postgresql.SearchSongs()"
+
+	rows, err := r.DB.Query(`SELECT songs.id, songs.song_name, groups.name,
+			ts_rank(songs_detail.text_search, query) AS rank,
+			ts_headline($1::regconfig, songs_detail.text, query, $3) AS snippet
+		FROM songs_detail
+		JOIN songs ON songs.id = songs_detail.song_id
+		JOIN groups ON groups.id = songs.group_id,
+		websearch_to_tsquery($1::regconfig, $2) AS query
+		WHERE songs_detail.text_search @@ query AND songs.deleted_at IS NULL
+		ORDER BY rank DESC, songs.id
+		LIMIT $4 OFFSET $5`,
+		language, query, searchHeadlineOptions, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	var results []*model.SearchResult
+	for rows.Next() {
+		result := &model.SearchResult{Song: &model.Song{}}
+		err = rows.Scan(&result.Song.ID, &result.Song.SongName, &result.Song.GroupName, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return results, nil
+}
+
+func (r *Database) CountSearchSongs(query string, language string) (int64, error) {
+	const op = "internal.storage.postgresql.CountSearchSongs()"
+
+	var count int64
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM songs_detail
+		JOIN songs ON songs.id = songs_detail.song_id
+		WHERE songs_detail.text_search @@ websearch_to_tsquery($1::regconfig, $2) AND songs.deleted_at IS NULL`,
+		language, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return count, nil
+}
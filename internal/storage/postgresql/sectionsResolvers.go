@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/lib/lrc"
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// GetSongSections returns the sections of the song lyrics. Time-synced
+// lyrics take the place of the detail text.
+func (r *Database) GetSongSections(song *model.Song) ([]*model.LyricsSection, error) {
+	const op = "internal.storage.postgresql.GetSongSections()"
+
+	songId, err := r.foundSongId(song)
+	if err != nil {
+		return nil, err
+	}
+
+	lyricsSections, err := r.songSections(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return lyricsSections, nil
+}
+
+// songSections is GetSongSections for the song with the id songId.
+func (r *Database) songSections(songId int64) ([]*model.LyricsSection, error) {
+	lyrics, err := r.GetLyrics(songId)
+	if err == nil {
+		return sections.Parse(lrc.PlainText(lyrics)), nil
+	}
+	if !errors.Is(err, storage.ErrLyricsNotFound) {
+		return nil, err
+	}
+
+	var text string
+	var data []byte
+	err = r.DB.QueryRow("SELECT text, sections FROM songs_detail WHERE song_id = $1", songId).Scan(&text, &data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrSongDetailNotFound
+		}
+		return nil, err
+	}
+	return detailSections(text, data)
+}
+
+// detailSections reads the stored sections of a detail; details saved before
+// sections were stored are parsed from their text.
+func detailSections(text string, data []byte) ([]*model.LyricsSection, error) {
+	if data == nil {
+		return sections.Parse(text), nil
+	}
+
+	var lyricsSections []*model.LyricsSection
+	if err := json.Unmarshal(data, &lyricsSections); err != nil {
+		return nil, err
+	}
+	return lyricsSections, nil
+}
@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nabishec/restapi/internal/lib/sections"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// PutTranslation replaces the translation of the song to the language of
+// the translation. It must have as many sections as the song lyrics.
+func (r *Database) PutTranslation(translation *model.Translation) error {
+	const op = "internal.storage.postgresql.PutTranslation()"
+
+	original, err := r.songSections(translation.SongID)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	translated := sections.ParseTranslation(translation.Text)
+	if !sections.Align(translated, original) {
+		return fmt.Errorf("%s:%w: %d sections for %d", op, storage.ErrTranslationNotAligned,
+			len(translated), len(original))
+	}
+
+	err = r.DB.QueryRow(`INSERT INTO song_translations (song_id, language, text) VALUES ($1, $2, $3)
+		ON CONFLICT (song_id, language) DO UPDATE SET text = EXCLUDED.text, updated_at = now()
+		RETURNING updated_at`,
+		translation.SongID, translation.Language, translation.Text).Scan(&translation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	translation.Sections = translated
+	return nil
+}
+
+func (r *Database) GetTranslation(songId int64, language string) (*model.Translation, error) {
+	const op = "internal.storage.postgresql.GetTranslation()"
+
+	if err := r.checkSong(songId); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	translation := &model.Translation{SongID: songId, Language: language}
+	err := r.DB.QueryRow("SELECT text, updated_at FROM song_translations WHERE song_id = $1 AND language = $2",
+		songId, language).Scan(&translation.Text, &translation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrTranslationNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err := r.alignTranslations(songId, translation); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return translation, nil
+}
+
+// GetTranslations lists the translations of the song by language.
+func (r *Database) GetTranslations(songId int64) ([]*model.Translation, error) {
+	const op = "internal.storage.postgresql.GetTranslations()"
+
+	if err := r.checkSong(songId); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	translations, err := r.songTranslations(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return translations, nil
+}
+
+// GetSongTranslations is GetTranslations for the song given by its name and group.
+func (r *Database) GetSongTranslations(song *model.Song) ([]*model.Translation, error) {
+	const op = "internal.storage.postgresql.GetSongTranslations()"
+
+	songId, err := r.foundSongId(song)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := r.songTranslations(songId)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return translations, nil
+}
+
+func (r *Database) DeleteTranslation(songId int64, language string) error {
+	const op = "internal.storage.postgresql.DeleteTranslation()"
+
+	if err := r.checkSong(songId); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	res, err := r.DB.Exec("DELETE FROM song_translations WHERE song_id = $1 AND language = $2", songId, language)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrTranslationNotFound)
+	}
+	return nil
+}
+
+// checkSong makes sure the song with the id songId exists and is not in the trash.
+func (r *Database) checkSong(songId int64) error {
+	var id int64
+	err := r.DB.QueryRow("SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL", songId).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrSongNotFound
+	}
+	return err
+}
+
+func (r *Database) songTranslations(songId int64) ([]*model.Translation, error) {
+	rows, err := r.DB.Query(`SELECT language, text, updated_at FROM song_translations
+		WHERE song_id = $1 ORDER BY language`, songId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*model.Translation
+	for rows.Next() {
+		translation := &model.Translation{SongID: songId}
+		if err := rows.Scan(&translation.Language, &translation.Text, &translation.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.alignTranslations(songId, translations...); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// alignTranslations splits the translations into sections aligned to the
+// current sections of the song lyrics, as far as they still match them.
+func (r *Database) alignTranslations(songId int64, translations ...*model.Translation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	original, err := r.songSections(songId)
+	if err != nil && !errors.Is(err, storage.ErrSongDetailNotFound) {
+		return err
+	}
+
+	for _, translation := range translations {
+		translation.Sections = sections.ParseTranslation(translation.Text)
+		sections.Align(translation.Sections, original)
+	}
+	return nil
+}
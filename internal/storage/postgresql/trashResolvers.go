@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+// GetTrash lists the deleted songs, most recently deleted first.
+func (r *Database) GetTrash(limit int64, offset int64) ([]*model.Song, error) {
+	const op = "internal.storage.postgresql.GetTrash()"
+
+	var songs []*model.Song
+	err := r.DB.Select(&songs, `SELECT songs.id, songs.song_name, groups.name AS group_name, songs.deleted_at
+		FROM songs
+		JOIN groups ON groups.id = songs.group_id
+		WHERE songs.deleted_at IS NOT NULL
+		ORDER BY songs.deleted_at DESC, songs.id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return songs, nil
+}
+
+func (r *Database) CountTrash() (int64, error) {
+	const op = "internal.storage.postgresql.CountTrash()"
+
+	var count int64
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM songs WHERE deleted_at IS NOT NULL").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return count, nil
+}
+
+// RestoreSong takes a song out of the trash unless another song with the
+// same name was added to its group in the meantime.
+func (r *Database) RestoreSong(id int64) (*model.Song, error) {
+	const op = "internal.storage.postgresql.RestoreSong()"
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer tx.Rollback()
+
+	var song model.Song
+	err = tx.Get(&song, `SELECT songs.id, songs.song_name, groups.name AS group_name
+		FROM songs
+		JOIN groups ON groups.id = songs.group_id
+		WHERE songs.id = $1 AND songs.deleted_at IS NOT NULL
+		FOR UPDATE OF songs`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrSongNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	var taken bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM songs
+		WHERE song_name = $1 AND group_id = (SELECT group_id FROM songs WHERE id = $2)
+			AND deleted_at IS NULL)`,
+		song.SongName, id).Scan(&taken)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	if taken {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrSongAlreadyExists)
+	}
+
+	_, err = tx.Exec("UPDATE songs SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return &song, nil
+}
+
+// PurgeSongs removes for good the songs deleted before the given time, with
+// their details, revisions, album tracks and playlist entries.
+func (r *Database) PurgeSongs(before time.Time) (int64, error) {
+	const op = "internal.storage.postgresql.PurgeSongs()"
+
+	res, err := r.DB.Exec("DELETE FROM songs WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,297 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+const deliveryColumns = `webhook_deliveries.id, webhook_deliveries.webhook_id, webhooks.url, webhooks.secret,
+	webhook_deliveries.state, webhook_deliveries.attempts, webhook_deliveries.last_error,
+	webhook_deliveries.replay_of, webhook_deliveries.run_at, webhook_deliveries.created_at,
+	webhook_deliveries.updated_at, webhook_deliveries.finished_at,
+	events.id, events.type, events.song_id, events.song_name, events.group_name, events.data,
+	events.request_id, events.created_at`
+
+const deliveryJoins = `JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+	JOIN events ON events.id = webhook_deliveries.event_id`
+
+func (r *Database) AddWebhook(hook *model.Webhook) error {
+	const op = "internal.storage.postgresql.AddWebhook()"
+
+	events, err := json.Marshal(hook.Events)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	err = r.DB.QueryRow(`INSERT INTO webhooks (url, events, secret) VALUES ($1, $2::jsonb, $3)
+		RETURNING id, created_at`,
+		hook.URL, string(events), hook.Secret).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+func (r *Database) GetWebhooks() ([]*model.Webhook, error) {
+	const op = "internal.storage.postgresql.GetWebhooks()"
+
+	rows, err := r.DB.Query("SELECT id, url, events, secret, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	var hooks []*model.Webhook
+	for rows.Next() {
+		var hook model.Webhook
+		var events []byte
+		if err := rows.Scan(&hook.ID, &hook.URL, &events, &hook.Secret, &hook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		if err := json.Unmarshal(events, &hook.Events); err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		hooks = append(hooks, &hook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes the webhook together with its deliveries.
+func (r *Database) DeleteWebhook(id int64) error {
+	const op = "internal.storage.postgresql.DeleteWebhook()"
+
+	res, err := r.DB.Exec("DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		return fmt.Errorf("%s:%w", op, storage.ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// ClaimDelivery takes the next due delivery, or a running one whose lease
+// has passed, and holds it for lease, like ClaimJob.
+func (r *Database) ClaimDelivery(lease time.Duration) (*model.WebhookDelivery, error) {
+	const op = "internal.storage.postgresql.ClaimDelivery()"
+
+	row := r.DB.QueryRow(`WITH claimed AS (
+			UPDATE webhook_deliveries SET state = 'running', attempts = attempts + 1,
+				locked_until = now() + make_interval(secs => $1), updated_at = now()
+			WHERE id = (
+				SELECT id FROM webhook_deliveries
+				WHERE state = 'queued' AND run_at <= now()
+					OR state = 'running' AND locked_until < now()
+				ORDER BY run_at, id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT `+deliveryColumns+` FROM claimed AS webhook_deliveries `+deliveryJoins,
+		lease.Seconds())
+
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrDeliveryNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return delivery, nil
+}
+
+// CompleteDelivery marks the claimed delivery succeeded and logs its attempt.
+func (r *Database) CompleteDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error {
+	const op = "internal.storage.postgresql.CompleteDelivery()"
+
+	err := r.finishDelivery(delivery, attempt, `state = 'succeeded', last_error = '', finished_at = now()`)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// RetryDelivery logs the failed attempt and queues the delivery again to
+// run at runAt.
+func (r *Database) RetryDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt, runAt time.Time) error {
+	const op = "internal.storage.postgresql.RetryDelivery()"
+
+	err := r.finishDelivery(delivery, attempt, `state = 'queued', last_error = $3, run_at = $4`, attempt.Error, runAt)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// FailDelivery logs the failed attempt and marks the delivery failed for good.
+func (r *Database) FailDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error {
+	const op = "internal.storage.postgresql.FailDelivery()"
+
+	err := r.finishDelivery(delivery, attempt, `state = 'failed', last_error = $3, finished_at = now()`, attempt.Error)
+	if err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+	return nil
+}
+
+// finishDelivery ends the attempt of the delivery and logs it, unless the
+// delivery has been claimed again since, like finishJob.
+func (r *Database) finishDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt, set string, args ...interface{}) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE webhook_deliveries SET `+set+`, locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`,
+		append([]interface{}{delivery.ID, delivery.Attempts}, args...)...)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return storage.ErrDeliveryNotFound
+	}
+
+	err = tx.QueryRow(`INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`,
+		delivery.ID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMs).Scan(&attempt.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetWebhookDeliveries returns the last deliveries to the webhook, newest
+// first, in the state if one is given, with the log of their attempts.
+func (r *Database) GetWebhookDeliveries(webhookId int64, state string, first int) ([]*model.WebhookDelivery, error) {
+	const op = "internal.storage.postgresql.GetWebhookDeliveries()"
+
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1)", webhookId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s:%w", op, storage.ErrWebhookNotFound)
+	}
+
+	const filter = `webhook_deliveries.webhook_id = $1 AND ($2 = '' OR webhook_deliveries.state = $2)`
+
+	rows, err := r.DB.Query(`SELECT `+deliveryColumns+` FROM webhook_deliveries `+deliveryJoins+`
+		WHERE `+filter+`
+		ORDER BY webhook_deliveries.id DESC
+		LIMIT $3`,
+		webhookId, state, first)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	byId := make(map[int64]*model.WebhookDelivery)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+		byId[delivery.ID] = delivery
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	attempts, err := r.DB.Query(`SELECT delivery_id, attempt, status_code, error, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id IN (
+			SELECT webhook_deliveries.id FROM webhook_deliveries
+			WHERE `+filter+`
+			ORDER BY webhook_deliveries.id DESC
+			LIMIT $3
+		)
+		ORDER BY delivery_id, attempt`,
+		webhookId, state, first)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	defer attempts.Close()
+
+	for attempts.Next() {
+		var deliveryId int64
+		var attempt model.DeliveryAttempt
+		err := attempts.Scan(&deliveryId, &attempt.Attempt, &attempt.StatusCode, &attempt.Error,
+			&attempt.DurationMs, &attempt.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", op, err)
+		}
+		// A delivery queued between the two queries isn't in the list.
+		if delivery, ok := byId[deliveryId]; ok {
+			delivery.Log = append(delivery.Log, &attempt)
+		}
+	}
+	if err := attempts.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDelivery queues the event of the delivery to its webhook once more,
+// as a new delivery.
+func (r *Database) ReplayDelivery(id int64) (*model.WebhookDelivery, error) {
+	const op = "internal.storage.postgresql.ReplayDelivery()"
+
+	row := r.DB.QueryRow(`WITH replayed AS (
+			INSERT INTO webhook_deliveries (webhook_id, event_id, replay_of)
+			SELECT webhook_id, event_id, id FROM webhook_deliveries WHERE id = $1
+			RETURNING *
+		)
+		SELECT `+deliveryColumns+` FROM replayed AS webhook_deliveries `+deliveryJoins, id)
+
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s:%w", op, storage.ErrDeliveryNotFound)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
+	}
+	return delivery, nil
+}
+
+func scanDelivery(row rowScanner) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{Event: &model.Event{Song: &model.Song{}}}
+	var replayOf, songId sql.NullInt64
+	var finishedAt sql.NullTime
+	var data []byte
+
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.URL, &delivery.Secret,
+		&delivery.State, &delivery.Attempts, &delivery.LastError,
+		&replayOf, &delivery.RunAt, &delivery.CreatedAt,
+		&delivery.UpdatedAt, &finishedAt,
+		&delivery.Event.ID, &delivery.Event.Type, &songId, &delivery.Event.Song.SongName,
+		&delivery.Event.Song.GroupName, &data, &delivery.Event.RequestID, &delivery.Event.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.ReplayOf = replayOf.Int64
+	delivery.Event.Song.ID = songId.Int64
+	delivery.Event.Data = data
+	if finishedAt.Valid {
+		delivery.FinishedAt = &finishedAt.Time
+	}
+	return delivery, nil
+}
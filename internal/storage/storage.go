@@ -3,7 +3,28 @@ package storage
 import "errors"
 
 var (
-	ErrSongNotFound       = errors.New("song not found")
-	ErrSongDetailNotFound = errors.New("song detail not found")
-	ErrSongAlreadyExists  = errors.New("song exists")
+	ErrSongNotFound          = errors.New("song not found")
+	ErrSongDetailNotFound    = errors.New("song detail not found")
+	ErrSongDetailExists      = errors.New("song detail exists")
+	ErrSongAlreadyExists     = errors.New("song exists")
+	ErrGroupNotFound         = errors.New("group not found")
+	ErrGroupAlreadyExists    = errors.New("group exists")
+	ErrGroupHasSongs         = errors.New("group has songs")
+	ErrGroupHasTrashedSongs  = errors.New("group has songs in the trash")
+	ErrAlbumNotFound         = errors.New("album not found")
+	ErrAlbumAlreadyExists    = errors.New("album exists")
+	ErrTrackAlreadyExists    = errors.New("track exists")
+	ErrPlaylistNotFound      = errors.New("playlist not found")
+	ErrPlaylistAlreadyExists = errors.New("playlist exists")
+	ErrEntryNotFound         = errors.New("playlist entry not found")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrLyricsNotFound        = errors.New("lyrics not found")
+	ErrLyricLineNotFound     = errors.New("lyric line not found")
+	ErrChordSheetNotFound    = errors.New("chord sheet not found")
+	ErrTranslationNotFound   = errors.New("translation not found")
+	ErrTranslationNotAligned = errors.New("translation not aligned to the song sections")
+	ErrJobNotFound           = errors.New("job not found")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
 )
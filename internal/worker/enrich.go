@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/nabishec/restapi/internal/clients"
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type EnricherImp interface {
+	ClaimJob(kind string, lease time.Duration) (*model.Job, error)
+	CompleteJob(job *model.Job) error
+	RetryJob(job *model.Job, lastError string, runAt time.Time) error
+	FailJob(job *model.Job, lastError string) error
+	GetSongDetail(song *model.Song) (*model.SongDetail, error)
+	EnrichSongDetail(song *model.Song, songDetail *model.SongDetail, change *model.DetailChange) error
+	AddAuditRecord(record *model.AuditRecord) error
+	AddEvent(event *model.Event) error
+}
+
+type SongDetailFetchingImp interface {
+	GetSongDetail(ctx context.Context, song *model.Song) (*model.SongDetail, error)
+}
+
+// Enrich runs cfg.Workers workers fetching the details of new songs from the
+// metadata providers until ctx is done, and waits for them to stop.
+func Enrich(ctx context.Context, log *slog.Logger, enricher EnricherImp, fetching SongDetailFetchingImp, cfg config.Enrichment) {
+	const op = "internal.worker.Enrich()"
+
+	log = log.With(slog.String("op", op))
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(cfg.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			enrichJobs(ctx, log.With(slog.Int("worker", i)), enricher, fetching, cfg)
+		}()
+	}
+	wg.Wait()
+}
+
+// enrichJobs runs the queued jobs one by one, and polls the queue once every
+// cfg.PollInterval while it is empty.
+func enrichJobs(ctx context.Context, log *slog.Logger, enricher EnricherImp, fetching SongDetailFetchingImp, cfg config.Enrichment) {
+	for ctx.Err() == nil {
+		job, err := enricher.ClaimJob(model.JobEnrichSong, cfg.Lease)
+		if err == nil {
+			enrichSong(ctx, log, enricher, fetching, cfg, job)
+			continue
+		}
+		if !errors.Is(err, storage.ErrJobNotFound) {
+			log.Error("failed to claim job", slerr.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(cfg.PollInterval):
+		}
+	}
+}
+
+func enrichSong(ctx context.Context, log *slog.Logger, enricher EnricherImp, fetching SongDetailFetchingImp, cfg config.Enrichment, job *model.Job) {
+	log = log.With(slog.Int64("job", job.ID), slog.Int("attempt", job.Attempts))
+
+	// Details put by hand before the job ran are never overwritten, and a job
+	// run again after adding its details finds them and only completes.
+	if _, err := enricher.GetSongDetail(job.Song); err == nil {
+		skipEnrichment(log, enricher, job)
+		return
+	}
+
+	// The attempt must end before the lease does, or another worker takes the job.
+	fetchCtx, cancel := context.WithTimeout(ctx, cfg.Lease)
+	defer cancel()
+
+	songDetail, err := fetching.GetSongDetail(fetchCtx, job.Song)
+	if err == nil {
+		err = enricher.EnrichSongDetail(job.Song, songDetail, &model.DetailChange{
+			Author:  job.Author,
+			Message: "details fetched from metadata providers",
+		})
+	}
+
+	switch {
+	case errors.Is(err, storage.ErrSongDetailExists):
+		// The details were put while they were being fetched.
+		skipEnrichment(log, enricher, job)
+		return
+	case err == nil:
+		err = enricher.CompleteJob(job)
+		recordEnrichment(log, enricher, job, songDetail)
+		publishEnrichment(log, enricher, job, model.EventDetailCreated, songDetail)
+		log.Info("song enriched")
+	case ctx.Err() != nil:
+		// The service is stopping; the job is run again once its lease passes.
+		log.Info("song enrichment interrupted", slerr.Err(err))
+		return
+	case permanent(err) || job.Attempts >= job.MaxAttempts:
+		log.Error("song enrichment failed", slerr.Err(err))
+		job.LastError = err.Error()
+		err = enricher.FailJob(job, job.LastError)
+		if err == nil {
+			job.State = model.JobFailed
+			publishEnrichment(log, enricher, job, model.EventEnrichmentFailed, job)
+		}
+	default:
+		runAt := time.Now().Add(retryDelay(cfg.RetryBaseDelay, cfg.RetryMaxDelay, job.Attempts))
+		log.Info("song enrichment will be retried", slog.Time("runAt", runAt), slerr.Err(err))
+		err = enricher.RetryJob(job, err.Error(), runAt)
+	}
+	if err != nil {
+		log.Error("failed to update job", slerr.Err(err))
+	}
+}
+
+// skipEnrichment completes the job of a song which has details already.
+func skipEnrichment(log *slog.Logger, enricher EnricherImp, job *model.Job) {
+	log.Info("song already has details, enrichment skipped")
+	if err := enricher.CompleteJob(job); err != nil {
+		log.Error("failed to update job", slerr.Err(err))
+	}
+}
+
+// permanent tells whether the error would come again however many times the
+// job is retried.
+func permanent(err error) bool {
+	return errors.Is(err, clients.ErrNotFound) || errors.Is(err, clients.ErrBadRequest) ||
+		errors.Is(err, clients.ErrNotConfigured) || errors.Is(err, storage.ErrSongNotFound)
+}
+
+// retryDelay doubles baseDelay with every attempt up to maxDelay; a random
+// half of it is jitter.
+func retryDelay(baseDelay time.Duration, maxDelay time.Duration, attempt int) time.Duration {
+	delay := baseDelay << max(attempt-1, 0)
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// recordEnrichment audits the details added by the job on behalf of the
+// request that queued it.
+func recordEnrichment(log *slog.Logger, recorder EnricherImp, job *model.Job, songDetail *model.SongDetail) {
+	after, err := json.Marshal(songDetail)
+	if err != nil {
+		log.Error("failed to marshal audit value", slerr.Err(err))
+	}
+
+	err = recorder.AddAuditRecord(&model.AuditRecord{
+		Operation: model.AuditAddSongDetail,
+		SongName:  job.Song.SongName,
+		GroupName: job.Song.GroupName,
+		RequestID: job.RequestID,
+		Actor:     job.Author,
+		After:     after,
+	})
+	if err != nil {
+		log.Error("failed to record audit", slog.String("operation", model.AuditAddSongDetail), slerr.Err(err))
+	}
+}
+
+// publishEnrichment adds the event of the job to the event log on behalf of
+// the request that queued it.
+func publishEnrichment(log *slog.Logger, publisher EnricherImp, job *model.Job, eventType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Error("failed to marshal event data", slerr.Err(err))
+	}
+
+	err = publisher.AddEvent(&model.Event{
+		Type:      eventType,
+		Song:      &model.Song{ID: job.SongID, SongName: job.Song.SongName, GroupName: job.Song.GroupName},
+		Data:      raw,
+		RequestID: job.RequestID,
+	})
+	if err != nil {
+		log.Error("failed to publish event", slog.String("type", eventType), slerr.Err(err))
+	}
+}
@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nabishec/restapi/internal/clients"
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+	"github.com/nabishec/restapi/internal/storage/memory"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fetcher answers the fetches with err, or with details when it is nil.
+type fetcher struct {
+	err error
+}
+
+func (f *fetcher) GetSongDetail(ctx context.Context, song *model.Song) (*model.SongDetail, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &model.SongDetail{ReleaseDate: "2006-07-16", ReleaseDatePrecision: "day", Link: "https://example.com", Text: "a"}, nil
+}
+
+var enrichConfig = config.Enrichment{
+	RetryBaseDelay: time.Minute,
+	RetryMaxDelay:  time.Hour,
+	Lease:          time.Minute,
+}
+
+// queuedJob adds a song with its enrichment job to a new storage.
+func queuedJob(t *testing.T, maxAttempts int) (*memory.Storage, *model.Job) {
+	t.Helper()
+
+	songStorage := memory.NewStorage()
+	job := &model.Job{Kind: model.JobEnrichSong, MaxAttempts: maxAttempts}
+	if err := songStorage.AddSongAndEnqueue(&model.Song{SongName: "Song1", GroupName: "Group1"}, job); err != nil {
+		t.Fatal(err)
+	}
+	return songStorage, job
+}
+
+// runJob claims the job and runs one attempt of it.
+func runJob(t *testing.T, songStorage *memory.Storage, fetching SongDetailFetchingImp) *model.Job {
+	t.Helper()
+
+	job, err := songStorage.ClaimJob(model.JobEnrichSong, enrichConfig.Lease)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrichSong(context.Background(), discard, songStorage, fetching, enrichConfig, job)
+
+	job, err = songStorage.GetJob(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestEnrichSong(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		maxAttempts int
+		state       string
+	}{
+		{name: "fetched", state: model.JobSucceeded, maxAttempts: 3},
+		{name: "unavailable", err: clients.ErrUnavailable, maxAttempts: 3, state: model.JobQueued},
+		{name: "unavailable on the last attempt", err: clients.ErrUnavailable, maxAttempts: 1, state: model.JobFailed},
+		{name: "unknown song", err: clients.ErrNotFound, maxAttempts: 3, state: model.JobFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songStorage, _ := queuedJob(t, tt.maxAttempts)
+
+			job := runJob(t, songStorage, &fetcher{err: tt.err})
+			if job.State != tt.state || job.Attempts != 1 {
+				t.Fatalf("job %s after %d attempts, want %s after 1", job.State, job.Attempts, tt.state)
+			}
+			if (tt.err != nil) != (job.LastError != "") {
+				t.Errorf("last error = %q", job.LastError)
+			}
+
+			_, err := songStorage.GetSongDetail(job.Song)
+			if (tt.err == nil) != (err == nil) {
+				t.Errorf("song detail error = %v", err)
+			}
+			// The first retry waits half to all of the base delay.
+			if wait := time.Until(job.RunAt); tt.state == model.JobQueued && wait < enrichConfig.RetryBaseDelay/2-time.Second {
+				t.Errorf("retry in %s, want at least %s", wait, enrichConfig.RetryBaseDelay/2)
+			}
+		})
+	}
+}
+
+func TestEnrichSongRetried(t *testing.T) {
+	songStorage, _ := queuedJob(t, 3)
+
+	job := runJob(t, songStorage, &fetcher{err: clients.ErrUnavailable})
+	if job.State != model.JobQueued {
+		t.Fatalf("job %s, want %s", job.State, model.JobQueued)
+	}
+	// The retry waits for its backoff.
+	if _, err := songStorage.ClaimJob(model.JobEnrichSong, enrichConfig.Lease); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Fatalf("retried job claimed before its time: %v", err)
+	}
+}
+
+func TestClaimJobLease(t *testing.T) {
+	songStorage, _ := queuedJob(t, 3)
+
+	first, err := songStorage.ClaimJob(model.JobEnrichSong, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := songStorage.ClaimJob(model.JobEnrichSong, time.Minute); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Fatalf("job claimed twice within its lease: %v", err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	second, err := songStorage.ClaimJob(model.JobEnrichSong, time.Minute)
+	if err != nil {
+		t.Fatalf("job not claimed again after its lease: %v", err)
+	}
+	if second.ID != first.ID || second.Attempts != 2 {
+		t.Fatalf("claimed job %d attempt %d, want job %d attempt 2", second.ID, second.Attempts, first.ID)
+	}
+
+	// The attempt whose lease passed no longer owns the job.
+	if err := songStorage.CompleteJob(first); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Errorf("stale attempt completed the job: %v", err)
+	}
+	if err := songStorage.CompleteJob(second); err != nil {
+		t.Errorf("current attempt failed to complete the job: %v", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{attempt: 1, min: 5 * time.Second, max: 10 * time.Second},
+		{attempt: 2, min: 10 * time.Second, max: 20 * time.Second},
+		{attempt: 4, min: 40 * time.Second, max: 80 * time.Second},
+		{attempt: 10, min: 50 * time.Second, max: 100 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			if delay := retryDelay(10*time.Second, 100*time.Second, tt.attempt); delay < tt.min || delay > tt.max {
+				t.Errorf("retryDelay(attempt %d) = %s, want between %s and %s", tt.attempt, delay, tt.min, tt.max)
+			}
+		}
+	}
+}
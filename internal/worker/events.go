@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/eventbus"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+)
+
+// listenRetryDelay is the pause before listening again after a failure.
+const listenRetryDelay = 5 * time.Second
+
+type EventListenerImp interface {
+	ListenEvents(ctx context.Context, notify func()) error
+}
+
+// ListenEvents wakes the subscribers of the bus whenever an event is added
+// to the log, until ctx is done. A lost connection is made again; events
+// added meanwhile are read once it is.
+func ListenEvents(ctx context.Context, log *slog.Logger, listener EventListenerImp, bus *eventbus.Bus) {
+	const op = "internal.worker.ListenEvents()"
+
+	log = log.With(slog.String("op", op))
+
+	for ctx.Err() == nil {
+		if err := listener.ListenEvents(ctx, bus.Notify); err != nil {
+			log.Error("failed to listen for events", slerr.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
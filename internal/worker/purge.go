@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+)
+
+type PurgerImp interface {
+	PurgeSongs(before time.Time) (int64, error)
+}
+
+// Purge removes for good the songs that have been in the trash longer than
+// retention, once every interval until ctx is done.
+func Purge(ctx context.Context, log *slog.Logger, purger PurgerImp, interval time.Duration, retention time.Duration) {
+	const op = "internal.worker.Purge()"
+
+	log = log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeSongs(time.Now().Add(-retention))
+		if err != nil {
+			log.Error("failed to purge trash", slerr.Err(err))
+		} else if purged > 0 {
+			log.Info("trash purged", slog.Int64("songs", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
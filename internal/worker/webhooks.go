@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/lib/logger/slerr"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+)
+
+type DelivererImp interface {
+	ClaimDelivery(lease time.Duration) (*model.WebhookDelivery, error)
+	CompleteDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error
+	RetryDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt, runAt time.Time) error
+	FailDelivery(delivery *model.WebhookDelivery, attempt *model.DeliveryAttempt) error
+}
+
+type WebhookSendingImp interface {
+	Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error)
+}
+
+// Deliver runs cfg.Workers workers sending the queued events to the
+// webhooks until ctx is done, and waits for them to stop.
+func Deliver(ctx context.Context, log *slog.Logger, deliverer DelivererImp, sending WebhookSendingImp, cfg config.Webhooks) {
+	const op = "internal.worker.Deliver()"
+
+	log = log.With(slog.String("op", op))
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(cfg.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deliverEvents(ctx, log.With(slog.Int("worker", i)), deliverer, sending, cfg)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliverEvents sends the queued deliveries one by one, and polls the queue
+// once every cfg.PollInterval while it is empty.
+func deliverEvents(ctx context.Context, log *slog.Logger, deliverer DelivererImp, sending WebhookSendingImp, cfg config.Webhooks) {
+	for ctx.Err() == nil {
+		delivery, err := deliverer.ClaimDelivery(cfg.Lease)
+		if err == nil {
+			deliverEvent(ctx, log, deliverer, sending, cfg, delivery)
+			continue
+		}
+		if !errors.Is(err, storage.ErrDeliveryNotFound) {
+			log.Error("failed to claim delivery", slerr.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(cfg.PollInterval):
+		}
+	}
+}
+
+func deliverEvent(ctx context.Context, log *slog.Logger, deliverer DelivererImp, sending WebhookSendingImp, cfg config.Webhooks, delivery *model.WebhookDelivery) {
+	log = log.With(slog.Int64("delivery", delivery.ID), slog.Int64("webhook", delivery.WebhookID),
+		slog.Int("attempt", delivery.Attempts))
+
+	// The attempt must end before the lease does, or another worker takes the delivery.
+	sendCtx, cancel := context.WithTimeout(ctx, min(cfg.Timeout, cfg.Lease))
+	defer cancel()
+
+	started := time.Now()
+	statusCode, err := sending.Send(sendCtx, delivery)
+	attempt := &model.DeliveryAttempt{
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	switch {
+	case err == nil:
+		err = deliverer.CompleteDelivery(delivery, attempt)
+		log.Info("event delivered", slog.Int("status", statusCode))
+	case ctx.Err() != nil:
+		// The service is stopping; the delivery is sent again once its lease passes.
+		log.Info("event delivery interrupted", slerr.Err(err))
+		return
+	case delivery.Attempts >= cfg.MaxAttempts:
+		log.Error("event delivery failed", slog.Int("status", statusCode), slerr.Err(err))
+		err = deliverer.FailDelivery(delivery, attempt)
+	default:
+		runAt := time.Now().Add(retryDelay(cfg.RetryBaseDelay, cfg.RetryMaxDelay, delivery.Attempts))
+		log.Info("event delivery will be retried", slog.Int("status", statusCode), slog.Time("runAt", runAt),
+			slerr.Err(err))
+		err = deliverer.RetryDelivery(delivery, attempt, runAt)
+	}
+	if err != nil {
+		log.Error("failed to update delivery", slerr.Err(err))
+	}
+}
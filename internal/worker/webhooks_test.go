@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nabishec/restapi/internal/config"
+	"github.com/nabishec/restapi/internal/model"
+	"github.com/nabishec/restapi/internal/storage"
+	"github.com/nabishec/restapi/internal/storage/memory"
+)
+
+// sender answers the deliveries with the status and err.
+type sender struct {
+	status int
+	err    error
+}
+
+func (s *sender) Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	return s.status, s.err
+}
+
+var webhookConfig = config.Webhooks{
+	Timeout:        time.Second,
+	MaxAttempts:    2,
+	RetryBaseDelay: time.Minute,
+	RetryMaxDelay:  time.Hour,
+	Lease:          time.Minute,
+}
+
+// queuedDelivery adds a webhook and an event it is subscribed to, which
+// queues a delivery, to a new storage.
+func queuedDelivery(t *testing.T) (*memory.Storage, int64) {
+	t.Helper()
+
+	songStorage := memory.NewStorage()
+	hook := &model.Webhook{URL: "https://example.com/hook", Events: []string{model.EventSongCreated}}
+	if err := songStorage.AddWebhook(hook); err != nil {
+		t.Fatal(err)
+	}
+	err := songStorage.AddEvent(&model.Event{Type: model.EventSongCreated, Song: &model.Song{SongName: "Song1", GroupName: "Group1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return songStorage, hook.ID
+}
+
+// deliverOnce claims the delivery, sends it once and returns its state.
+func deliverOnce(t *testing.T, songStorage *memory.Storage, webhookId int64, sending WebhookSendingImp) *model.WebhookDelivery {
+	t.Helper()
+
+	delivery, err := songStorage.ClaimDelivery(webhookConfig.Lease)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliverEvent(context.Background(), discard, songStorage, sending, webhookConfig, delivery)
+
+	deliveries, err := songStorage.GetWebhookDeliveries(webhookId, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deliveries[0]
+}
+
+func TestDeliverEvent(t *testing.T) {
+	songStorage, webhookId := queuedDelivery(t)
+	failing := &sender{status: 503, err: errors.New("webhook answered 503")}
+
+	delivery := deliverOnce(t, songStorage, webhookId, failing)
+	if delivery.State != model.JobQueued || delivery.Attempts != 1 || delivery.LastError == "" {
+		t.Fatalf("delivery %s after %d attempts (%q), want queued again after 1", delivery.State, delivery.Attempts, delivery.LastError)
+	}
+	if wait := time.Until(delivery.RunAt); wait < webhookConfig.RetryBaseDelay/2-time.Second {
+		t.Errorf("retry in %s, want at least %s", wait, webhookConfig.RetryBaseDelay/2)
+	}
+	if len(delivery.Log) != 1 || delivery.Log[0].StatusCode != 503 {
+		t.Errorf("attempts logged: %+v", delivery.Log)
+	}
+
+	// The retry waits for its backoff, and only the attempt holding the
+	// delivery may finish it.
+	if _, err := songStorage.ClaimDelivery(webhookConfig.Lease); !errors.Is(err, storage.ErrDeliveryNotFound) {
+		t.Fatalf("retried delivery claimed before its time: %v", err)
+	}
+	if err := songStorage.RetryDelivery(delivery, &model.DeliveryAttempt{}, time.Now()); !errors.Is(err, storage.ErrDeliveryNotFound) {
+		t.Errorf("delivery retried without being claimed: %v", err)
+	}
+}
+
+func TestDeliverEventMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name    string
+		sending *sender
+		state   string
+	}{
+		{name: "succeeded", sending: &sender{status: 200}, state: model.JobSucceeded},
+		{name: "failed again", sending: &sender{status: 500, err: errors.New("webhook answered 500")}, state: model.JobFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := webhookConfig
+			cfg.RetryBaseDelay, cfg.RetryMaxDelay = 0, 0
+			songStorage, webhookId := queuedDelivery(t)
+
+			delivery, err := songStorage.ClaimDelivery(cfg.Lease)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deliverEvent(context.Background(), discard, songStorage, &sender{status: 500, err: errors.New("webhook answered 500")}, cfg, delivery)
+
+			// The last of cfg.MaxAttempts ends the delivery either way.
+			delivery = deliverOnce(t, songStorage, webhookId, tt.sending)
+			if delivery.State != tt.state || delivery.Attempts != cfg.MaxAttempts || len(delivery.Log) != cfg.MaxAttempts {
+				t.Fatalf("delivery %s after %d attempts, %d logged, want %s after %d", delivery.State, delivery.Attempts,
+					len(delivery.Log), tt.state, cfg.MaxAttempts)
+			}
+			if delivery.FinishedAt == nil {
+				t.Error("finished delivery has no finish time")
+			}
+		})
+	}
+}